@@ -0,0 +1,103 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package v1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// OperationLogServiceClient is the client API for OperationLogService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type OperationLogServiceClient interface {
+	// ListOperationLogs lists the operation logs.
+	ListOperationLogs(ctx context.Context, in *ListOperationLogsRequest, opts ...grpc.CallOption) (*ListOperationLogsResponse, error)
+}
+
+type operationLogServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewOperationLogServiceClient(cc grpc.ClientConnInterface) OperationLogServiceClient {
+	return &operationLogServiceClient{cc}
+}
+
+func (c *operationLogServiceClient) ListOperationLogs(ctx context.Context, in *ListOperationLogsRequest, opts ...grpc.CallOption) (*ListOperationLogsResponse, error) {
+	out := new(ListOperationLogsResponse)
+	err := c.cc.Invoke(ctx, "/gobackend.apiserver.v1.OperationLogService/ListOperationLogs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// OperationLogServiceServer is the server API for OperationLogService service.
+// All implementations must embed UnimplementedOperationLogServiceServer
+// for forward compatibility
+type OperationLogServiceServer interface {
+	// ListOperationLogs lists the operation logs.
+	ListOperationLogs(context.Context, *ListOperationLogsRequest) (*ListOperationLogsResponse, error)
+	mustEmbedUnimplementedOperationLogServiceServer()
+}
+
+// UnimplementedOperationLogServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedOperationLogServiceServer struct {
+}
+
+func (UnimplementedOperationLogServiceServer) ListOperationLogs(context.Context, *ListOperationLogsRequest) (*ListOperationLogsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListOperationLogs not implemented")
+}
+func (UnimplementedOperationLogServiceServer) mustEmbedUnimplementedOperationLogServiceServer() {}
+
+// UnsafeOperationLogServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to OperationLogServiceServer will
+// result in compilation errors.
+type UnsafeOperationLogServiceServer interface {
+	mustEmbedUnimplementedOperationLogServiceServer()
+}
+
+func RegisterOperationLogServiceServer(s grpc.ServiceRegistrar, srv OperationLogServiceServer) {
+	s.RegisterService(&OperationLogService_ServiceDesc, srv)
+}
+
+func _OperationLogService_ListOperationLogs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListOperationLogsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OperationLogServiceServer).ListOperationLogs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gobackend.apiserver.v1.OperationLogService/ListOperationLogs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OperationLogServiceServer).ListOperationLogs(ctx, req.(*ListOperationLogsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// OperationLogService_ServiceDesc is the grpc.ServiceDesc for OperationLogService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var OperationLogService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "gobackend.apiserver.v1.OperationLogService",
+	HandlerType: (*OperationLogServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListOperationLogs",
+			Handler:    _OperationLogService_ListOperationLogs_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "apiserver/v1/operation_log.proto",
+}
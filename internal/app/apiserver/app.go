@@ -13,6 +13,7 @@ Go Web Backend Demo.`
 // NewApp creates an App object with default parameters.
 func NewApp(binaryName string) *app.App {
 	opts := options.New()
+	reloader := &reloader{}
 
 	application := app.New("APIServer",
 		binaryName,
@@ -21,13 +22,16 @@ func NewApp(binaryName string) *app.App {
 		app.WithProcessLock("/tmp"),
 		app.WithDefaultValidArgs(),
 		app.WithRunModeEnv("RUN_MODE"),
-		app.WithRunFunc(run(opts)),
+		app.WithRunFunc(run(opts, reloader)),
+		app.WithReload(func() app.CliOptions { return options.New() }, reloader.reload),
 	)
 
+	application.AddCommand(newMigrateCommand())
+
 	return application
 }
 
-func run(opts *options.Options) app.RunFunc {
+func run(opts *options.Options, reloader *reloader) app.RunFunc {
 	return func(binaryName string) error {
 		cfg := config.New(opts)
 
@@ -36,6 +40,9 @@ func run(opts *options.Options) app.RunFunc {
 			return err
 		}
 
-		return server.PrepareRun().Run()
+		prepared := server.PrepareRun()
+		reloader.set(server)
+
+		return prepared.Run()
 	}
 }
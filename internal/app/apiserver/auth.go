@@ -0,0 +1,283 @@
+package apiserver
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"strings"
+	"time"
+
+	jwt "github.com/appleboy/gin-jwt/v2"
+	jwtv3 "github.com/dgrijalva/jwt-go"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/metadata"
+
+	"gobackend/pkg/core"
+	"gobackend/pkg/errors"
+	"gobackend/pkg/log"
+	metav1 "gobackend/pkg/meta/v1"
+
+	"gobackend/internal/app/apiserver/secretcache"
+	"gobackend/internal/app/apiserver/store"
+	"gobackend/internal/pkg/code"
+	v1 "gobackend/internal/pkg/entity/apiserver/v1"
+	"gobackend/internal/pkg/middleware"
+	"gobackend/internal/pkg/middleware/auth"
+	genericoptions "gobackend/internal/pkg/options"
+	genericserver "gobackend/internal/pkg/server"
+)
+
+const (
+	// APIServerAudience defines the value of jwt audience field.
+	APIServerAudience = "gobackend.apiserver"
+
+	// APIServerIssuer defines the value of jwt issuer field.
+	APIServerIssuer = "gobackend-apiserver"
+)
+
+type loginInfo struct {
+	Username string `form:"username" json:"username" binding:"required,username"`
+	Password string `form:"password" json:"password" binding:"required"`
+}
+
+// newAuthStrategy returns the authentication strategy selected by auth.strategy.
+func newAuthStrategy(
+	opts *genericoptions.AuthOptions,
+	storeIns store.Factory,
+	jwtStrategy auth.JWTStrategy,
+	secrets *secretcache.Cache,
+) auth.Strategy {
+	switch opts.Strategy {
+	case genericoptions.AuthStrategyBasic:
+		return newBasicAuth(storeIns)
+	case genericoptions.AuthStrategyJWT:
+		return jwtStrategy
+	case genericoptions.AuthStrategyCache:
+		return newCacheAuth(secrets)
+	default:
+		return auth.NewAutoStrategy(newBasicAuth(storeIns), jwtStrategy).WithCache(newCacheAuth(secrets))
+	}
+}
+
+// newGRPCAuth authenticates the gRPC calls with the strategy, the `authorization` metadata
+// is verified as the Authorization header.
+func newGRPCAuth(strategy auth.Strategy) genericserver.GRPCAuthFunc {
+	return func(ctx context.Context) (string, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+
+		values := md.Get("authorization")
+		if len(values) == 0 {
+			return "", errors.WithCode(code.ErrMissingHeader, "The `authorization` metadata was empty.")
+		}
+
+		return strategy.Verify(ctx, values[0])
+	}
+}
+
+func newCacheAuth(secrets *secretcache.Cache) auth.CacheStrategy {
+	return auth.NewCacheStrategy(secrets.Get)
+}
+
+func newBasicAuth(storeIns store.Factory) auth.BasicStrategy {
+	return auth.NewBasicStrategy(func(ctx context.Context, username string, password string) bool {
+		user, err := storeIns.Users().Get(ctx, username, metav1.GetOptions{})
+		if err != nil {
+			return false
+		}
+
+		if err := user.Compare(password); err != nil {
+			return false
+		}
+
+		return true
+	})
+}
+
+func newJWTAuth(opts *genericoptions.AuthOptions, storeIns store.Factory) (auth.JWTStrategy, error) {
+	ginjwt, err := jwt.New(&jwt.GinJWTMiddleware{
+		Realm:            opts.Realm,
+		SigningAlgorithm: "HS256",
+		Key:              []byte(opts.Key),
+		Timeout:          opts.Timeout,
+		MaxRefresh:       opts.MaxRefresh,
+		Authenticator:    authenticator(storeIns),
+		LoginResponse:    tokenResponse(),
+		RefreshResponse:  tokenResponse(),
+		LogoutResponse: func(c *gin.Context, code int) {
+			core.WriteResponse(c, nil, nil)
+		},
+		PayloadFunc: payloadFunc(),
+		IdentityHandler: func(c *gin.Context) interface{} {
+			claims := jwt.ExtractClaims(c)
+
+			return claims[jwt.IdentityKey]
+		},
+		IdentityKey: middleware.UsernameKey,
+		Authorizator: func(data interface{}, c *gin.Context) bool {
+			if v, ok := data.(string); ok {
+				log.C(c).Infof("user `%s` is authenticated.", v)
+
+				return true
+			}
+
+			return false
+		},
+		HTTPStatusMessageFunc: func(e error, c *gin.Context) string {
+			_ = c.Error(e)
+
+			return e.Error()
+		},
+		Unauthorized: func(c *gin.Context, status int, message string) {
+			core.WriteResponse(c, errors.WithCode(jwtErrorCode(c, status), message), nil)
+		},
+		// The tokens are not looked up in the cookies, which browsers send along with the cross site
+		// requests, so that the API is not exposed to CSRF.
+		TokenLookup:   "header: Authorization, query: token",
+		TokenHeadName: "Bearer",
+		TimeFunc:      time.Now,
+	})
+	if err != nil {
+		return auth.JWTStrategy{}, errors.Wrap(err, "create jwt middleware failed")
+	}
+
+	return auth.NewJWTStrategy(*ginjwt), nil
+}
+
+func authenticator(storeIns store.Factory) func(c *gin.Context) (interface{}, error) {
+	return func(c *gin.Context) (interface{}, error) {
+		var (
+			login loginInfo
+			err   error
+		)
+
+		// support header and body both
+		if c.Request.Header.Get("Authorization") != "" {
+			login, err = parseWithHeader(c)
+		} else {
+			login, err = parseWithBody(c)
+		}
+
+		if err != nil {
+			return "", jwt.ErrMissingLoginValues
+		}
+
+		// Get the user information by the login username.
+		user, err := storeIns.Users().Get(middleware.RequestContext(c), login.Username, metav1.GetOptions{})
+		if err != nil {
+			log.C(c).Errorf("get user information failed: %s", err.Error())
+
+			return "", jwt.ErrFailedAuthentication
+		}
+
+		// Compare the login password with the user password.
+		if err := user.Compare(login.Password); err != nil {
+			return "", jwt.ErrFailedAuthentication
+		}
+
+		return user, nil
+	}
+}
+
+func parseWithHeader(c *gin.Context) (loginInfo, error) {
+	authHeader := strings.SplitN(c.Request.Header.Get("Authorization"), " ", 2)
+	if len(authHeader) != 2 || authHeader[0] != "Basic" {
+		log.C(c).Errorf("get basic string from Authorization header failed")
+
+		return loginInfo{}, jwt.ErrFailedAuthentication
+	}
+
+	payload, err := base64.StdEncoding.DecodeString(authHeader[1])
+	if err != nil {
+		log.C(c).Errorf("decode basic string: %s", err.Error())
+
+		return loginInfo{}, jwt.ErrFailedAuthentication
+	}
+
+	pair := strings.SplitN(string(payload), ":", 2)
+	if len(pair) != 2 {
+		log.C(c).Errorf("parse payload failed")
+
+		return loginInfo{}, jwt.ErrFailedAuthentication
+	}
+
+	return loginInfo{
+		Username: pair[0],
+		Password: pair[1],
+	}, nil
+}
+
+func parseWithBody(c *gin.Context) (loginInfo, error) {
+	var login loginInfo
+	if err := c.ShouldBindJSON(&login); err != nil {
+		log.C(c).Errorf("parse login parameters: %s", err.Error())
+
+		return loginInfo{}, jwt.ErrFailedAuthentication
+	}
+
+	return login, nil
+}
+
+func tokenResponse() func(c *gin.Context, code int, token string, expire time.Time) {
+	return func(c *gin.Context, code int, token string, expire time.Time) {
+		core.WriteResponse(c, nil, map[string]string{
+			"token":  token,
+			"expire": expire.Format(time.RFC3339),
+		})
+	}
+}
+
+func payloadFunc() func(data interface{}) jwt.MapClaims {
+	return func(data interface{}) jwt.MapClaims {
+		claims := jwt.MapClaims{
+			"iss": APIServerIssuer,
+			"aud": APIServerAudience,
+		}
+
+		if u, ok := data.(*v1.User); ok {
+			claims[jwt.IdentityKey] = u.Name
+			claims["sub"] = u.Name
+		}
+
+		return claims
+	}
+}
+
+// jwtErrorCode maps the error recorded by HTTPStatusMessageFunc to an error code.
+func jwtErrorCode(c *gin.Context, status int) int {
+	if status == http.StatusForbidden {
+		return code.ErrPermissionDenied
+	}
+
+	var err error
+	if last := c.Errors.Last(); last != nil {
+		err = last.Err
+	}
+
+	// The expired tokens are rejected by the jwt parser before the middleware checks the expiration.
+	var ve *jwtv3.ValidationError
+	if errors.As(err, &ve) && ve.Errors&jwtv3.ValidationErrorExpired != 0 {
+		return code.ErrExpired
+	}
+
+	switch {
+	case errors.Is(err, jwt.ErrMissingLoginValues):
+		return code.ErrBind
+	case errors.Is(err, jwt.ErrFailedAuthentication):
+		return code.ErrPasswordIncorrect
+	case errors.Is(err, jwt.ErrExpiredToken):
+		return code.ErrExpired
+	case errors.Is(err, jwt.ErrEmptyAuthHeader):
+		return code.ErrMissingHeader
+	case errors.Is(err, jwt.ErrInvalidAuthHeader):
+		return code.ErrInvalidAuthHeader
+	case errors.Is(err, jwt.ErrEmptyQueryToken):
+		// The token is looked up in the query after the header, the error of the header is lost.
+		if c.GetHeader("Authorization") != "" {
+			return code.ErrInvalidAuthHeader
+		}
+
+		return code.ErrMissingHeader
+	default:
+		return code.ErrTokenInvalid
+	}
+}
@@ -0,0 +1,213 @@
+package apiserver
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"gobackend/pkg/core"
+	metav1 "gobackend/pkg/meta/v1"
+
+	"gobackend/internal/app/apiserver/store/fake"
+	"gobackend/internal/pkg/code"
+	v1 "gobackend/internal/pkg/entity/apiserver/v1"
+	"gobackend/internal/pkg/middleware"
+	genericoptions "gobackend/internal/pkg/options"
+)
+
+// newAuthServer serves the jwt login and refresh handlers and a protected /me route,
+// the tokens are issued at the time returned by the clock.
+func newAuthServer(t *testing.T, clock *time.Time) *gin.Engine {
+	t.Helper()
+
+	storeIns := fake.New()
+
+	user := &v1.User{Nickname: "alice", Password: "Passw0rd!", Email: "alice@example.com"}
+	user.Name = "alice"
+
+	if err := storeIns.Users().Create(context.Background(), user, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	opts := genericoptions.NewAuthOptions()
+	opts.Key = "test-key"
+	opts.MaxRefresh = 24 * time.Hour
+
+	strategy, err := newJWTAuth(opts, storeIns)
+	if err != nil {
+		t.Fatalf("newJWTAuth() error = %v", err)
+	}
+
+	strategy.TimeFunc = func() time.Time { return *clock }
+
+	gin.SetMode(gin.TestMode)
+	g := gin.New()
+	g.POST("/login", strategy.LoginHandler)
+	g.POST("/refresh", strategy.RefreshHandler)
+	g.GET("/me", strategy.AuthFunc(), func(c *gin.Context) {
+		core.WriteResponse(c, nil, map[string]string{"username": c.GetString(middleware.UsernameKey)})
+	})
+
+	return g
+}
+
+func serveAuth(g *gin.Engine, method, path, header, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	if header != "" {
+		req.Header.Set("Authorization", header)
+	}
+
+	w := httptest.NewRecorder()
+	g.ServeHTTP(w, req)
+
+	return w
+}
+
+func login(t *testing.T, g *gin.Engine) string {
+	t.Helper()
+
+	w := serveAuth(g, http.MethodPost, "/login", "", `{"username":"alice","password":"Passw0rd!"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("login status = %d, body = %s", w.Code, w.Body)
+	}
+
+	var resp map[string]string
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil || resp["token"] == "" {
+		t.Fatalf("login body = %s, error = %v", w.Body, err)
+	}
+
+	return resp["token"]
+}
+
+func errorCode(t *testing.T, w *httptest.ResponseRecorder) int {
+	t.Helper()
+
+	var resp core.ErrResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("unmarshal %s: %v", w.Body, err)
+	}
+
+	return resp.Code
+}
+
+func TestLogin(t *testing.T) {
+	basic := func(username, password string) string {
+		return "Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+password))
+	}
+
+	tests := []struct {
+		name   string
+		header string
+		body   string
+		status int
+		code   int
+	}{
+		{"body", "", `{"username":"alice","password":"Passw0rd!"}`, http.StatusOK, 0},
+		{"basic header", basic("alice", "Passw0rd!"), "", http.StatusOK, 0},
+		{"bad password", "", `{"username":"alice","password":"wrong"}`, http.StatusUnauthorized, code.ErrPasswordIncorrect},
+		{"bad basic password", basic("alice", "wrong"), "", http.StatusUnauthorized, code.ErrPasswordIncorrect},
+		{"unknown user", "", `{"username":"bob","password":"Passw0rd!"}`, http.StatusUnauthorized, code.ErrPasswordIncorrect},
+		{"missing password", "", `{"username":"alice"}`, http.StatusBadRequest, code.ErrBind},
+		{"bad header", "Bearer token", "", http.StatusBadRequest, code.ErrBind},
+	}
+
+	now := time.Now()
+	g := newAuthServer(t, &now)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serveAuth(g, http.MethodPost, "/login", tt.header, tt.body)
+			if w.Code != tt.status {
+				t.Fatalf("status = %d, want %d, body = %s", w.Code, tt.status, w.Body)
+			}
+
+			if tt.code != 0 {
+				if got := errorCode(t, w); got != tt.code {
+					t.Errorf("code = %d, want %d", got, tt.code)
+				}
+			}
+		})
+	}
+}
+
+func TestTokenVerify(t *testing.T) {
+	now := time.Now()
+	g := newAuthServer(t, &now)
+	token := login(t, g)
+
+	w := serveAuth(g, http.MethodGet, "/me", "Bearer "+token, "")
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"username":"alice"`) {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body)
+	}
+
+	tests := []struct {
+		name   string
+		header string
+		code   int
+	}{
+		{"missing header", "", code.ErrMissingHeader},
+		{"invalid header", "Token " + token, code.ErrInvalidAuthHeader},
+		{"invalid token", "Bearer " + token + "x", code.ErrTokenInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serveAuth(g, http.MethodGet, "/me", tt.header, "")
+			if w.Code != http.StatusUnauthorized {
+				t.Fatalf("status = %d, want 401, body = %s", w.Code, w.Body)
+			}
+
+			if got := errorCode(t, w); got != tt.code {
+				t.Errorf("code = %d, want %d", got, tt.code)
+			}
+		})
+	}
+}
+
+func TestTokenExpiredAndRefresh(t *testing.T) {
+	// The token is issued two hours ago and expired an hour ago.
+	clock := time.Now().Add(-2 * time.Hour)
+	g := newAuthServer(t, &clock)
+	expired := login(t, g)
+	clock = time.Now()
+
+	w := serveAuth(g, http.MethodGet, "/me", "Bearer "+expired, "")
+	if w.Code != http.StatusUnauthorized || errorCode(t, w) != code.ErrExpired {
+		t.Fatalf("expired token status = %d, body = %s, want ErrExpired", w.Code, w.Body)
+	}
+
+	// The expired token is refreshed within the max refresh duration.
+	w = serveAuth(g, http.MethodPost, "/refresh", "Bearer "+expired, "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("refresh status = %d, body = %s", w.Code, w.Body)
+	}
+
+	var resp map[string]string
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("unmarshal %s: %v", w.Body, err)
+	}
+
+	w = serveAuth(g, http.MethodGet, "/me", "Bearer "+resp["token"], "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("refreshed token status = %d, body = %s", w.Code, w.Body)
+	}
+
+	// The token issued before the max refresh duration can not be refreshed.
+	clock = time.Now().Add(-48 * time.Hour)
+	stale := login(t, g)
+	clock = time.Now()
+
+	w = serveAuth(g, http.MethodPost, "/refresh", "Bearer "+stale, "")
+	if w.Code != http.StatusUnauthorized || errorCode(t, w) != code.ErrExpired {
+		t.Fatalf("stale refresh status = %d, body = %s, want ErrExpired", w.Code, w.Body)
+	}
+}
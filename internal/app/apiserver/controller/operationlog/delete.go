@@ -4,15 +4,26 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"gobackend/pkg/core"
+	"gobackend/pkg/errors"
 	metav1 "gobackend/pkg/meta/v1"
+
+	"gobackend/internal/pkg/code"
+	"gobackend/internal/pkg/middleware"
 )
 
 // Delete deletes an operation log record.
 func (o *Controller) Delete(c *gin.Context) {
+	var opts metav1.DeleteOptions
+	if err := c.ShouldBindQuery(&opts); err != nil {
+		core.WriteResponse(c, errors.WithCode(code.ErrBind, err.Error()), nil)
+
+		return
+	}
+
 	if err := o.store.OperationLogs().Delete(
-		c,
+		middleware.RequestContext(c),
 		c.Param("id"),
-		metav1.DeleteOptions{Unscoped: true},
+		metav1.DeleteOptions{Unscoped: true, DryRun: opts.DryRun},
 	); err != nil {
 		core.WriteResponse(c, err, nil)
 
@@ -0,0 +1,110 @@
+package operationlog
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"gobackend/pkg/core"
+	"gobackend/pkg/errors"
+	"gobackend/pkg/log"
+	metav1 "gobackend/pkg/meta/v1"
+
+	"gobackend/internal/app/apiserver/oplog"
+	"gobackend/internal/app/apiserver/store"
+	"gobackend/internal/pkg/code"
+	"gobackend/internal/pkg/entity/apiserver/operationlog"
+	"gobackend/internal/pkg/middleware"
+)
+
+// ExportOptions is the options of exporting operation logs.
+type ExportOptions struct {
+	metav1.ListOptions
+
+	// Format is the export format, csv or jsonl, defaults to jsonl.
+	Format string `form:"format"`
+}
+
+// Export streams the operation logs matching the field selector as CSV or JSON lines.
+func (o *Controller) Export(c *gin.Context) {
+	var r ExportOptions
+
+	if err := c.ShouldBindQuery(&r); err != nil {
+		core.WriteResponse(c, errors.WithCode(code.ErrBind, err.Error()), nil)
+
+		return
+	}
+
+	if _, err := store.OperationLogFields.Requirements(r.FieldSelector); err != nil {
+		core.WriteResponse(c, err, nil)
+
+		return
+	}
+
+	exporter, err := oplog.NewExporter(r.Format, r.Fields, c.Writer)
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+
+		return
+	}
+
+	// The response is committed when the first page is written, the errors after it
+	// can only be logged.
+	started := false
+	start := func() {
+		if started {
+			return
+		}
+
+		started = true
+
+		format := r.Format
+		if format == "" {
+			format = oplog.FormatJSONL
+		}
+
+		c.Header("Content-Type", exporter.ContentType())
+		c.Header("Content-Disposition", fmt.Sprintf(
+			`attachment; filename="operation-logs-%s.%s"`,
+			time.Now().UTC().Format("20060102T150405Z"),
+			format,
+		))
+		c.Status(http.StatusOK)
+	}
+
+	err = oplog.Scan(middleware.RequestContext(c), o.store, r.ListOptions, func(items []*operationlog.OperationLog) error {
+		start()
+
+		if err := exporter.Write(items); err != nil {
+			return err
+		}
+
+		if err := exporter.Flush(); err != nil {
+			return err
+		}
+
+		c.Writer.Flush()
+
+		return nil
+	})
+	if err != nil && !started {
+		core.WriteResponse(c, err, nil)
+
+		return
+	}
+
+	if err != nil {
+		log.C(c).Errorf("export operation logs error: %s", err)
+		_ = c.Error(err)
+
+		return
+	}
+
+	start()
+
+	if err := exporter.Flush(); err != nil {
+		log.C(c).Errorf("export operation logs error: %s", err)
+	}
+}
@@ -5,10 +5,11 @@ import (
 
 	"gobackend/pkg/core"
 	"gobackend/pkg/errors"
-	"gobackend/pkg/fields"
 	metav1 "gobackend/pkg/meta/v1"
 
+	"gobackend/internal/app/apiserver/store"
 	"gobackend/internal/pkg/code"
+	"gobackend/internal/pkg/middleware"
 )
 
 // List operation logs.
@@ -21,13 +22,13 @@ func (o *Controller) List(c *gin.Context) {
 		return
 	}
 
-	if _, err := fields.ParseSelector(r.FieldSelector); err != nil {
-		core.WriteResponse(c, errors.WithCode(code.ErrFieldSelectorValidation, ""), nil)
+	if _, err := store.OperationLogFields.Requirements(r.FieldSelector); err != nil {
+		core.WriteResponse(c, err, nil)
 
 		return
 	}
 
-	operationLogs, err := o.store.OperationLogs().List(c, r)
+	operationLogs, err := o.store.OperationLogs().List(middleware.RequestContext(c), r)
 
 	if err != nil {
 		core.WriteResponse(c, err, nil)
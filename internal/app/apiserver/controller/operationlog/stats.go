@@ -0,0 +1,49 @@
+package operationlog
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"gobackend/pkg/core"
+	"gobackend/pkg/errors"
+	metav1 "gobackend/pkg/meta/v1"
+
+	"gobackend/internal/app/apiserver/oplog"
+	"gobackend/internal/app/apiserver/store"
+	"gobackend/internal/pkg/code"
+	"gobackend/internal/pkg/middleware"
+)
+
+// StatsOptions is the options of the statistics of operation logs.
+type StatsOptions struct {
+	metav1.ListOptions
+
+	// GroupBy is the comma separated dimensions to group by, e.g. `username,day`.
+	GroupBy string `form:"group_by"`
+}
+
+// Stats returns the statistics of the operation logs matching the field selector,
+// grouped by the dimensions.
+func (o *Controller) Stats(c *gin.Context) {
+	var r StatsOptions
+
+	if err := c.ShouldBindQuery(&r); err != nil {
+		core.WriteResponse(c, errors.WithCode(code.ErrBind, err.Error()), nil)
+
+		return
+	}
+
+	if _, err := store.OperationLogFields.Requirements(r.FieldSelector); err != nil {
+		core.WriteResponse(c, err, nil)
+
+		return
+	}
+
+	stats, err := oplog.Stats(middleware.RequestContext(c), o.store, r.ListOptions, r.GroupBy)
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+
+		return
+	}
+
+	core.WriteResponse(c, nil, stats)
+}
@@ -0,0 +1,43 @@
+package policy
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"gobackend/pkg/core"
+	"gobackend/pkg/errors"
+	"gobackend/pkg/log"
+
+	"gobackend/internal/pkg/code"
+	v1 "gobackend/internal/pkg/entity/apiserver/v1"
+	"gobackend/internal/pkg/middleware"
+)
+
+// Authorize decides whether the subject can perform the action on the resource.
+func (p *Controller) Authorize(c *gin.Context) {
+	log.C(c).Debug("authorize function called")
+
+	var r v1.AuthzRequest
+
+	if err := c.ShouldBindJSON(&r); err != nil {
+		core.WriteResponse(c, errors.WithCode(code.ErrBind, err.Error()), nil)
+
+		return
+	}
+
+	if errs := r.Validate(); len(errs) != 0 {
+		core.WriteResponse(c, errors.WithCode(code.ErrValidation, errs.ToAggregate().Error()), nil)
+
+		return
+	}
+
+	ctx := middleware.RequestContext(c)
+
+	resp, err := p.srv.Policies().Authorize(ctx, &r)
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+
+		return
+	}
+
+	core.WriteResponse(c, nil, resp)
+}
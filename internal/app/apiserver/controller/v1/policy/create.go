@@ -0,0 +1,53 @@
+package policy
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"gobackend/pkg/core"
+	"gobackend/pkg/errors"
+	"gobackend/pkg/log"
+	metav1 "gobackend/pkg/meta/v1"
+
+	"gobackend/internal/pkg/code"
+	v1 "gobackend/internal/pkg/entity/apiserver/v1"
+	"gobackend/internal/pkg/middleware"
+)
+
+// Create add new policy to the storage.
+func (p *Controller) Create(c *gin.Context) {
+	log.C(c).Debug("policy create function called")
+
+	var opts metav1.CreateOptions
+	if err := c.ShouldBindQuery(&opts); err != nil {
+		core.WriteResponse(c, errors.WithCode(code.ErrBind, err.Error()), nil)
+
+		return
+	}
+
+	var r v1.Policy
+
+	if err := c.ShouldBindJSON(&r); err != nil {
+		core.WriteResponse(c, errors.WithCode(code.ErrBind, err.Error()), nil)
+
+		return
+	}
+
+	if errs := r.Validate(); len(errs) != 0 {
+		core.WriteResponse(c, errors.WithCode(code.ErrValidation, errs.ToAggregate().Error()), nil)
+
+		return
+	}
+
+	// The policy is always owned by the requester.
+	r.Username = c.GetString(middleware.UsernameKey)
+
+	ctx := middleware.RequestContext(c)
+
+	if err := p.srv.Policies().Create(ctx, &r, opts); err != nil {
+		core.WriteResponse(c, err, nil)
+
+		return
+	}
+
+	core.WriteResponse(c, nil, r)
+}
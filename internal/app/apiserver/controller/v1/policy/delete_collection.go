@@ -0,0 +1,40 @@
+package policy
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"gobackend/pkg/core"
+	"gobackend/pkg/errors"
+	"gobackend/pkg/log"
+	metav1 "gobackend/pkg/meta/v1"
+
+	"gobackend/internal/pkg/code"
+	"gobackend/internal/pkg/middleware"
+)
+
+// DeleteCollection batch delete policies by multiple policy names.
+func (p *Controller) DeleteCollection(c *gin.Context) {
+	log.C(c).Info("batch delete policy function called.")
+
+	var opts metav1.DeleteOptions
+	if err := c.ShouldBindQuery(&opts); err != nil {
+		core.WriteResponse(c, errors.WithCode(code.ErrBind, err.Error()), nil)
+
+		return
+	}
+
+	ctx := middleware.RequestContext(c)
+
+	if err := p.srv.Policies().DeleteCollection(
+		ctx,
+		c.GetString(middleware.UsernameKey),
+		c.QueryArray("name"),
+		metav1.DeleteOptions{Unscoped: true, DryRun: opts.DryRun},
+	); err != nil {
+		core.WriteResponse(c, err, nil)
+
+		return
+	}
+
+	core.WriteResponse(c, nil, nil)
+}
@@ -0,0 +1,54 @@
+package policy
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"gobackend/pkg/core"
+	"gobackend/pkg/errors"
+	"gobackend/pkg/log"
+	metav1 "gobackend/pkg/meta/v1"
+
+	"gobackend/internal/pkg/code"
+	"gobackend/internal/pkg/middleware"
+)
+
+// Get get a policy by the policy identifier.
+func (p *Controller) Get(c *gin.Context) {
+	log.C(c).Debug("get policy function called")
+
+	ctx := middleware.RequestContext(c)
+
+	policy, err := p.srv.Policies().Get(
+		ctx,
+		c.GetString(middleware.UsernameKey),
+		c.Param("name"),
+		metav1.GetOptions{},
+	)
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+
+		return
+	}
+
+	if !core.IfMatch(c, policy.ResourceVersion) {
+		core.WriteResponse(
+			c,
+			errors.WithCode(code.ErrResourceConflict, "the current resource version is %d", policy.ResourceVersion),
+			nil,
+		)
+
+		return
+	}
+
+	core.SetETag(c, policy.ResourceVersion)
+
+	if !core.IfNoneMatch(c, policy.ResourceVersion) {
+		c.Status(http.StatusNotModified)
+
+		return
+	}
+
+	core.WriteResponse(c, nil, policy)
+}
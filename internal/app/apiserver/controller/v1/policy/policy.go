@@ -0,0 +1,18 @@
+package policy
+
+import (
+	srvv1 "gobackend/internal/app/apiserver/service/v1"
+	"gobackend/internal/app/apiserver/store"
+)
+
+// Controller create a policy handler used to handle request for policy resource.
+type Controller struct {
+	srv srvv1.Service
+}
+
+// NewController creates a policy handler.
+func NewController(store store.Factory) *Controller {
+	return &Controller{
+		srv: srvv1.NewService(store),
+	}
+}
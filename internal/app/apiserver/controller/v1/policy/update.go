@@ -0,0 +1,81 @@
+package policy
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"gobackend/pkg/core"
+	"gobackend/pkg/errors"
+	"gobackend/pkg/log"
+	metav1 "gobackend/pkg/meta/v1"
+
+	"gobackend/internal/pkg/code"
+	v1 "gobackend/internal/pkg/entity/apiserver/v1"
+	"gobackend/internal/pkg/middleware"
+)
+
+// Update update a policy by the policy identifier.
+func (p *Controller) Update(c *gin.Context) {
+	log.C(c).Debug("update policy function called")
+
+	var opts metav1.UpdateOptions
+	if err := c.ShouldBindQuery(&opts); err != nil {
+		core.WriteResponse(c, errors.WithCode(code.ErrBind, err.Error()), nil)
+
+		return
+	}
+
+	var r v1.Policy
+
+	if err := c.ShouldBindJSON(&r); err != nil {
+		core.WriteResponse(c, errors.WithCode(code.ErrBind, err.Error()), nil)
+
+		return
+	}
+
+	ctx := middleware.RequestContext(c)
+
+	policy, err := p.srv.Policies().Get(ctx, c.GetString(middleware.UsernameKey), c.Param("name"), metav1.GetOptions{})
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+
+		return
+	}
+
+	// The update is based on the version in the If-Match header or the request body if any,
+	// it is rejected if the resource has been modified since then.
+	if !core.IfMatch(c, policy.ResourceVersion) || !core.IfNoneMatch(c, policy.ResourceVersion) ||
+		(r.ResourceVersion != 0 && r.ResourceVersion != policy.ResourceVersion) {
+		core.WriteResponse(
+			c,
+			errors.WithCode(code.ErrResourceConflict, "the current resource version is %d", policy.ResourceVersion),
+			nil,
+		)
+
+		return
+	}
+
+	policy.Description = r.Description
+	policy.Effect = r.Effect
+	policy.Subjects = r.Subjects
+	policy.Actions = r.Actions
+	policy.Resources = r.Resources
+	policy.Conditions = r.Conditions
+	policy.Labels = r.Labels
+	policy.Extend = r.Extend
+
+	if errs := policy.Validate(); len(errs) != 0 {
+		core.WriteResponse(c, errors.WithCode(code.ErrValidation, errs.ToAggregate().Error()), nil)
+
+		return
+	}
+
+	if err := p.srv.Policies().Update(ctx, policy, opts); err != nil {
+		core.WriteResponse(c, err, nil)
+
+		return
+	}
+
+	core.SetETag(c, policy.ResourceVersion)
+
+	core.WriteResponse(c, nil, policy)
+}
@@ -0,0 +1,56 @@
+package secret
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"gobackend/pkg/core"
+	"gobackend/pkg/errors"
+	"gobackend/pkg/log"
+	metav1 "gobackend/pkg/meta/v1"
+
+	"gobackend/internal/pkg/code"
+	v1 "gobackend/internal/pkg/entity/apiserver/v1"
+	"gobackend/internal/pkg/middleware"
+)
+
+// Create add new secret key pairs to the storage.
+func (s *Controller) Create(c *gin.Context) {
+	log.C(c).Debug("secret create function called")
+
+	var opts metav1.CreateOptions
+	if err := c.ShouldBindQuery(&opts); err != nil {
+		core.WriteResponse(c, errors.WithCode(code.ErrBind, err.Error()), nil)
+
+		return
+	}
+
+	var r v1.Secret
+
+	if err := c.ShouldBindJSON(&r); err != nil {
+		core.WriteResponse(c, errors.WithCode(code.ErrBind, err.Error()), nil)
+
+		return
+	}
+
+	if errs := r.Validate(); len(errs) != 0 {
+		core.WriteResponse(c, errors.WithCode(code.ErrValidation, errs.ToAggregate().Error()), nil)
+
+		return
+	}
+
+	// The secret is always owned by the requester.
+	r.Username = c.GetString(middleware.UsernameKey)
+
+	ctx := middleware.RequestContext(c)
+
+	if err := s.srv.Secrets().Create(ctx, &r, opts); err != nil {
+		core.WriteResponse(c, err, nil)
+
+		return
+	}
+
+	// The id may be cached as not found.
+	s.cache.Invalidate(r.SecretID)
+
+	core.WriteResponse(c, nil, r)
+}
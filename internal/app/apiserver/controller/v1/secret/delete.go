@@ -0,0 +1,46 @@
+package secret
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"gobackend/pkg/core"
+	"gobackend/pkg/errors"
+	"gobackend/pkg/log"
+	metav1 "gobackend/pkg/meta/v1"
+
+	"gobackend/internal/pkg/code"
+	"gobackend/internal/pkg/middleware"
+)
+
+// Delete delete a secret by the secret identifier.
+func (s *Controller) Delete(c *gin.Context) {
+	log.C(c).Debug("delete secret function called")
+
+	var opts metav1.DeleteOptions
+	if err := c.ShouldBindQuery(&opts); err != nil {
+		core.WriteResponse(c, errors.WithCode(code.ErrBind, err.Error()), nil)
+
+		return
+	}
+
+	username := c.GetString(middleware.UsernameKey)
+
+	ctx := middleware.RequestContext(c)
+
+	secret, err := s.srv.Secrets().Get(ctx, username, c.Param("name"), metav1.GetOptions{})
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+
+		return
+	}
+
+	if err := s.srv.Secrets().Delete(ctx, username, secret.Name, metav1.DeleteOptions{Unscoped: true, DryRun: opts.DryRun}); err != nil {
+		core.WriteResponse(c, err, nil)
+
+		return
+	}
+
+	s.cache.Invalidate(secret.SecretID)
+
+	core.WriteResponse(c, nil, nil)
+}
@@ -0,0 +1,57 @@
+package secret
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"gobackend/pkg/core"
+	"gobackend/pkg/errors"
+	"gobackend/pkg/log"
+	metav1 "gobackend/pkg/meta/v1"
+
+	"gobackend/internal/pkg/code"
+	"gobackend/internal/pkg/middleware"
+)
+
+// DeleteCollection batch delete secrets by multiple secret names.
+func (s *Controller) DeleteCollection(c *gin.Context) {
+	log.C(c).Info("batch delete secret function called.")
+
+	var opts metav1.DeleteOptions
+	if err := c.ShouldBindQuery(&opts); err != nil {
+		core.WriteResponse(c, errors.WithCode(code.ErrBind, err.Error()), nil)
+
+		return
+	}
+
+	username := c.GetString(middleware.UsernameKey)
+	names := c.QueryArray("name")
+
+	ctx := middleware.RequestContext(c)
+
+	secrets, err := s.srv.Secrets().List(ctx, username, metav1.ListOptions{})
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+
+		return
+	}
+
+	if err := s.srv.Secrets().DeleteCollection(ctx, username, names, metav1.DeleteOptions{Unscoped: true, DryRun: opts.DryRun}); err != nil {
+		core.WriteResponse(c, err, nil)
+
+		return
+	}
+
+	secretIDs := make([]string, 0, len(names))
+
+	for _, secret := range secrets.Items {
+		for _, name := range names {
+			if secret.Name == name {
+				secretIDs = append(secretIDs, secret.SecretID)
+			}
+		}
+	}
+
+	s.cache.Invalidate(secretIDs...)
+
+	core.WriteResponse(c, nil, nil)
+}
@@ -0,0 +1,54 @@
+package secret
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"gobackend/pkg/core"
+	"gobackend/pkg/errors"
+	"gobackend/pkg/log"
+	metav1 "gobackend/pkg/meta/v1"
+
+	"gobackend/internal/pkg/code"
+	"gobackend/internal/pkg/middleware"
+)
+
+// Get get a secret by the secret identifier.
+func (s *Controller) Get(c *gin.Context) {
+	log.C(c).Debug("get secret function called")
+
+	ctx := middleware.RequestContext(c)
+
+	secret, err := s.srv.Secrets().Get(
+		ctx,
+		c.GetString(middleware.UsernameKey),
+		c.Param("name"),
+		metav1.GetOptions{},
+	)
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+
+		return
+	}
+
+	if !core.IfMatch(c, secret.ResourceVersion) {
+		core.WriteResponse(
+			c,
+			errors.WithCode(code.ErrResourceConflict, "the current resource version is %d", secret.ResourceVersion),
+			nil,
+		)
+
+		return
+	}
+
+	core.SetETag(c, secret.ResourceVersion)
+
+	if !core.IfNoneMatch(c, secret.ResourceVersion) {
+		c.Status(http.StatusNotModified)
+
+		return
+	}
+
+	core.WriteResponse(c, nil, secret)
+}
@@ -0,0 +1,50 @@
+package secret
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"gobackend/pkg/core"
+	"gobackend/pkg/errors"
+	"gobackend/pkg/labels"
+	"gobackend/pkg/log"
+	metav1 "gobackend/pkg/meta/v1"
+
+	"gobackend/internal/app/apiserver/store"
+	"gobackend/internal/pkg/code"
+	"gobackend/internal/pkg/middleware"
+)
+
+// List list the secrets of the requester.
+func (s *Controller) List(c *gin.Context) {
+	log.C(c).Debug("list secret function called")
+
+	var r metav1.ListOptions
+	if err := c.ShouldBindQuery(&r); err != nil {
+		core.WriteResponse(c, errors.WithCode(code.ErrBind, err.Error()), nil)
+
+		return
+	}
+
+	if _, err := store.SecretFields.Requirements(r.FieldSelector); err != nil {
+		core.WriteResponse(c, err, nil)
+
+		return
+	}
+
+	if _, err := labels.Parse(r.LabelSelector); err != nil {
+		core.WriteResponse(c, errors.WithCode(code.ErrLabelSelectorValidation, err.Error()), nil)
+
+		return
+	}
+
+	ctx := middleware.RequestContext(c)
+
+	secrets, err := s.srv.Secrets().List(ctx, c.GetString(middleware.UsernameKey), r)
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+
+		return
+	}
+
+	core.WriteResponse(c, nil, secrets)
+}
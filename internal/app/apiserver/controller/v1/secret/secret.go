@@ -0,0 +1,25 @@
+package secret
+
+import (
+	srvv1 "gobackend/internal/app/apiserver/service/v1"
+	"gobackend/internal/app/apiserver/store"
+)
+
+// Invalidator removes changed or deleted secrets from the secret cache.
+type Invalidator interface {
+	Invalidate(secretIDs ...string)
+}
+
+// Controller create a secret handler used to handle request for secret resource.
+type Controller struct {
+	srv   srvv1.Service
+	cache Invalidator
+}
+
+// NewController creates a secret handler.
+func NewController(store store.Factory, cache Invalidator) *Controller {
+	return &Controller{
+		srv:   srvv1.NewService(store),
+		cache: cache,
+	}
+}
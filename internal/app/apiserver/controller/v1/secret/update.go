@@ -0,0 +1,80 @@
+package secret
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"gobackend/pkg/core"
+	"gobackend/pkg/errors"
+	"gobackend/pkg/log"
+	metav1 "gobackend/pkg/meta/v1"
+
+	"gobackend/internal/pkg/code"
+	v1 "gobackend/internal/pkg/entity/apiserver/v1"
+	"gobackend/internal/pkg/middleware"
+)
+
+// Update update a secret by the secret identifier.
+// Only the expires, description and extend fields can be changed.
+func (s *Controller) Update(c *gin.Context) {
+	log.C(c).Debug("update secret function called")
+
+	var opts metav1.UpdateOptions
+	if err := c.ShouldBindQuery(&opts); err != nil {
+		core.WriteResponse(c, errors.WithCode(code.ErrBind, err.Error()), nil)
+
+		return
+	}
+
+	var r v1.Secret
+
+	if err := c.ShouldBindJSON(&r); err != nil {
+		core.WriteResponse(c, errors.WithCode(code.ErrBind, err.Error()), nil)
+
+		return
+	}
+
+	ctx := middleware.RequestContext(c)
+
+	secret, err := s.srv.Secrets().Get(ctx, c.GetString(middleware.UsernameKey), c.Param("name"), metav1.GetOptions{})
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+
+		return
+	}
+
+	// The update is based on the version in the If-Match header or the request body if any,
+	// it is rejected if the resource has been modified since then.
+	if !core.IfMatch(c, secret.ResourceVersion) || !core.IfNoneMatch(c, secret.ResourceVersion) ||
+		(r.ResourceVersion != 0 && r.ResourceVersion != secret.ResourceVersion) {
+		core.WriteResponse(
+			c,
+			errors.WithCode(code.ErrResourceConflict, "the current resource version is %d", secret.ResourceVersion),
+			nil,
+		)
+
+		return
+	}
+
+	secret.Expires = r.Expires
+	secret.Description = r.Description
+	secret.Labels = r.Labels
+	secret.Extend = r.Extend
+
+	if errs := secret.Validate(); len(errs) != 0 {
+		core.WriteResponse(c, errors.WithCode(code.ErrValidation, errs.ToAggregate().Error()), nil)
+
+		return
+	}
+
+	if err := s.srv.Secrets().Update(ctx, secret, opts); err != nil {
+		core.WriteResponse(c, err, nil)
+
+		return
+	}
+
+	s.cache.Invalidate(secret.SecretID)
+
+	core.SetETag(c, secret.ResourceVersion)
+
+	core.WriteResponse(c, nil, secret)
+}
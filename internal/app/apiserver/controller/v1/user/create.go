@@ -10,12 +10,20 @@ import (
 
 	"gobackend/internal/pkg/code"
 	v1 "gobackend/internal/pkg/entity/apiserver/v1"
+	"gobackend/internal/pkg/middleware"
 )
 
 // Create add new user to the storage.
 func (u *Controller) Create(c *gin.Context) {
 	log.C(c).Debug("user create function called")
 
+	var opts metav1.CreateOptions
+	if err := c.ShouldBindQuery(&opts); err != nil {
+		core.WriteResponse(c, errors.WithCode(code.ErrBind, err.Error()), nil)
+
+		return
+	}
+
 	var r v1.User
 
 	if err := c.ShouldBindJSON(&r); err != nil {
@@ -31,7 +39,9 @@ func (u *Controller) Create(c *gin.Context) {
 	}
 
 	// Insert the user to the storage.
-	if err := u.srv.Users().Create(c, &r, metav1.CreateOptions{}); err != nil {
+	ctx := middleware.RequestContext(c)
+
+	if err := u.srv.Users().Create(ctx, &r, opts); err != nil {
 		core.WriteResponse(c, err, nil)
 
 		return
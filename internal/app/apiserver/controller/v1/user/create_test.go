@@ -0,0 +1,97 @@
+package user
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"gobackend/pkg/errors"
+	"gobackend/pkg/json"
+	metav1 "gobackend/pkg/meta/v1"
+
+	"gobackend/internal/app/apiserver/store/fake"
+	"gobackend/internal/pkg/code"
+	"gobackend/internal/pkg/entity/apiserver/operationlog"
+	"gobackend/internal/pkg/middleware"
+)
+
+// recordingQueue records the operation logs instead of writing them.
+type recordingQueue struct {
+	mu   sync.Mutex
+	logs []*operationlog.OperationLog
+}
+
+func (q *recordingQueue) Enqueue(operationLog *operationlog.OperationLog) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.logs = append(q.logs, operationLog)
+
+	return true
+}
+
+func TestCreateDryRun(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	ds := fake.New()
+	queue := &recordingQueue{}
+
+	g := gin.New()
+	g.POST("/users", func(c *gin.Context) {
+		c.Set(middleware.UsernameKey, "alice")
+	}, middleware.OperationLog(queue), NewController(ds, nopInvalidator{}).Create)
+
+	create := func(query string) *httptest.ResponseRecorder {
+		body := `{"metadata":{"name":"bob"},"nickname":"bob","password":"Passw0rd!","email":"bob@example.com"}`
+		req := httptest.NewRequest(http.MethodPost, "/users"+query, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		w := httptest.NewRecorder()
+		g.ServeHTTP(w, req)
+
+		return w
+	}
+
+	w := create("?dry_run=All")
+	if w.Code != http.StatusOK {
+		t.Fatalf("POST dry run status = %d, body %s", w.Code, w.Body.String())
+	}
+
+	var resp struct {
+		Metadata map[string]interface{} `json:"metadata"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("unmarshal %s: %v", w.Body.String(), err)
+	}
+
+	for _, field := range []string{"id", "instance_id", "resource_version"} {
+		if _, ok := resp.Metadata[field]; ok {
+			t.Errorf("POST dry run response has %s: %s", field, w.Body.String())
+		}
+	}
+
+	if _, err := ds.Users().Get(context.Background(), "bob", metav1.GetOptions{}); !errors.IsCode(err, code.ErrUserNotFound) {
+		t.Errorf("Get() after dry run error = %v, want ErrUserNotFound", err)
+	}
+
+	if len(queue.logs) != 0 {
+		t.Errorf("dry run is logged: %+v", queue.logs[0])
+	}
+
+	if w := create(""); w.Code != http.StatusOK {
+		t.Fatalf("POST status = %d, body %s", w.Code, w.Body.String())
+	}
+
+	if _, err := ds.Users().Get(context.Background(), "bob", metav1.GetOptions{}); err != nil {
+		t.Errorf("Get() error = %v", err)
+	}
+
+	if len(queue.logs) != 1 {
+		t.Errorf("logged %d operations, want 1", len(queue.logs))
+	}
+}
@@ -4,8 +4,12 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"gobackend/pkg/core"
+	"gobackend/pkg/errors"
 	"gobackend/pkg/log"
 	metav1 "gobackend/pkg/meta/v1"
+
+	"gobackend/internal/pkg/code"
+	"gobackend/internal/pkg/middleware"
 )
 
 // Delete delete an user by the user identifier.
@@ -13,11 +17,23 @@ import (
 func (u *Controller) Delete(c *gin.Context) {
 	log.C(c).Debug("delete user function called")
 
-	if err := u.srv.Users().Delete(c, c.Param("name"), metav1.DeleteOptions{Unscoped: true}); err != nil {
+	var opts metav1.DeleteOptions
+	if err := c.ShouldBindQuery(&opts); err != nil {
+		core.WriteResponse(c, errors.WithCode(code.ErrBind, err.Error()), nil)
+
+		return
+	}
+
+	ctx := middleware.RequestContext(c)
+
+	if err := u.srv.Users().Delete(ctx, c.Param("name"), metav1.DeleteOptions{Unscoped: true, DryRun: opts.DryRun}); err != nil {
 		core.WriteResponse(c, err, nil)
 
 		return
 	}
 
+	// The secrets of the user are deleted with it.
+	u.cache.InvalidateUsers(c.Param("name"))
+
 	core.WriteResponse(c, nil, nil)
 }
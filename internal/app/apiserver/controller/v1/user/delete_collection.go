@@ -4,8 +4,12 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"gobackend/pkg/core"
+	"gobackend/pkg/errors"
 	"gobackend/pkg/log"
 	metav1 "gobackend/pkg/meta/v1"
+
+	"gobackend/internal/pkg/code"
+	"gobackend/internal/pkg/middleware"
 )
 
 // DeleteCollection batch delete users by multiple usernames.
@@ -13,13 +17,25 @@ import (
 func (u *Controller) DeleteCollection(c *gin.Context) {
 	log.C(c).Info("batch delete user function called.")
 
+	var opts metav1.DeleteOptions
+	if err := c.ShouldBindQuery(&opts); err != nil {
+		core.WriteResponse(c, errors.WithCode(code.ErrBind, err.Error()), nil)
+
+		return
+	}
+
 	usernames := c.QueryArray("name")
 
-	if err := u.srv.Users().DeleteCollection(c, usernames, metav1.DeleteOptions{}); err != nil {
+	ctx := middleware.RequestContext(c)
+
+	if err := u.srv.Users().DeleteCollection(ctx, usernames, opts); err != nil {
 		core.WriteResponse(c, err, nil)
 
 		return
 	}
 
+	// The secrets of the users are deleted with them.
+	u.cache.InvalidateUsers(usernames...)
+
 	core.WriteResponse(c, nil, nil)
 }
@@ -1,23 +1,49 @@
 package user
 
 import (
+	"net/http"
+
 	"github.com/gin-gonic/gin"
 
 	"gobackend/pkg/core"
+	"gobackend/pkg/errors"
 	"gobackend/pkg/log"
 	metav1 "gobackend/pkg/meta/v1"
+
+	"gobackend/internal/pkg/code"
+	"gobackend/internal/pkg/middleware"
 )
 
 // Get get an user by the user identifier.
 func (u *Controller) Get(c *gin.Context) {
 	log.C(c).Debug("user Get function is called")
 
-	user, err := u.srv.Users().Get(c, c.Param("name"), metav1.GetOptions{})
+	ctx := middleware.RequestContext(c)
+
+	user, err := u.srv.Users().Get(ctx, c.Param("name"), metav1.GetOptions{})
 	if err != nil {
 		core.WriteResponse(c, err, nil)
 
 		return
 	}
 
+	if !core.IfMatch(c, user.ResourceVersion) {
+		core.WriteResponse(
+			c,
+			errors.WithCode(code.ErrResourceConflict, "the current resource version is %d", user.ResourceVersion),
+			nil,
+		)
+
+		return
+	}
+
+	core.SetETag(c, user.ResourceVersion)
+
+	if !core.IfNoneMatch(c, user.ResourceVersion) {
+		c.Status(http.StatusNotModified)
+
+		return
+	}
+
 	core.WriteResponse(c, nil, user)
 }
@@ -5,11 +5,13 @@ import (
 
 	"gobackend/pkg/core"
 	"gobackend/pkg/errors"
-	"gobackend/pkg/fields"
+	"gobackend/pkg/labels"
 	"gobackend/pkg/log"
 	metav1 "gobackend/pkg/meta/v1"
 
+	"gobackend/internal/app/apiserver/store"
 	"gobackend/internal/pkg/code"
+	"gobackend/internal/pkg/middleware"
 )
 
 // List users.
@@ -23,13 +25,21 @@ func (u *Controller) List(c *gin.Context) {
 		return
 	}
 
-	if _, err := fields.ParseSelector(r.FieldSelector); err != nil {
-		core.WriteResponse(c, errors.WithCode(code.ErrFieldSelectorValidation, ""), nil)
+	if _, err := store.UserFields.Requirements(r.FieldSelector); err != nil {
+		core.WriteResponse(c, err, nil)
+
+		return
+	}
+
+	if _, err := labels.Parse(r.LabelSelector); err != nil {
+		core.WriteResponse(c, errors.WithCode(code.ErrLabelSelectorValidation, err.Error()), nil)
 
 		return
 	}
 
-	users, err := u.srv.Users().List(c, r)
+	ctx := middleware.RequestContext(c)
+
+	users, err := u.srv.Users().List(ctx, r)
 
 	if err != nil {
 		core.WriteResponse(c, err, nil)
@@ -0,0 +1,140 @@
+package user
+
+import (
+	"encoding/json"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/gin-gonic/gin"
+
+	"gobackend/pkg/core"
+	"gobackend/pkg/errors"
+	"gobackend/pkg/log"
+	metav1 "gobackend/pkg/meta/v1"
+
+	"gobackend/internal/pkg/code"
+	v1 "gobackend/internal/pkg/entity/apiserver/v1"
+	"gobackend/internal/pkg/middleware"
+)
+
+// Supported patch content types.
+const (
+	mergePatchType = "application/merge-patch+json"
+	jsonPatchType  = "application/json-patch+json"
+)
+
+// Patch partially update a user info by the user identifier.
+// The request body is a JSON Merge Patch (RFC 7386) or a JSON Patch (RFC 6902) document
+// selected by the Content-Type header, only the fields which can be updated by Update
+// are applied.
+func (u *Controller) Patch(c *gin.Context) {
+	log.C(c).Debug("patch user function called")
+
+	var opts metav1.PatchOptions
+	if err := c.ShouldBindQuery(&opts); err != nil {
+		core.WriteResponse(c, errors.WithCode(code.ErrBind, err.Error()), nil)
+
+		return
+	}
+
+	contentType := c.ContentType()
+	if contentType != mergePatchType && contentType != jsonPatchType {
+		core.WriteResponse(c, errors.WithCode(
+			code.ErrBind,
+			"unsupported patch type `%s`, supported types: %s, %s",
+			contentType,
+			mergePatchType,
+			jsonPatchType,
+		), nil)
+
+		return
+	}
+
+	patch, err := c.GetRawData()
+	if err != nil {
+		core.WriteResponse(c, errors.WithCode(code.ErrBind, err.Error()), nil)
+
+		return
+	}
+
+	ctx := middleware.RequestContext(c)
+
+	user, err := u.srv.Users().Get(ctx, c.Param("name"), metav1.GetOptions{})
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+
+		return
+	}
+
+	r, err := applyPatch(user, contentType, patch)
+	if err != nil {
+		core.WriteResponse(c, errors.WithCode(code.ErrBind, err.Error()), nil)
+
+		return
+	}
+
+	// Like Update, the patch is based on the version in the If-Match header or the patched
+	// object if any, it is rejected if the resource has been modified since then.
+	if !core.IfMatch(c, user.ResourceVersion) || !core.IfNoneMatch(c, user.ResourceVersion) ||
+		r.ResourceVersion != user.ResourceVersion {
+		core.WriteResponse(
+			c,
+			errors.WithCode(code.ErrResourceConflict, "the current resource version is %d", user.ResourceVersion),
+			nil,
+		)
+
+		return
+	}
+
+	user.Nickname = r.Nickname
+	user.Email = r.Email
+	user.Phone = r.Phone
+	user.Labels = r.Labels
+	user.Extend = r.Extend
+
+	if errs := user.ValidateUpdate(); len(errs) != 0 {
+		core.WriteResponse(c, errors.WithCode(code.ErrValidation, errs.ToAggregate().Error()), nil)
+
+		return
+	}
+
+	if err := u.srv.Users().Update(ctx, user, metav1.UpdateOptions{DryRun: opts.DryRun}); err != nil {
+		core.WriteResponse(c, err, nil)
+
+		return
+	}
+
+	core.SetETag(c, user.ResourceVersion)
+
+	core.WriteResponse(c, nil, user)
+}
+
+// applyPatch applies the patch to the JSON document of user and returns the patched user.
+func applyPatch(user *v1.User, contentType string, patch []byte) (*v1.User, error) {
+	original, err := json.Marshal(user)
+	if err != nil {
+		return nil, err
+	}
+
+	var patched []byte
+
+	switch contentType {
+	case mergePatchType:
+		patched, err = jsonpatch.MergePatch(original, patch)
+	default:
+		var p jsonpatch.Patch
+		if p, err = jsonpatch.DecodePatch(patch); err == nil {
+			patched, err = p.Apply(original)
+		}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var r v1.User
+	if err := json.Unmarshal(patched, &r); err != nil {
+		return nil, err
+	}
+
+	return &r, nil
+}
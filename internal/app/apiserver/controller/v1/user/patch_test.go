@@ -0,0 +1,175 @@
+package user
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"gobackend/pkg/core"
+	"gobackend/pkg/json"
+	metav1 "gobackend/pkg/meta/v1"
+
+	"gobackend/internal/app/apiserver/store"
+	"gobackend/internal/app/apiserver/store/fake"
+	"gobackend/internal/pkg/code"
+	v1 "gobackend/internal/pkg/entity/apiserver/v1"
+	"gobackend/internal/pkg/middleware"
+)
+
+type nopInvalidator struct{}
+
+func (nopInvalidator) InvalidateUsers(...string) {}
+
+// newPatchServer returns the store with alice, and the router serving the patches of alice by alice.
+func newPatchServer(t *testing.T) (store.Factory, *gin.Engine) {
+	t.Helper()
+
+	gin.SetMode(gin.TestMode)
+
+	ds := fake.New()
+
+	user := &v1.User{Nickname: "alice", Password: "Passw0rd!", Email: "alice@example.com"}
+	user.Name = "alice"
+
+	if err := ds.Users().Create(context.Background(), user, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	g := gin.New()
+	g.PATCH("/users/:name", func(c *gin.Context) {
+		c.Set(middleware.UsernameKey, "alice")
+	}, NewController(ds, nopInvalidator{}).Patch)
+
+	return ds, g
+}
+
+func TestPatch(t *testing.T) {
+	tests := []struct {
+		name         string
+		contentType  string
+		ifMatch      string
+		body         string
+		wantStatus   int
+		wantCode     int
+		wantNickname string
+	}{
+		{
+			name:         "merge patch",
+			contentType:  mergePatchType,
+			body:         `{"nickname":"ally","phone":null}`,
+			wantStatus:   http.StatusOK,
+			wantNickname: "ally",
+		},
+		{
+			name:         "json patch",
+			contentType:  jsonPatchType,
+			body:         `[{"op":"test","path":"/nickname","value":"alice"},{"op":"replace","path":"/nickname","value":"ally"}]`,
+			wantStatus:   http.StatusOK,
+			wantNickname: "ally",
+		},
+		{
+			name:         "unsupported content type",
+			contentType:  "application/json",
+			body:         `{"nickname":"ally"}`,
+			wantStatus:   http.StatusBadRequest,
+			wantCode:     code.ErrBind,
+			wantNickname: "alice",
+		},
+		{
+			name:         "disallowed fields are ignored",
+			contentType:  mergePatchType,
+			body:         `{"nickname":"ally","is_admin":1,"password":"Hacked0!","metadata":{"name":"mallory"}}`,
+			wantStatus:   http.StatusOK,
+			wantNickname: "ally",
+		},
+		{
+			name:         "resource version mismatch",
+			contentType:  mergePatchType,
+			body:         `{"nickname":"ally","metadata":{"resource_version":99}}`,
+			wantStatus:   http.StatusConflict,
+			wantCode:     code.ErrResourceConflict,
+			wantNickname: "alice",
+		},
+		{
+			name:         "if-match mismatch",
+			contentType:  jsonPatchType,
+			ifMatch:      `"99"`,
+			body:         `[{"op":"replace","path":"/nickname","value":"ally"}]`,
+			wantStatus:   http.StatusConflict,
+			wantCode:     code.ErrResourceConflict,
+			wantNickname: "alice",
+		},
+		{
+			name:         "invalid merge patch",
+			contentType:  mergePatchType,
+			body:         `{"nickname":`,
+			wantStatus:   http.StatusBadRequest,
+			wantCode:     code.ErrBind,
+			wantNickname: "alice",
+		},
+		{
+			name:         "invalid json patch",
+			contentType:  jsonPatchType,
+			body:         `{"op":"replace","path":"/nickname","value":"ally"}`,
+			wantStatus:   http.StatusBadRequest,
+			wantCode:     code.ErrBind,
+			wantNickname: "alice",
+		},
+		{
+			name:         "failed json patch test",
+			contentType:  jsonPatchType,
+			body:         `[{"op":"test","path":"/nickname","value":"bob"},{"op":"replace","path":"/nickname","value":"ally"}]`,
+			wantStatus:   http.StatusBadRequest,
+			wantCode:     code.ErrBind,
+			wantNickname: "alice",
+		},
+		{
+			name:         "invalid patched user",
+			contentType:  mergePatchType,
+			body:         `{"email":"not an email"}`,
+			wantStatus:   http.StatusBadRequest,
+			wantCode:     code.ErrValidation,
+			wantNickname: "alice",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ds, g := newPatchServer(t)
+
+			req := httptest.NewRequest(http.MethodPatch, "/users/alice", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+
+			w := httptest.NewRecorder()
+			g.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("PATCH status = %d, want %d, body %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+
+			if tt.wantCode != 0 {
+				var resp core.ErrResponse
+				if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil || resp.Code != tt.wantCode {
+					t.Errorf("PATCH response = %s, want code %d", w.Body.String(), tt.wantCode)
+				}
+			}
+
+			user, err := ds.Users().Get(context.Background(), "alice", metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+
+			if user.Nickname != tt.wantNickname || user.IsAdministrator() || user.Compare("Passw0rd!") != nil {
+				t.Errorf("patched user = %+v, want nickname %s and the other fields unchanged", user, tt.wantNickname)
+			}
+		})
+	}
+}
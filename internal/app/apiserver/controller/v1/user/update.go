@@ -10,12 +10,20 @@ import (
 
 	"gobackend/internal/pkg/code"
 	v1 "gobackend/internal/pkg/entity/apiserver/v1"
+	"gobackend/internal/pkg/middleware"
 )
 
 // Update update a user info by the user identifier.
 func (u *Controller) Update(c *gin.Context) {
 	log.C(c).Debug("update user function called")
 
+	var opts metav1.UpdateOptions
+	if err := c.ShouldBindQuery(&opts); err != nil {
+		core.WriteResponse(c, errors.WithCode(code.ErrBind, err.Error()), nil)
+
+		return
+	}
+
 	var r v1.User
 
 	if err := c.ShouldBindJSON(&r); err != nil {
@@ -24,16 +32,32 @@ func (u *Controller) Update(c *gin.Context) {
 		return
 	}
 
-	user, err := u.srv.Users().Get(c, c.Param("name"), metav1.GetOptions{})
+	ctx := middleware.RequestContext(c)
+
+	user, err := u.srv.Users().Get(ctx, c.Param("name"), metav1.GetOptions{})
 	if err != nil {
 		core.WriteResponse(c, err, nil)
 
 		return
 	}
 
+	// The update is based on the version in the If-Match header or the request body if any,
+	// it is rejected if the resource has been modified since then.
+	if !core.IfMatch(c, user.ResourceVersion) || !core.IfNoneMatch(c, user.ResourceVersion) ||
+		(r.ResourceVersion != 0 && r.ResourceVersion != user.ResourceVersion) {
+		core.WriteResponse(
+			c,
+			errors.WithCode(code.ErrResourceConflict, "the current resource version is %d", user.ResourceVersion),
+			nil,
+		)
+
+		return
+	}
+
 	user.Nickname = r.Nickname
 	user.Email = r.Email
 	user.Phone = r.Phone
+	user.Labels = r.Labels
 	user.Extend = r.Extend
 
 	if errs := user.ValidateUpdate(); len(errs) != 0 {
@@ -43,11 +67,13 @@ func (u *Controller) Update(c *gin.Context) {
 	}
 
 	// Save changed fields.
-	if err := u.srv.Users().Update(c, user, metav1.UpdateOptions{}); err != nil {
+	if err := u.srv.Users().Update(ctx, user, opts); err != nil {
 		core.WriteResponse(c, err, nil)
 
 		return
 	}
 
+	core.SetETag(c, user.ResourceVersion)
+
 	core.WriteResponse(c, nil, user)
 }
@@ -5,14 +5,21 @@ import (
 	"gobackend/internal/app/apiserver/store"
 )
 
+// Invalidator removes the secrets of deleted users from the secret cache.
+type Invalidator interface {
+	InvalidateUsers(usernames ...string)
+}
+
 // Controller create a user handler used to handle request for user resource.
 type Controller struct {
-	srv srvv1.Service
+	srv   srvv1.Service
+	cache Invalidator
 }
 
 // NewController creates a user handler.
-func NewController(store store.Factory) *Controller {
+func NewController(store store.Factory, cache Invalidator) *Controller {
 	return &Controller{
-		srv: srvv1.NewService(store),
+		srv:   srvv1.NewService(store),
+		cache: cache,
 	}
 }
@@ -0,0 +1,164 @@
+package apiserver
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gosuri/uitable"
+
+	"gobackend/pkg/app"
+	"gobackend/pkg/db/migrate"
+	cliflag "gobackend/pkg/flag"
+
+	"gobackend/internal/app/apiserver/store/mysql"
+	genericoptions "gobackend/internal/pkg/options"
+)
+
+// migrateOptions only contains the options needed to connect to the database.
+type migrateOptions struct {
+	MySQL *genericoptions.MySQLOptions `json:"mysql" mapstructure:"mysql"`
+}
+
+func (o *migrateOptions) Flags() (fss cliflag.NamedFlagSets) {
+	o.MySQL.AddFlags(fss.FlagSet("mysql"))
+
+	return fss
+}
+
+func (o *migrateOptions) Validate() []error {
+	errs := o.MySQL.Validate()
+
+	if o.MySQL.Driver == genericoptions.DriverMemory {
+		errs = append(errs, fmt.Errorf("the memory driver has no schema to migrate"))
+	}
+
+	return errs
+}
+
+// resetOptions requires the confirmation of the reset, which drops all the data.
+type resetOptions struct {
+	migrateOptions `mapstructure:",squash"`
+
+	// Yes is only set by the flag, the configuration file can not confirm the reset.
+	Yes bool `json:"-" mapstructure:"-"`
+}
+
+func (o *resetOptions) Flags() (fss cliflag.NamedFlagSets) {
+	fss = o.migrateOptions.Flags()
+	fss.FlagSet("migrate").BoolVar(&o.Yes, "yes", o.Yes, "Confirm the reset, ALL DATA WILL BE LOST.")
+
+	return fss
+}
+
+func (o *resetOptions) Validate() []error {
+	errs := o.migrateOptions.Validate()
+
+	if !o.Yes {
+		errs = append(errs, fmt.Errorf("reset drops all the data, confirm it with --yes"))
+	}
+
+	return errs
+}
+
+// newMigrateCommand creates the `migrate` command which manages the versioned database schema.
+func newMigrateCommand() *app.Command {
+	opts := &migrateOptions{
+		MySQL: genericoptions.NewMySQLOptions(),
+	}
+	resetOpts := &resetOptions{
+		migrateOptions: migrateOptions{MySQL: genericoptions.NewMySQLOptions()},
+	}
+
+	cmd := app.NewCommand("migrate", "Manage the versioned database schema.")
+	cmd.AddCommands(
+		app.NewCommand("up [N]", "Apply all or the next N pending migrations.",
+			app.WithCommandOptions(opts),
+			app.WithCommandRunFunc(withMigrator(opts, func(m *migrate.Migrator, n int) error {
+				applied, err := m.Up(n)
+				fmt.Printf("%d migrations applied\n", applied)
+
+				return err
+			})),
+		),
+		app.NewCommand("down [N]", "Roll back the last N applied migrations, N defaults to 1.",
+			app.WithCommandOptions(opts),
+			app.WithCommandRunFunc(withMigrator(opts, func(m *migrate.Migrator, n int) error {
+				if n <= 0 {
+					n = 1
+				}
+
+				rolledBack, err := m.Down(n)
+				fmt.Printf("%d migrations rolled back\n", rolledBack)
+
+				return err
+			})),
+		),
+		app.NewCommand("status", "Show the status of all the migrations.",
+			app.WithCommandOptions(opts),
+			app.WithCommandRunFunc(withMigrator(opts, func(m *migrate.Migrator, _ int) error {
+				status, err := m.Status()
+				if err != nil {
+					return err
+				}
+
+				table := uitable.New()
+				table.AddRow("VERSION", "DESCRIPTION", "APPLIED AT")
+
+				for _, s := range status {
+					appliedAt := "pending"
+					if s.Applied {
+						appliedAt = s.AppliedAt.Format(time.RFC3339)
+					}
+
+					table.AddRow(s.Version, s.Description, appliedAt)
+				}
+
+				fmt.Println(table)
+
+				return nil
+			})),
+		),
+		app.NewCommand("reset", "Roll back all the migrations and apply them again, ALL DATA WILL BE LOST. "+
+			"It must be confirmed with --yes.",
+			app.WithCommandOptions(resetOpts),
+			app.WithCommandRunFunc(withMigrator(&resetOpts.migrateOptions, func(m *migrate.Migrator, _ int) error {
+				if err := m.Reset(); err != nil {
+					return err
+				}
+
+				fmt.Println("all migrations reset")
+
+				return nil
+			})),
+		),
+	)
+
+	return cmd
+}
+
+// withMigrator parses the optional step count argument and runs fn with a connected migrator.
+func withMigrator(opts *migrateOptions, fn func(m *migrate.Migrator, n int) error) app.RunCommandFunc {
+	return func(args []string) error {
+		var n int
+
+		if len(args) > 1 {
+			return fmt.Errorf("accepts at most 1 argument, received %d", len(args))
+		}
+
+		if len(args) == 1 {
+			var err error
+			if n, err = strconv.Atoi(args[0]); err != nil || n <= 0 {
+				return fmt.Errorf("invalid migration step count %q", args[0])
+			}
+		}
+
+		m, closeDB, err := mysql.NewMigrator(opts.MySQL)
+		if err != nil {
+			return err
+		}
+		defer closeDB()
+
+		return fn(m, n)
+	}
+}
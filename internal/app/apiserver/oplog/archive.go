@@ -0,0 +1,86 @@
+package oplog
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gobackend/pkg/json"
+
+	"gobackend/internal/pkg/entity/apiserver/operationlog"
+)
+
+// archive writes operation logs to a gzip compressed JSON lines file, one operation log per line.
+type archive struct {
+	file *os.File
+	gz   *gzip.Writer
+	buf  *bufio.Writer
+}
+
+// newArchive creates an archive file named by the time in the directory.
+func newArchive(dir string, now time.Time) (*archive, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, err
+	}
+
+	name := filepath.Join(dir, fmt.Sprintf("operation-logs-%s.jsonl.gz", now.UTC().Format("20060102T150405.000Z")))
+
+	file, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o640)
+	if err != nil {
+		return nil, err
+	}
+
+	gz := gzip.NewWriter(file)
+
+	return &archive{file: file, gz: gz, buf: bufio.NewWriter(gz)}, nil
+}
+
+// Write writes the operation logs and syncs them to the disk,
+// so that they can be deleted from the database safely.
+func (a *archive) Write(items []*operationlog.OperationLog) error {
+	for _, item := range items {
+		data, err := json.Marshal(item)
+		if err != nil {
+			return err
+		}
+
+		if _, err := a.buf.Write(append(data, '\n')); err != nil {
+			return err
+		}
+	}
+
+	if err := a.buf.Flush(); err != nil {
+		return err
+	}
+
+	if err := a.gz.Flush(); err != nil {
+		return err
+	}
+
+	return a.file.Sync()
+}
+
+// Close completes the archive file.
+func (a *archive) Close() error {
+	if err := a.buf.Flush(); err != nil {
+		_ = a.file.Close()
+
+		return err
+	}
+
+	if err := a.gz.Close(); err != nil {
+		_ = a.file.Close()
+
+		return err
+	}
+
+	return a.file.Close()
+}
+
+// Name returns the file name of the archive.
+func (a *archive) Name() string {
+	return a.file.Name()
+}
@@ -0,0 +1,225 @@
+package oplog
+
+import (
+	"bufio"
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"gobackend/pkg/errors"
+	"gobackend/pkg/json"
+
+	"gobackend/internal/app/apiserver/store"
+	"gobackend/internal/pkg/code"
+	"gobackend/internal/pkg/entity/apiserver/operationlog"
+)
+
+// Export formats.
+const (
+	FormatCSV   = "csv"
+	FormatJSONL = "jsonl"
+)
+
+// exportFields are the fields exported by default, in the order of the CSV columns.
+var exportFields = []string{
+	"id",
+	"created_at",
+	"username",
+	"client_ip",
+	"req_method",
+	"req_path",
+	"req_body",
+	"req_referer",
+	"user_agent",
+	"req_time",
+	"req_latency",
+	"http_status",
+	"res_data",
+}
+
+// Exporter writes the operation logs in an export format.
+type Exporter interface {
+	// ContentType returns the MIME type of the export format.
+	ContentType() string
+
+	// Write writes the operation logs, the CSV header is written before the first ones.
+	Write(items []*operationlog.OperationLog) error
+
+	// Flush writes the buffered data to the underlying writer.
+	Flush() error
+}
+
+// NewExporter creates an exporter writing the fields, which are comma separated, of the operation
+// logs in the format to w. All the fields are exported if fields is empty. An error with code
+// ErrValidation is returned if the format or a field is not supported.
+func NewExporter(format string, fields string, w io.Writer) (Exporter, error) {
+	names := exportFields
+	if fields != "" {
+		if _, err := store.OperationLogColumns.Select(fields); err != nil {
+			return nil, err
+		}
+
+		names = strings.Split(fields, ",")
+		for i := range names {
+			names[i] = strings.TrimSpace(names[i])
+		}
+	}
+
+	switch format {
+	case FormatCSV:
+		return &csvExporter{fields: names, w: csv.NewWriter(w)}, nil
+	case FormatJSONL, "":
+		return &jsonlExporter{fields: names, all: fields == "", w: bufio.NewWriter(w)}, nil
+	default:
+		return nil, errors.WithCode(code.ErrValidation, "unsupported export format '%s'", format)
+	}
+}
+
+type csvExporter struct {
+	fields      []string
+	w           *csv.Writer
+	wroteHeader bool
+}
+
+func (e *csvExporter) ContentType() string {
+	return "text/csv; charset=utf-8"
+}
+
+func (e *csvExporter) Write(items []*operationlog.OperationLog) error {
+	if !e.wroteHeader {
+		if err := e.w.Write(e.fields); err != nil {
+			return err
+		}
+
+		e.wroteHeader = true
+	}
+
+	record := make([]string, len(e.fields))
+
+	for _, item := range items {
+		for i, field := range e.fields {
+			record[i] = csvValue(fieldValue(item, field))
+		}
+
+		if err := e.w.Write(record); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (e *csvExporter) Flush() error {
+	// An empty export still has the header.
+	if !e.wroteHeader {
+		if err := e.Write(nil); err != nil {
+			return err
+		}
+	}
+
+	e.w.Flush()
+
+	return e.w.Error()
+}
+
+type jsonlExporter struct {
+	fields []string
+	all    bool
+	w      *bufio.Writer
+}
+
+func (e *jsonlExporter) ContentType() string {
+	return "application/x-ndjson; charset=utf-8"
+}
+
+func (e *jsonlExporter) Write(items []*operationlog.OperationLog) error {
+	for _, item := range items {
+		var v interface{} = item
+
+		// Only the requested fields are exported.
+		if !e.all {
+			obj := make(map[string]interface{}, len(e.fields))
+			for _, field := range e.fields {
+				obj[field] = fieldValue(item, field)
+			}
+
+			v = obj
+		}
+
+		data, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+
+		if _, err := e.w.Write(append(data, '\n')); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (e *jsonlExporter) Flush() error {
+	return e.w.Flush()
+}
+
+// fieldValue returns the value of a field in store.OperationLogColumns.
+func fieldValue(item *operationlog.OperationLog, field string) interface{} {
+	switch field {
+	case "id":
+		return item.ID
+	case "created_at":
+		return item.CreatedAt
+	case "updated_at":
+		return item.UpdatedAt
+	case "username":
+		return item.Username
+	case "user_agent":
+		return item.UserAgent
+	case "client_ip":
+		return item.ClientIP
+	case "req_method":
+		return item.ReqMethod
+	case "req_path":
+		return item.ReqPath
+	case "req_body":
+		return item.ReqBody
+	case "req_referer":
+		return item.ReqReferer
+	case "req_time":
+		return item.ReqTime
+	case "req_latency":
+		return item.ReqLatency
+	case "http_status":
+		return item.HTTPStatus
+	case "res_data":
+		return item.ResData
+	default:
+		return nil
+	}
+}
+
+// csvValue formats a field value for CSV. The strings starting with a formula character are
+// prefixed with a single quote, so that spreadsheet applications do not evaluate them.
+func csvValue(v interface{}) string {
+	switch t := v.(type) {
+	case string:
+		if t != "" && strings.ContainsRune("=+-@\t\r", rune(t[0])) {
+			return "'" + t
+		}
+
+		return t
+	case time.Time:
+		return t.UTC().Format(time.RFC3339Nano)
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	case uint64:
+		return strconv.FormatUint(t, 10)
+	case int:
+		return strconv.Itoa(t)
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,194 @@
+// Package oplog processes the operation logs: the asynchronous pipeline, the retention janitor,
+// and the exports and statistics.
+package oplog
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/AlekSi/pointer"
+
+	"gobackend/pkg/log"
+	metav1 "gobackend/pkg/meta/v1"
+
+	"gobackend/internal/app/apiserver/store"
+	genericoptions "gobackend/internal/pkg/options"
+)
+
+// Janitor deletes the expired operation logs periodically according to the retention policy,
+// the operation logs are archived before they are deleted if an archive directory is configured.
+type Janitor struct {
+	store store.Factory
+	opts  *genericoptions.OperationLogOptions
+	now   func() time.Time
+
+	once   sync.Once
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewJanitor creates a janitor of the operation logs in the store.
+func NewJanitor(storeIns store.Factory, opts *genericoptions.OperationLogOptions) *Janitor {
+	return &Janitor{
+		store: storeIns,
+		opts:  opts,
+		now:   time.Now,
+	}
+}
+
+// Start cleans up the operation logs every cleanup interval in a goroutine until Stop is called,
+// the first cleanup starts immediately. It does nothing if the retention policy is not enabled.
+func (j *Janitor) Start() {
+	if !j.opts.RetentionEnabled() {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	j.cancel, j.done = cancel, make(chan struct{})
+
+	go func() {
+		defer close(j.done)
+
+		ticker := time.NewTicker(j.opts.CleanupInterval)
+		defer ticker.Stop()
+
+		for {
+			if n, err := j.Cleanup(ctx); err != nil && ctx.Err() == nil {
+				log.Errorf("clean up operation logs error: %s", err)
+			} else if n > 0 {
+				log.Infof("%d expired operation logs deleted", n)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop stops the janitor and waits for the running cleanup to return,
+// which is interrupted between two batches.
+func (j *Janitor) Stop() {
+	j.once.Do(func() {
+		if j.cancel == nil {
+			return
+		}
+
+		j.cancel()
+		<-j.done
+	})
+}
+
+// Cleanup deletes the expired operation logs in batches, and returns the number of them.
+func (j *Janitor) Cleanup(ctx context.Context) (deleted int, err error) {
+	selectors, err := j.expired(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var a *archive
+
+	defer func() {
+		if a == nil {
+			return
+		}
+
+		if closeErr := a.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+
+		log.Infof("%d expired operation logs archived to %s", deleted, a.Name())
+	}()
+
+	// Only the ids are needed if the operation logs are not archived.
+	fields := "id"
+	if j.opts.ArchiveDir != "" {
+		fields = ""
+	}
+
+	for _, selector := range selectors {
+		for {
+			if err := ctx.Err(); err != nil {
+				return deleted, err
+			}
+
+			list, err := j.store.OperationLogs().List(ctx, metav1.ListOptions{
+				FieldSelector: selector,
+				SortBy:        "id",
+				Fields:        fields,
+				Limit:         pointer.ToInt64(int64(j.opts.CleanupBatchSize)),
+				TotalCount:    pointer.ToBool(false),
+			})
+			if err != nil {
+				return deleted, err
+			}
+
+			if len(list.Items) == 0 {
+				break
+			}
+
+			if j.opts.ArchiveDir != "" {
+				if a == nil {
+					if a, err = newArchive(j.opts.ArchiveDir, j.now()); err != nil {
+						return deleted, err
+					}
+				}
+
+				if err := a.Write(list.Items); err != nil {
+					return deleted, err
+				}
+			}
+
+			ids := make([]uint64, 0, len(list.Items))
+			for _, item := range list.Items {
+				ids = append(ids, item.ID)
+			}
+
+			err = j.store.OperationLogs().DeleteCollection(ctx, ids, metav1.DeleteOptions{Unscoped: true})
+			if err != nil {
+				return deleted, err
+			}
+
+			deleted += len(ids)
+
+			if len(list.Items) < j.opts.CleanupBatchSize {
+				break
+			}
+		}
+	}
+
+	return deleted, nil
+}
+
+// expired returns the field selectors of the expired operation logs.
+func (j *Janitor) expired(ctx context.Context) ([]string, error) {
+	var selectors []string
+
+	if j.opts.MaxRows > 0 {
+		// The newest operation log out of the limit, it and the older ones are expired.
+		list, err := j.store.OperationLogs().List(ctx, metav1.ListOptions{
+			Fields:     "id",
+			Offset:     pointer.ToInt64(j.opts.MaxRows),
+			Limit:      pointer.ToInt64(1),
+			TotalCount: pointer.ToBool(false),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if len(list.Items) > 0 {
+			selectors = append(selectors, fmt.Sprintf("id<=%d", list.Items[0].ID))
+		}
+	}
+
+	if j.opts.MaxAge > 0 {
+		before := j.now().Add(-j.opts.MaxAge).UTC().Format(time.RFC3339Nano)
+		selectors = append(selectors, "req_time<"+before)
+	}
+
+	return selectors, nil
+}
@@ -0,0 +1,188 @@
+package oplog
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gobackend/pkg/json"
+	metav1 "gobackend/pkg/meta/v1"
+
+	"gobackend/internal/app/apiserver/store"
+	"gobackend/internal/app/apiserver/store/fake"
+	"gobackend/internal/pkg/entity/apiserver/operationlog"
+	genericoptions "gobackend/internal/pkg/options"
+)
+
+var now = time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+
+// newStore creates a store with n operation logs, the i-th one is requested i hours ago.
+func newStore(t *testing.T, n int) store.Factory {
+	t.Helper()
+
+	ds := fake.New()
+
+	for i := n - 1; i >= 0; i-- {
+		err := ds.OperationLogs().Create(context.Background(), &operationlog.OperationLog{
+			ReqPath: fmt.Sprintf("/v1/users/%d", i),
+			ReqTime: now.Add(-time.Duration(i) * time.Hour),
+		}, metav1.CreateOptions{})
+		if err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	return ds
+}
+
+func remaining(t *testing.T, ds store.Factory) []string {
+	t.Helper()
+
+	list, err := ds.OperationLogs().List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+
+	paths := make([]string, 0, len(list.Items))
+	for _, item := range list.Items {
+		paths = append(paths, item.ReqPath)
+	}
+
+	return paths
+}
+
+func TestJanitorCleanup(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    genericoptions.OperationLogOptions
+		deleted int
+		want    string
+	}{
+		{
+			name:    "max rows",
+			opts:    genericoptions.OperationLogOptions{MaxRows: 3, CleanupBatchSize: 2},
+			deleted: 4,
+			want:    "[/v1/users/0 /v1/users/1 /v1/users/2]",
+		},
+		{
+			name:    "max age",
+			opts:    genericoptions.OperationLogOptions{MaxAge: 90 * time.Minute, CleanupBatchSize: 10},
+			deleted: 5,
+			want:    "[/v1/users/0 /v1/users/1]",
+		},
+		{
+			name:    "both",
+			opts:    genericoptions.OperationLogOptions{MaxRows: 4, MaxAge: 150 * time.Minute, CleanupBatchSize: 1},
+			deleted: 4,
+			want:    "[/v1/users/0 /v1/users/1 /v1/users/2]",
+		},
+		{
+			name:    "nothing expired",
+			opts:    genericoptions.OperationLogOptions{MaxRows: 10, CleanupBatchSize: 10},
+			deleted: 0,
+			want:    "[/v1/users/0 /v1/users/1 /v1/users/2 /v1/users/3 /v1/users/4 /v1/users/5 /v1/users/6]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ds := newStore(t, 7)
+
+			opts := tt.opts
+			j := NewJanitor(ds, &opts)
+			j.now = func() time.Time { return now }
+
+			deleted, err := j.Cleanup(context.Background())
+			if err != nil {
+				t.Fatalf("Cleanup() error = %v", err)
+			}
+
+			if deleted != tt.deleted {
+				t.Errorf("Cleanup() = %d, want %d", deleted, tt.deleted)
+			}
+
+			if got := fmt.Sprint(remaining(t, ds)); got != tt.want {
+				t.Errorf("remaining = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJanitorArchive(t *testing.T) {
+	ds := newStore(t, 5)
+	dir := t.TempDir()
+
+	j := NewJanitor(ds, &genericoptions.OperationLogOptions{MaxRows: 2, CleanupBatchSize: 2, ArchiveDir: dir})
+	j.now = func() time.Time { return now }
+
+	if _, err := j.Cleanup(context.Background()); err != nil {
+		t.Fatalf("Cleanup() error = %v", err)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "operation-logs-*.jsonl.gz"))
+	if len(files) != 1 {
+		t.Fatalf("archive files = %v, want one file", files)
+	}
+
+	f, err := os.Open(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var paths []string
+
+	scanner := bufio.NewScanner(gz)
+	for scanner.Scan() {
+		var item operationlog.OperationLog
+		if err := json.Unmarshal(scanner.Bytes(), &item); err != nil {
+			t.Fatalf("invalid line %q: %v", scanner.Text(), err)
+		}
+
+		paths = append(paths, item.ReqPath)
+	}
+
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := fmt.Sprint(paths), "[/v1/users/4 /v1/users/3 /v1/users/2]"; got != want {
+		t.Errorf("archived = %s, want %s", got, want)
+	}
+}
+
+func TestJanitorStop(t *testing.T) {
+	j := NewJanitor(newStore(t, 1), &genericoptions.OperationLogOptions{
+		MaxRows:          1,
+		CleanupInterval:  time.Millisecond,
+		CleanupBatchSize: 1,
+	})
+	j.Start()
+
+	stopped := make(chan struct{})
+
+	go func() {
+		j.Stop()
+		j.Stop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Stop() did not return")
+	}
+
+	// Stop does nothing if the janitor is not started.
+	NewJanitor(newStore(t, 1), genericoptions.NewOperationLogOptions()).Stop()
+}
@@ -0,0 +1,81 @@
+package oplog
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	metricsNamespace = "gobackend"
+	metricsSubsystem = "operation_log"
+)
+
+// The reasons an operation log is dropped.
+const (
+	dropQueueFull = "queue_full"
+	dropClosed    = "closed"
+	dropTimeout   = "drain_timeout"
+)
+
+var (
+	queueLength = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "queue_length",
+		Help:      "The number of the operation logs waiting in the queue.",
+	})
+
+	queueCapacity = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "queue_capacity",
+		Help:      "The maximum number of the operation logs in the queue.",
+	})
+
+	enqueuedTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "enqueued_total",
+		Help:      "The number of the operation logs put into the queue.",
+	})
+
+	droppedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "dropped_total",
+		Help:      "The number of the operation logs dropped before they are written, by reason.",
+	}, []string{"reason"})
+
+	writtenTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "written_total",
+		Help:      "The number of the operation logs written, by sink.",
+	}, []string{"sink"})
+
+	writeErrorsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "write_errors_total",
+		Help:      "The number of the operation logs failed to be written, by sink.",
+	}, []string{"sink"})
+
+	writeDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "write_duration_seconds",
+		Help:      "The time spent writing a batch of operation logs, by sink.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"sink"})
+)
+
+func init() {
+	prometheus.MustRegister(
+		queueLength,
+		queueCapacity,
+		enqueuedTotal,
+		droppedTotal,
+		writtenTotal,
+		writeErrorsTotal,
+		writeDuration,
+	)
+}
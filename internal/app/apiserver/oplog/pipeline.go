@@ -0,0 +1,213 @@
+package oplog
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"gobackend/pkg/log"
+
+	"gobackend/internal/pkg/entity/apiserver/operationlog"
+	genericoptions "gobackend/internal/pkg/options"
+)
+
+// Pipeline writes the operation logs to the sinks asynchronously. The operation logs are put into
+// a bounded queue, and written in batches when a batch is full or the flush interval elapses.
+// The new operation logs are dropped instead of blocking the requests when the queue is full.
+type Pipeline struct {
+	sinks []Sink
+	opts  *genericoptions.OperationLogOptions
+	queue chan *operationlog.OperationLog
+
+	// lock guards closed, so that no operation log is put into the closed queue.
+	lock   sync.RWMutex
+	closed bool
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+	once   sync.Once
+}
+
+// NewPipeline creates a pipeline writing the operation logs to the sinks.
+func NewPipeline(sinks []Sink, opts *genericoptions.OperationLogOptions) *Pipeline {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	queueCapacity.Set(float64(opts.QueueSize))
+
+	return &Pipeline{
+		sinks:  sinks,
+		opts:   opts,
+		queue:  make(chan *operationlog.OperationLog, opts.QueueSize),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Start writes the queued operation logs in a goroutine until Close is called.
+func (p *Pipeline) Start() {
+	p.done = make(chan struct{})
+
+	go func() {
+		defer close(p.done)
+
+		ticker := time.NewTicker(p.opts.FlushInterval)
+		defer ticker.Stop()
+
+		batch := make([]*operationlog.OperationLog, 0, p.opts.BatchSize)
+
+		for {
+			select {
+			case item, ok := <-p.queue:
+				if !ok {
+					p.flush(batch)
+
+					return
+				}
+
+				queueLength.Dec()
+
+				batch = append(batch, item)
+				if len(batch) < p.opts.BatchSize {
+					continue
+				}
+			case <-ticker.C:
+				if len(batch) == 0 {
+					continue
+				}
+			}
+
+			p.flush(batch)
+			batch = make([]*operationlog.OperationLog, 0, p.opts.BatchSize)
+		}
+	}()
+}
+
+// Enqueue puts the operation log into the queue without blocking. It returns false if the
+// operation log is dropped because the queue is full or the pipeline is closed.
+func (p *Pipeline) Enqueue(item *operationlog.OperationLog) bool {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	if p.closed {
+		droppedTotal.WithLabelValues(dropClosed).Inc()
+
+		return false
+	}
+
+	queueLength.Inc()
+
+	select {
+	case p.queue <- item:
+		enqueuedTotal.Inc()
+
+		return true
+	default:
+		queueLength.Dec()
+		droppedTotal.WithLabelValues(dropQueueFull).Inc()
+
+		return false
+	}
+}
+
+// Check reports whether the operation logs are accepted, it fails when the pipeline is closed
+// or the queue is full.
+func (p *Pipeline) Check(ctx context.Context) error {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	if p.closed {
+		return fmt.Errorf("the pipeline is closed")
+	}
+
+	if len(p.queue) == cap(p.queue) {
+		return fmt.Errorf("the queue is full, %d operation logs are waiting to be written", len(p.queue))
+	}
+
+	return nil
+}
+
+// Close stops accepting operation logs, waits for the queued ones to be written,
+// and closes the sinks. The writes are canceled and the rest of the queued operation logs
+// are dropped when the drain timeout elapses. It is safe to call Close more than once.
+func (p *Pipeline) Close() error {
+	var err error
+
+	p.once.Do(func() {
+		p.lock.Lock()
+		p.closed = true
+		close(p.queue)
+		p.lock.Unlock()
+
+		if p.done != nil {
+			err = p.drain()
+		}
+
+		p.cancel()
+
+		for _, sink := range p.sinks {
+			if closeErr := sink.Close(); closeErr != nil {
+				log.Errorf("close operation log sink %s error: %s", sink.Name(), closeErr)
+
+				if err == nil {
+					err = closeErr
+				}
+			}
+		}
+	})
+
+	return err
+}
+
+// drain waits for the queued operation logs to be written.
+func (p *Pipeline) drain() error {
+	if p.opts.DrainTimeout == 0 {
+		<-p.done
+
+		return nil
+	}
+
+	timer := time.NewTimer(p.opts.DrainTimeout)
+	defer timer.Stop()
+
+	select {
+	case <-p.done:
+		return nil
+	case <-timer.C:
+	}
+
+	// Interrupt the running write, the rest are dropped by flush.
+	p.cancel()
+	<-p.done
+
+	return fmt.Errorf("drain operation logs timed out after %s", p.opts.DrainTimeout)
+}
+
+// flush writes the batch to every sink, a failed sink does not stop the others.
+func (p *Pipeline) flush(batch []*operationlog.OperationLog) {
+	if len(batch) == 0 {
+		return
+	}
+
+	if p.ctx.Err() != nil {
+		droppedTotal.WithLabelValues(dropTimeout).Add(float64(len(batch)))
+
+		return
+	}
+
+	for _, sink := range p.sinks {
+		start := time.Now()
+		err := sink.Write(p.ctx, batch)
+		writeDuration.WithLabelValues(sink.Name()).Observe(time.Since(start).Seconds())
+
+		if err != nil {
+			writeErrorsTotal.WithLabelValues(sink.Name()).Add(float64(len(batch)))
+			log.Errorf("write %d operation logs to sink %s error: %s", len(batch), sink.Name(), err)
+
+			continue
+		}
+
+		writtenTotal.WithLabelValues(sink.Name()).Add(float64(len(batch)))
+	}
+}
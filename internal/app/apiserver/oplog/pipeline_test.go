@@ -0,0 +1,227 @@
+package oplog
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"gobackend/internal/pkg/entity/apiserver/operationlog"
+	genericoptions "gobackend/internal/pkg/options"
+)
+
+// recordSink records the request paths of the batches written to it.
+type recordSink struct {
+	lock    sync.Mutex
+	batches [][]string
+	closed  bool
+
+	// block blocks the writes until the context is done if it is true.
+	block bool
+}
+
+func (s *recordSink) Name() string {
+	return "record"
+}
+
+func (s *recordSink) Write(ctx context.Context, items []*operationlog.OperationLog) error {
+	if s.block {
+		<-ctx.Done()
+
+		return ctx.Err()
+	}
+
+	paths := make([]string, 0, len(items))
+	for _, item := range items {
+		paths = append(paths, item.ReqPath)
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.batches = append(s.batches, paths)
+
+	return nil
+}
+
+func (s *recordSink) Close() error {
+	s.closed = true
+
+	return nil
+}
+
+func (s *recordSink) String() string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return fmt.Sprint(s.batches)
+}
+
+func newPipelineOptions() *genericoptions.OperationLogOptions {
+	opts := genericoptions.NewOperationLogOptions()
+	opts.QueueSize = 10
+	opts.BatchSize = 2
+	opts.FlushInterval = time.Hour
+
+	return opts
+}
+
+func enqueue(p *Pipeline, n int) (accepted int) {
+	for i := 0; i < n; i++ {
+		if p.Enqueue(&operationlog.OperationLog{ReqPath: fmt.Sprintf("/%d", i)}) {
+			accepted++
+		}
+	}
+
+	return accepted
+}
+
+func TestPipelineBatch(t *testing.T) {
+	sink := &recordSink{}
+	p := NewPipeline([]Sink{sink}, newPipelineOptions())
+	p.Start()
+
+	if got := enqueue(p, 5); got != 5 {
+		t.Fatalf("Enqueue() accepted %d, want 5", got)
+	}
+
+	if err := p.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	if got, want := sink.String(), "[[/0 /1] [/2 /3] [/4]]"; got != want {
+		t.Errorf("batches = %s, want %s", got, want)
+	}
+
+	if !sink.closed {
+		t.Error("sink is not closed")
+	}
+
+	if p.Enqueue(&operationlog.OperationLog{}) {
+		t.Error("Enqueue() after Close() = true, want false")
+	}
+
+	if err := p.Close(); err != nil {
+		t.Errorf("second Close() error = %v", err)
+	}
+}
+
+func TestPipelineFlushInterval(t *testing.T) {
+	sink := &recordSink{}
+	opts := newPipelineOptions()
+	opts.FlushInterval = 10 * time.Millisecond
+
+	p := NewPipeline([]Sink{sink}, opts)
+	p.Start()
+
+	defer p.Close()
+
+	enqueue(p, 1)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for sink.String() != "[[/0]]" {
+		if time.Now().After(deadline) {
+			t.Fatalf("batches = %s, want [[/0]]", sink)
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestPipelineQueueFull(t *testing.T) {
+	sink := &recordSink{}
+	opts := newPipelineOptions()
+	opts.QueueSize = 3
+
+	// The pipeline is not started, nothing is taken from the queue.
+	p := NewPipeline([]Sink{sink}, opts)
+
+	if err := p.Check(context.Background()); err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+
+	if got := enqueue(p, 5); got != 3 {
+		t.Fatalf("Enqueue() accepted %d, want 3", got)
+	}
+
+	if err := p.Check(context.Background()); err == nil {
+		t.Error("Check() of the full queue error = nil")
+	}
+
+	p.Start()
+
+	if err := p.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	if err := p.Check(context.Background()); err == nil {
+		t.Error("Check() after Close() error = nil")
+	}
+
+	if got, want := sink.String(), "[[/0 /1] [/2]]"; got != want {
+		t.Errorf("batches = %s, want %s", got, want)
+	}
+}
+
+func TestPipelineDrainTimeout(t *testing.T) {
+	sink := &recordSink{block: true}
+	opts := newPipelineOptions()
+	opts.DrainTimeout = 10 * time.Millisecond
+
+	p := NewPipeline([]Sink{sink}, opts)
+	p.Start()
+
+	enqueue(p, 5)
+
+	closed := make(chan error)
+
+	go func() {
+		closed <- p.Close()
+	}()
+
+	select {
+	case err := <-closed:
+		if err == nil {
+			t.Error("Close() error = nil, want a timeout error")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close() did not return")
+	}
+
+	if !sink.closed {
+		t.Error("sink is not closed")
+	}
+}
+
+func TestWriterSink(t *testing.T) {
+	var buf bytes.Buffer
+
+	sink := NewWriterSink("buffer", &buf)
+
+	err := sink.Write(context.Background(), []*operationlog.OperationLog{{ReqPath: "/a"}, {ReqPath: "/b"}})
+	if err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], `"/a"`) || !strings.Contains(lines[1], `"/b"`) {
+		t.Errorf("output = %q, want two JSON lines", buf.String())
+	}
+}
+
+func TestDatabaseSink(t *testing.T) {
+	ds := newStore(t, 0)
+	sink := NewDatabaseSink(ds)
+
+	err := sink.Write(context.Background(), []*operationlog.OperationLog{{ReqPath: "/a"}, {ReqPath: "/b"}})
+	if err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	if got, want := fmt.Sprint(remaining(t, ds)), "[/b /a]"; got != want {
+		t.Errorf("stored = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,72 @@
+package oplog
+
+import (
+	"context"
+
+	"github.com/AlekSi/pointer"
+
+	"gobackend/pkg/errors"
+	metav1 "gobackend/pkg/meta/v1"
+
+	"gobackend/internal/app/apiserver/store"
+	"gobackend/internal/pkg/code"
+	"gobackend/internal/pkg/entity/apiserver/operationlog"
+)
+
+// scanPageSize is the number of the operation logs listed at a time by Scan.
+const scanPageSize = 500
+
+// Scan lists the operation logs matching the list options page by page with continue tokens,
+// and calls fn with every page until fn returns an error. The field selector, sort_by and fields
+// options are honoured, limit caps the total number of the operation logs, and offset and
+// continue are not supported. The options are validated before fn is called.
+func Scan(
+	ctx context.Context,
+	storeIns store.Factory,
+	opts metav1.ListOptions,
+	fn func(items []*operationlog.OperationLog) error,
+) error {
+	if (opts.Offset != nil && *opts.Offset != 0) || opts.Continue != "" {
+		return errors.WithCode(code.ErrValidation, "offset and continue are not supported")
+	}
+
+	remaining := int64(-1)
+	if opts.Limit != nil && *opts.Limit > 0 {
+		remaining = *opts.Limit
+	}
+
+	opts.Offset = nil
+	opts.TotalCount = pointer.ToBool(false)
+
+	for remaining != 0 {
+		size := int64(scanPageSize)
+		if remaining > 0 && remaining < size {
+			size = remaining
+		}
+
+		opts.Limit = pointer.ToInt64(size)
+
+		list, err := storeIns.OperationLogs().List(ctx, opts)
+		if err != nil {
+			return err
+		}
+
+		if len(list.Items) > 0 {
+			if err := fn(list.Items); err != nil {
+				return err
+			}
+		}
+
+		if list.Continue == "" {
+			return nil
+		}
+
+		if remaining > 0 {
+			remaining -= int64(len(list.Items))
+		}
+
+		opts.Continue = list.Continue
+	}
+
+	return nil
+}
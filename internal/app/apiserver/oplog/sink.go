@@ -0,0 +1,142 @@
+package oplog
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"gobackend/pkg/json"
+	metav1 "gobackend/pkg/meta/v1"
+
+	"gobackend/internal/app/apiserver/store"
+	"gobackend/internal/pkg/entity/apiserver/operationlog"
+	genericoptions "gobackend/internal/pkg/options"
+)
+
+// Sink is where the operation logs are written to by the pipeline.
+type Sink interface {
+	// Name returns the name of the sink, it is used in the logs and the metrics.
+	Name() string
+
+	// Write writes a batch of operation logs. It is never called concurrently.
+	Write(ctx context.Context, items []*operationlog.OperationLog) error
+
+	// Close releases the resources of the sink, Write is not called after it.
+	Close() error
+}
+
+// NewSinks creates the sinks by the names in the options.
+func NewSinks(storeIns store.Factory, opts *genericoptions.OperationLogOptions) ([]Sink, error) {
+	sinks := make([]Sink, 0, len(opts.Sinks))
+
+	for _, name := range opts.Sinks {
+		var sink Sink
+
+		switch name {
+		case genericoptions.SinkDatabase:
+			sink = NewDatabaseSink(storeIns)
+		case genericoptions.SinkFile:
+			var err error
+			if sink, err = NewFileSink(opts.SinkFile); err != nil {
+				for _, s := range sinks {
+					_ = s.Close()
+				}
+
+				return nil, err
+			}
+		case genericoptions.SinkStdout:
+			sink = NewWriterSink(genericoptions.SinkStdout, os.Stdout)
+		default:
+			return nil, fmt.Errorf("unsupported operation log sink: %s", name)
+		}
+
+		sinks = append(sinks, sink)
+	}
+
+	return sinks, nil
+}
+
+// databaseSink inserts the operation logs into the store.
+type databaseSink struct {
+	store store.Factory
+}
+
+// NewDatabaseSink creates a sink which inserts a batch of operation logs into the store in a statement.
+func NewDatabaseSink(storeIns store.Factory) Sink {
+	return &databaseSink{store: storeIns}
+}
+
+func (s *databaseSink) Name() string {
+	return genericoptions.SinkDatabase
+}
+
+func (s *databaseSink) Write(ctx context.Context, items []*operationlog.OperationLog) error {
+	return s.store.OperationLogs().CreateCollection(ctx, items, metav1.CreateOptions{})
+}
+
+// Close does nothing, the store is closed by its owner.
+func (s *databaseSink) Close() error {
+	return nil
+}
+
+// writerSink writes the operation logs to a writer as JSON lines, one operation log per line.
+type writerSink struct {
+	name string
+	w    io.Writer
+	buf  bytes.Buffer
+}
+
+// NewWriterSink creates a sink which writes the operation logs to the writer as JSON lines.
+// The writer is closed with the sink if it is an io.Closer other than the standard output.
+func NewWriterSink(name string, w io.Writer) Sink {
+	return &writerSink{name: name, w: w}
+}
+
+// NewFileSink creates a sink which appends the operation logs to the file as JSON lines.
+func NewFileSink(path string) (Sink, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o640)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewWriterSink(genericoptions.SinkFile, file), nil
+}
+
+func (s *writerSink) Name() string {
+	return s.name
+}
+
+// Write writes the batch in a call, so that the lines of a batch are not interleaved
+// with the output of the others.
+func (s *writerSink) Write(ctx context.Context, items []*operationlog.OperationLog) error {
+	s.buf.Reset()
+
+	for _, item := range items {
+		data, err := json.Marshal(item)
+		if err != nil {
+			return err
+		}
+
+		s.buf.Write(data)
+		s.buf.WriteByte('\n')
+	}
+
+	_, err := s.w.Write(s.buf.Bytes())
+
+	return err
+}
+
+func (s *writerSink) Close() error {
+	if c, ok := s.w.(io.Closer); ok && s.w != os.Stdout {
+		return c.Close()
+	}
+
+	return nil
+}
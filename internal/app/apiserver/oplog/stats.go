@@ -0,0 +1,126 @@
+package oplog
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+
+	"gobackend/pkg/errors"
+	metav1 "gobackend/pkg/meta/v1"
+
+	"gobackend/internal/app/apiserver/store"
+	"gobackend/internal/pkg/code"
+	"gobackend/internal/pkg/entity/apiserver/operationlog"
+)
+
+// Stats groups the operation logs matching the field selector of the list options by
+// the comma separated dimensions, e.g. `username,day`, and returns the statistics of every group.
+// All the operation logs are in a group if groupBy is empty. An error with code ErrValidation is
+// returned if a dimension is not supported, see store.OperationLogDimensions.
+// The groups are aggregated by the store, the 95th percentiles of the latencies are read from
+// the histograms of the aggregates, they are at most 25% greater than the exact ones.
+func Stats(
+	ctx context.Context,
+	storeIns store.Factory,
+	opts metav1.ListOptions,
+	groupBy string,
+) (*operationlog.Stats, error) {
+	names := []string{}
+
+	if groupBy != "" {
+		for _, name := range strings.Split(groupBy, ",") {
+			name = strings.TrimSpace(name)
+
+			if _, ok := store.OperationLogDimensions[name]; !ok {
+				return nil, errors.WithCode(code.ErrValidation, "unsupported dimension '%s' in group_by", name)
+			}
+
+			for _, n := range names {
+				if n == name {
+					return nil, errors.WithCode(code.ErrValidation, "duplicate dimension '%s' in group_by", name)
+				}
+			}
+
+			names = append(names, name)
+		}
+	}
+
+	groups, err := storeIns.OperationLogs().Aggregate(ctx, opts, names)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		return less(groups[i], groups[j])
+	})
+
+	stats := &operationlog.Stats{
+		GroupBy: names,
+		Items:   make([]*operationlog.StatsItem, 0, len(groups)),
+	}
+
+	for _, g := range groups {
+		stats.TotalCount += g.Count
+		stats.Items = append(stats.Items, statsItem(names, g))
+	}
+
+	return stats, nil
+}
+
+// less orders the groups by the number of the operation logs in descending order,
+// and by the values of the dimensions.
+func less(g, other *operationlog.Aggregate) bool {
+	if g.Count != other.Count {
+		return g.Count > other.Count
+	}
+
+	for i := range g.Group {
+		if g.Group[i] != other.Group[i] {
+			return g.Group[i] < other.Group[i]
+		}
+	}
+
+	return false
+}
+
+func statsItem(names []string, g *operationlog.Aggregate) *operationlog.StatsItem {
+	item := &operationlog.StatsItem{
+		Group:      make(map[string]string, len(names)),
+		Count:      g.Count,
+		ErrorCount: g.ErrorCount,
+		ErrorRate:  float64(g.ErrorCount) / float64(g.Count),
+		AvgLatency: g.SumLatency / float64(g.Count),
+		P95Latency: percentile(g, 0.95),
+		MaxLatency: g.MaxLatency,
+	}
+
+	for i, name := range names {
+		item.Group[name] = g.Group[i]
+	}
+
+	return item
+}
+
+// percentile returns the upper bound of the bucket of the nearest-rank percentile in the histogram
+// of the latencies, or the max latency if it is less, e.g. if the latencies of the bucket are the same.
+func percentile(g *operationlog.Aggregate, p float64) float64 {
+	rank := int64(math.Ceil(p * float64(g.Count)))
+
+	var n int64
+
+	for i, count := range g.Latencies {
+		n += count
+		if n < rank {
+			continue
+		}
+
+		if i < len(store.LatencyBuckets) && store.LatencyBuckets[i] < g.MaxLatency {
+			return store.LatencyBuckets[i]
+		}
+
+		break
+	}
+
+	return g.MaxLatency
+}
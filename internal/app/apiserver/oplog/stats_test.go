@@ -0,0 +1,256 @@
+package oplog
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AlekSi/pointer"
+
+	metav1 "gobackend/pkg/meta/v1"
+
+	"gobackend/internal/app/apiserver/store"
+	"gobackend/internal/app/apiserver/store/fake"
+	"gobackend/internal/pkg/entity/apiserver/operationlog"
+)
+
+// newStatsStore creates a store with the operation logs of two users on two days.
+func newStatsStore(t *testing.T) store.Factory {
+	t.Helper()
+
+	ds := fake.New()
+
+	items := []*operationlog.OperationLog{
+		{Username: "alice", ReqPath: "/v1/users?dry_run=All", HTTPStatus: 200, ReqLatency: 0.1, ReqTime: now},
+		{Username: "alice", ReqPath: "/v1/users", HTTPStatus: 400, ReqLatency: 0.3, ReqTime: now},
+		{Username: "alice", ReqPath: "/v1/secrets", HTTPStatus: 200, ReqLatency: 0.2, ReqTime: now.Add(-24 * time.Hour)},
+		{Username: "bob", ReqPath: "=cmd|' /C calc'!A0", HTTPStatus: 500, ReqLatency: 1, ReqTime: now},
+	}
+
+	if err := ds.OperationLogs().CreateCollection(context.Background(), items, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("CreateCollection() error = %v", err)
+	}
+
+	return ds
+}
+
+func TestScan(t *testing.T) {
+	ds := newStore(t, 7)
+
+	var sizes []int
+
+	err := Scan(context.Background(), ds, metav1.ListOptions{Limit: pointer.ToInt64(1200)},
+		func(items []*operationlog.OperationLog) error {
+			sizes = append(sizes, len(items))
+
+			return nil
+		})
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+
+	if got, want := fmt.Sprint(sizes), "[7]"; got != want {
+		t.Errorf("pages = %s, want %s", got, want)
+	}
+
+	err = Scan(context.Background(), ds, metav1.ListOptions{Offset: pointer.ToInt64(1)},
+		func([]*operationlog.OperationLog) error { return nil })
+	if err == nil {
+		t.Error("Scan() with offset error = nil")
+	}
+}
+
+func TestScanPages(t *testing.T) {
+	ds := newStore(t, scanPageSize+3)
+
+	var sizes []int
+
+	scan := func(limit int64) string {
+		sizes = nil
+
+		opts := metav1.ListOptions{}
+		if limit > 0 {
+			opts.Limit = pointer.ToInt64(limit)
+		}
+
+		err := Scan(context.Background(), ds, opts, func(items []*operationlog.OperationLog) error {
+			sizes = append(sizes, len(items))
+
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Scan() error = %v", err)
+		}
+
+		return fmt.Sprint(sizes)
+	}
+
+	if got, want := scan(0), fmt.Sprintf("[%d 3]", scanPageSize); got != want {
+		t.Errorf("pages = %s, want %s", got, want)
+	}
+
+	if got, want := scan(scanPageSize+1), fmt.Sprintf("[%d 1]", scanPageSize); got != want {
+		t.Errorf("pages with limit = %s, want %s", got, want)
+	}
+}
+
+func TestExporter(t *testing.T) {
+	ds := newStatsStore(t)
+
+	tests := []struct {
+		name    string
+		format  string
+		fields  string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "csv",
+			format: FormatCSV,
+			fields: "id,username,req_path,http_status,req_latency",
+			want: "id,username,req_path,http_status,req_latency\n" +
+				"1,alice,/v1/users?dry_run=All,200,0.1\n" +
+				"2,alice,/v1/users,400,0.3\n" +
+				"3,alice,/v1/secrets,200,0.2\n" +
+				"4,bob,'=cmd|' /C calc'!A0,500,1\n",
+		},
+		{
+			name:   "jsonl",
+			format: FormatJSONL,
+			fields: "username,http_status",
+			want: `{"http_status":200,"username":"alice"}` + "\n" +
+				`{"http_status":400,"username":"alice"}` + "\n" +
+				`{"http_status":200,"username":"alice"}` + "\n" +
+				`{"http_status":500,"username":"bob"}` + "\n",
+		},
+		{name: "unknown format", format: "xml", wantErr: true},
+		{name: "unknown field", format: FormatCSV, fields: "password", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			e, err := NewExporter(tt.format, tt.fields, &buf)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewExporter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			err = Scan(context.Background(), ds, metav1.ListOptions{SortBy: "id"}, e.Write)
+			if err != nil {
+				t.Fatalf("Scan() error = %v", err)
+			}
+
+			if err := e.Flush(); err != nil {
+				t.Fatalf("Flush() error = %v", err)
+			}
+
+			if got := buf.String(); got != tt.want {
+				t.Errorf("export =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+
+	// An empty CSV export has the header only.
+	var buf bytes.Buffer
+
+	e, _ := NewExporter(FormatCSV, "id,username", &buf)
+	if err := e.Flush(); err != nil || buf.String() != "id,username\n" {
+		t.Errorf("empty export = %q, %v", buf.String(), err)
+	}
+}
+
+func TestStats(t *testing.T) {
+	ds := newStatsStore(t)
+
+	format := func(s *operationlog.Stats) string {
+		items := make([]string, 0, len(s.Items))
+		for _, item := range s.Items {
+			items = append(items, fmt.Sprintf("%v count=%d errors=%d rate=%.2f avg=%.2f p95=%.2f max=%.2f",
+				item.Group, item.Count, item.ErrorCount, item.ErrorRate,
+				item.AvgLatency, item.P95Latency, item.MaxLatency))
+		}
+
+		return strings.Join(items, "\n")
+	}
+
+	tests := []struct {
+		name     string
+		groupBy  string
+		selector string
+		want     string
+		wantErr  bool
+	}{
+		{
+			name: "all",
+			want: "map[] count=4 errors=2 rate=0.50 avg=0.40 p95=1.00 max=1.00",
+		},
+		{
+			name:    "user",
+			groupBy: "username",
+			want: "map[username:alice] count=3 errors=1 rate=0.33 avg=0.20 p95=0.30 max=0.30\n" +
+				"map[username:bob] count=1 errors=1 rate=1.00 avg=1.00 p95=1.00 max=1.00",
+		},
+		{
+			name:     "path and day with selector",
+			groupBy:  "req_path, day",
+			selector: "username=alice",
+			want: "map[day:2021-06-01 req_path:/v1/users] count=2 errors=1 rate=0.50 avg=0.20 p95=0.30 max=0.30\n" +
+				"map[day:2021-05-31 req_path:/v1/secrets] count=1 errors=0 rate=0.00 avg=0.20 p95=0.20 max=0.20",
+		},
+		{name: "unknown dimension", groupBy: "password", wantErr: true},
+		{name: "duplicate dimension", groupBy: "day,day", wantErr: true},
+		{name: "invalid selector", selector: "password=x", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats, err := Stats(context.Background(), ds, metav1.ListOptions{FieldSelector: tt.selector}, tt.groupBy)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Stats() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			if got := format(stats); got != tt.want {
+				t.Errorf("Stats() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStatsPercentile(t *testing.T) {
+	ds := fake.New()
+
+	// The latencies are 10ms, 20ms, ..., 1s, the exact 95th percentile is 950ms.
+	items := make([]*operationlog.OperationLog, 0, 100)
+	for i := 1; i <= 100; i++ {
+		items = append(items, &operationlog.OperationLog{HTTPStatus: 200, ReqLatency: float64(i) / 100, ReqTime: now})
+	}
+
+	if err := ds.OperationLogs().CreateCollection(context.Background(), items, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("CreateCollection() error = %v", err)
+	}
+
+	stats, err := Stats(context.Background(), ds, metav1.ListOptions{}, "")
+	if err != nil {
+		t.Fatalf("Stats() error = %v", err)
+	}
+
+	if got := stats.Items[0].P95Latency; got < 0.95 || got > 0.95*1.25 {
+		t.Errorf("P95Latency = %v, want in [0.95, %v]", got, 0.95*1.25)
+	}
+
+	if got := stats.Items[0].MaxLatency; got != 1 {
+		t.Errorf("MaxLatency = %v, want 1", got)
+	}
+}
@@ -11,12 +11,19 @@ import (
 
 // Options ...
 type Options struct {
-	GenericServerRun *genericoptions.ServerRunOptions       `json:"server"   mapstructure:"server"`
-	InsecureServing  *genericoptions.InsecureServingOptions `json:"insecure" mapstructure:"insecure"`
-	SecureServing    *genericoptions.SecureServingOptions   `json:"secure"   mapstructure:"secure"`
-	MySQL            *genericoptions.MySQLOptions           `json:"mysql"    mapstructure:"mysql"`
-	Feature          *genericoptions.FeatureOptions         `json:"feature"  mapstructure:"feature"`
-	Log              *genericoptions.LogOptions             `json:"log"      mapstructure:"log"`
+	GenericServerRun *genericoptions.ServerRunOptions       `json:"server"        mapstructure:"server"`
+	InsecureServing  *genericoptions.InsecureServingOptions `json:"insecure"      mapstructure:"insecure"`
+	SecureServing    *genericoptions.SecureServingOptions   `json:"secure"        mapstructure:"secure"`
+	GRPC             *genericoptions.GRPCOptions            `json:"grpc"          mapstructure:"grpc"`
+	MySQL            *genericoptions.MySQLOptions           `json:"mysql"         mapstructure:"mysql"`
+	Redis            *genericoptions.RedisOptions           `json:"redis"         mapstructure:"redis"`
+	Feature          *genericoptions.FeatureOptions         `json:"feature"       mapstructure:"feature"`
+	OperationLog     *genericoptions.OperationLogOptions    `json:"operation-log" mapstructure:"operation-log"`
+	Redaction        *genericoptions.RedactionOptions       `json:"redaction"     mapstructure:"redaction"`
+	RateLimit        *genericoptions.RateLimitOptions       `json:"rate-limit"    mapstructure:"rate-limit"`
+	Cors             *genericoptions.CorsOptions            `json:"cors"          mapstructure:"cors"`
+	Auth             *genericoptions.AuthOptions            `json:"auth"          mapstructure:"auth"`
+	Log              *genericoptions.LogOptions             `json:"log"           mapstructure:"log"`
 }
 
 // New creates a new Options object with default parameters.
@@ -25,8 +32,15 @@ func New() *Options {
 		GenericServerRun: genericoptions.NewServerRunOptions(),
 		InsecureServing:  genericoptions.NewInsecureServingOptions(),
 		SecureServing:    genericoptions.NewSecureServingOptions(),
+		GRPC:             genericoptions.NewGRPCOptions(),
 		MySQL:            genericoptions.NewMySQLOptions(),
+		Redis:            genericoptions.NewRedisOptions(),
 		Feature:          genericoptions.NewFeatureOptions(),
+		OperationLog:     genericoptions.NewOperationLogOptions(),
+		Redaction:        genericoptions.NewRedactionOptions(),
+		RateLimit:        genericoptions.NewRateLimitOptions(),
+		Cors:             genericoptions.NewCorsOptions(),
+		Auth:             genericoptions.NewAuthOptions(),
 		Log:              genericoptions.NewLogOptions(),
 	}
 
@@ -47,10 +61,22 @@ func (o *Options) ApplyTo(c *server.Config) (lastErr error) {
 		return
 	}
 
+	if lastErr = o.GRPC.ApplyTo(c); lastErr != nil {
+		return
+	}
+
 	if lastErr = o.Feature.ApplyTo(c); lastErr != nil {
 		return
 	}
 
+	if lastErr = o.Redaction.ApplyTo(c); lastErr != nil {
+		return
+	}
+
+	if lastErr = o.Cors.ApplyTo(c); lastErr != nil {
+		return
+	}
+
 	return nil
 }
 
@@ -59,8 +85,15 @@ func (o *Options) Flags() (fss cliflag.NamedFlagSets) {
 	o.GenericServerRun.AddFlags(fss.FlagSet("generic"))
 	o.InsecureServing.AddFlags(fss.FlagSet("insecure serving"))
 	o.SecureServing.AddFlags(fss.FlagSet("secure serving"))
+	o.GRPC.AddFlags(fss.FlagSet("grpc"))
 	o.MySQL.AddFlags(fss.FlagSet("mysql"))
+	o.Redis.AddFlags(fss.FlagSet("redis"))
 	o.Feature.AddFlags(fss.FlagSet("features"))
+	o.OperationLog.AddFlags(fss.FlagSet("operation log"))
+	o.Redaction.AddFlags(fss.FlagSet("redaction"))
+	o.RateLimit.AddFlags(fss.FlagSet("rate limit"))
+	o.Cors.AddFlags(fss.FlagSet("cors"))
+	o.Auth.AddFlags(fss.FlagSet("auth"))
 	o.Log.AddFlagsTo(fss.FlagSet("logs"))
 
 	return fss
@@ -84,8 +117,15 @@ func (o *Options) Validate() []error {
 	errs = append(errs, o.GenericServerRun.Validate()...)
 	errs = append(errs, o.InsecureServing.Validate()...)
 	errs = append(errs, o.SecureServing.Validate()...)
+	errs = append(errs, o.GRPC.Validate()...)
 	errs = append(errs, o.MySQL.Validate()...)
+	errs = append(errs, o.Redis.Validate()...)
 	errs = append(errs, o.Feature.Validate()...)
+	errs = append(errs, o.OperationLog.Validate()...)
+	errs = append(errs, o.Redaction.Validate()...)
+	errs = append(errs, o.RateLimit.Validate()...)
+	errs = append(errs, o.Cors.Validate()...)
+	errs = append(errs, o.Auth.Validate()...)
 	errs = append(errs, o.Log.Validate()...)
 
 	return errs
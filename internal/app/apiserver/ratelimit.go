@@ -0,0 +1,64 @@
+package apiserver
+
+import (
+	"github.com/go-redis/redis/v8"
+
+	"gobackend/internal/pkg/middleware/ratelimit"
+	genericoptions "gobackend/internal/pkg/options"
+)
+
+// rateLimitKeyPrefix prefixes the redis keys of the request counts.
+const rateLimitKeyPrefix = "gobackend:apiserver:ratelimit:"
+
+// newRateLimit creates the rate limiting middleware selected by the rate limit options,
+// client is only used by the redis backend.
+func newRateLimit(opts *genericoptions.RateLimitOptions, client redis.UniversalClient) *ratelimit.Middleware {
+	var limiter ratelimit.Limiter
+
+	switch opts.Backend {
+	case genericoptions.RateLimitBackendRedis:
+		limiter = ratelimit.NewRedisLimiter(client, rateLimitKeyPrefix)
+	default:
+		limiter = ratelimit.NewMemoryLimiter()
+	}
+
+	return ratelimit.New(limiter, rateLimitPolicy(opts))
+}
+
+// rateLimitPolicy converts the rate limit options to the policy, the zero fields of the routes
+// take the defaults.
+func rateLimitPolicy(opts *genericoptions.RateLimitOptions) ratelimit.Policy {
+	policy := ratelimit.Policy{
+		Key: opts.Key,
+		Limit: ratelimit.Limit{
+			Rate:   opts.Rate,
+			Period: opts.Period,
+			Burst:  opts.Burst,
+		},
+		Routes: make([]ratelimit.Route, 0, len(opts.Routes)),
+		AuthFailures: ratelimit.Limit{
+			Rate:   opts.AuthFailureRate,
+			Period: opts.AuthFailurePeriod,
+		},
+	}
+
+	for _, route := range opts.Routes {
+		period := route.Period
+		if period == 0 {
+			period = opts.Period
+		}
+
+		policy.Routes = append(policy.Routes, ratelimit.Route{
+			Method: route.Method,
+			Path:   route.Path,
+			Key:    route.Key,
+			Limit: ratelimit.Limit{
+				Rate:   route.Rate,
+				Period: period,
+				Burst:  route.Burst,
+			},
+		})
+	}
+
+	return policy
+}
@@ -0,0 +1,302 @@
+package apiserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"gobackend/pkg/app"
+	"gobackend/pkg/errors"
+	"gobackend/pkg/log"
+
+	"gobackend/internal/app/apiserver/options"
+	"gobackend/internal/pkg/middleware"
+)
+
+// reloadableKeys are the settings applied while serving when the configuration is reloaded,
+// the keys ending with a dot cover the whole sections. The changes of the other settings
+// require a restart.
+var reloadableKeys = []string{
+	"log.level",
+	"feature.operation-logging",
+	"feature.rate-limit",
+	"cors.",
+	"rate-limit.key",
+	"rate-limit.rate",
+	"rate-limit.period",
+	"rate-limit.burst",
+	"rate-limit.routes",
+	"rate-limit.auth-failure-rate",
+	"rate-limit.auth-failure-period",
+}
+
+// change is a changed setting, the values are JSON encoded.
+type change struct {
+	key           string
+	before, after string
+}
+
+func (c change) reloadable() bool {
+	for _, key := range reloadableKeys {
+		if c.key == key || (strings.HasSuffix(key, ".") && strings.HasPrefix(c.key, key)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c change) String() string {
+	return fmt.Sprintf("%s: %s -> %s", c.key, c.before, c.after)
+}
+
+// reloader passes the reloaded options to the server once it is running.
+type reloader struct {
+	mu     sync.Mutex
+	server *apiServer
+}
+
+func (r *reloader) set(server *apiServer) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.server = server
+}
+
+// reload implements app.ReloadFunc.
+func (r *reloader) reload(opts app.CliOptions) error {
+	r.mu.Lock()
+	server := r.server
+	r.mu.Unlock()
+
+	if server == nil {
+		return errors.New("the server is not running yet")
+	}
+
+	return server.reload(opts.(*options.Options))
+}
+
+// reload applies the reloaded options. The options are rejected as a whole if any setting
+// requiring a restart is changed, the rejected changes are returned in the error.
+func (s *apiServer) reload(opts *options.Options) error {
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+
+	changes, err := diffOptions(s.opts, opts)
+	if err != nil {
+		return err
+	}
+
+	if len(changes) == 0 {
+		log.Info("configuration is not changed")
+
+		return nil
+	}
+
+	var rejected []string
+
+	for _, c := range changes {
+		if !c.reloadable() {
+			rejected = append(rejected, c.String())
+		}
+	}
+
+	if opts.Feature.EnableRateLimit && s.rateLimit == nil {
+		rejected = append(rejected, "feature.rate-limit: the redis backend can not be enabled without a restart")
+	}
+
+	if len(rejected) > 0 {
+		return fmt.Errorf("none of the changes is applied, these ones require a restart:\n\t%s", strings.Join(rejected, "\n\t"))
+	}
+
+	// Validate the changes before applying any of them, the setters below do not fail then.
+	if err := validateReloadable(opts); err != nil {
+		return fmt.Errorf("none of the changes is applied: %w", err)
+	}
+
+	if err := middleware.SetCorsConfig(opts.Cors.Config()); err != nil {
+		return err
+	}
+
+	if err := log.SetLevel(opts.Log.Level); err != nil {
+		return err
+	}
+
+	s.operationLogging.Set(opts.Feature.EnableOperationLogging)
+	s.rateLimiting.Set(opts.Feature.EnableRateLimit)
+
+	if s.rateLimit != nil {
+		s.rateLimit.SetPolicy(rateLimitPolicy(opts.RateLimit))
+	}
+
+	s.opts = opts
+
+	applied := make([]string, 0, len(changes))
+	for _, c := range changes {
+		applied = append(applied, c.String())
+	}
+
+	log.Infof("configuration changes applied:\n\t%s", strings.Join(applied, "\n\t"))
+
+	return nil
+}
+
+// validateReloadable validates the settings applied while serving.
+func validateReloadable(opts *options.Options) error {
+	if err := middleware.ValidateCorsConfig(opts.Cors.Config()); err != nil {
+		return fmt.Errorf("invalid cors options: %w", err)
+	}
+
+	var level log.Level
+	if err := level.UnmarshalText([]byte(opts.Log.Level)); err != nil {
+		return fmt.Errorf("invalid log.level: %w", err)
+	}
+
+	if errs := opts.RateLimit.Validate(); len(errs) > 0 {
+		return errs[0]
+	}
+
+	return nil
+}
+
+// diffOptions returns the settings changed from running to reloaded, sorted by keys. The settings
+// are keyed as in the configuration file, e.g. log.level, and the passwords and the other secrets
+// are masked.
+func diffOptions(running, reloaded *options.Options) ([]change, error) {
+	before, secrets, err := flattenOptions(running)
+	if err != nil {
+		return nil, err
+	}
+
+	after, _, err := flattenOptions(reloaded)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]struct{}, len(before))
+	for key := range before {
+		keys[key] = struct{}{}
+	}
+
+	for key := range after {
+		keys[key] = struct{}{}
+	}
+
+	var changes []change
+
+	for key := range keys {
+		if reflect.DeepEqual(before[key], after[key]) {
+			continue
+		}
+
+		c := change{key: key, before: "<masked>", after: "<masked>"}
+		if _, secret := secrets[key]; !secret && !strings.HasSuffix(key, "password") {
+			c.before, c.after = encodeValue(before[key]), encodeValue(after[key])
+		}
+
+		changes = append(changes, c)
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].key < changes[j].key })
+
+	return changes, nil
+}
+
+// flattenOptions flattens the JSON encoded options into the values keyed by the dot joined keys,
+// the arrays are kept as single values. The secrets hidden from JSON, e.g. auth.key, are added by
+// their mapstructure keys, which are returned in secrets.
+func flattenOptions(opts *options.Options) (values map[string]interface{}, secrets map[string]struct{}, err error) {
+	data, err := json.Marshal(opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var tree map[string]interface{}
+	if err := json.Unmarshal(data, &tree); err != nil {
+		return nil, nil, err
+	}
+
+	values = make(map[string]interface{})
+	flatten("", tree, values)
+
+	secrets = make(map[string]struct{})
+	addHiddenValues("", reflect.ValueOf(opts), values, secrets)
+
+	return values, secrets, nil
+}
+
+// addHiddenValues adds the values of the fields tagged with `json:"-"` in v, keyed by the dot joined
+// mapstructure tags.
+func addHiddenValues(prefix string, v reflect.Value, values map[string]interface{}, secrets map[string]struct{}) {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return
+		}
+
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct {
+		return
+	}
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		tag := strings.Split(field.Tag.Get("mapstructure"), ",")
+		if tag[0] == "" {
+			if len(tag) > 1 && tag[1] == "squash" {
+				addHiddenValues(prefix, v.Field(i), values, secrets)
+			}
+
+			continue
+		}
+
+		key := tag[0]
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+
+		if field.Tag.Get("json") == "-" {
+			values[key] = v.Field(i).Interface()
+			secrets[key] = struct{}{}
+
+			continue
+		}
+
+		addHiddenValues(key, v.Field(i), values, secrets)
+	}
+}
+
+func flatten(prefix string, tree map[string]interface{}, values map[string]interface{}) {
+	for key, value := range tree {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+
+		if subtree, ok := value.(map[string]interface{}); ok {
+			flatten(key, subtree, values)
+
+			continue
+		}
+
+		values[key] = value
+	}
+}
+
+func encodeValue(value interface{}) string {
+	if value == nil {
+		return "<unset>"
+	}
+
+	data, _ := json.Marshal(value)
+
+	return string(data)
+}
@@ -0,0 +1,118 @@
+package apiserver
+
+import (
+	"strings"
+	"testing"
+
+	"gobackend/internal/app/apiserver/options"
+	"gobackend/internal/pkg/middleware"
+)
+
+func newReloadServer() *apiServer {
+	opts := options.New()
+
+	return &apiServer{
+		opts:             opts,
+		operationLogging: middleware.NewSwitch(false),
+		rateLimiting:     middleware.NewSwitch(false),
+		rateLimit:        newRateLimit(opts.RateLimit, nil),
+	}
+}
+
+func TestDiffOptions(t *testing.T) {
+	running, reloaded := options.New(), options.New()
+	reloaded.Log.Level = "debug"
+	reloaded.Redis.Password = "secret"
+	reloaded.Cors.AllowOrigins = []string{"https://example.com"}
+	reloaded.Auth.Key = "rotated-key"
+	reloaded.MySQL.Password = "secret"
+
+	changes, err := diffOptions(running, reloaded)
+	if err != nil {
+		t.Fatalf("diffOptions() error = %v", err)
+	}
+
+	want := []string{
+		`auth.key: <masked> -> <masked>`,
+		`cors.allow-origins: ["*"] -> ["https://example.com"]`,
+		`log.level: "info" -> "debug"`,
+		`mysql.password: <masked> -> <masked>`,
+		`redis.password: <masked> -> <masked>`,
+	}
+
+	if len(changes) != len(want) {
+		t.Fatalf("diffOptions() = %v, want %v", changes, want)
+	}
+
+	for i, c := range changes {
+		if c.String() != want[i] {
+			t.Errorf("changes[%d] = %s, want %s", i, c, want[i])
+		}
+	}
+}
+
+func TestReload(t *testing.T) {
+	s := newReloadServer()
+
+	reloaded := options.New()
+	reloaded.Feature.EnableOperationLogging = true
+	reloaded.Feature.EnableRateLimit = true
+	reloaded.RateLimit.Rate = 100
+
+	if err := s.reload(reloaded); err != nil {
+		t.Fatalf("reload() error = %v", err)
+	}
+
+	if !s.operationLogging.On() || !s.rateLimiting.On() || s.opts != reloaded {
+		t.Fatal("reload() did not apply the changes")
+	}
+
+	restart := options.New()
+	restart.Feature.EnableOperationLogging = false
+	restart.MySQL.Host = "mysql:3306"
+
+	err := s.reload(restart)
+	if err == nil || !strings.Contains(err.Error(), "mysql.host") {
+		t.Fatalf("reload() error = %v, want the changes requiring a restart", err)
+	}
+
+	if !s.operationLogging.On() || s.opts != reloaded {
+		t.Fatal("reload() applied the changes along with the ones requiring a restart")
+	}
+
+	rotated := options.New()
+	rotated.Feature.EnableOperationLogging = true
+	rotated.Feature.EnableRateLimit = true
+	rotated.RateLimit.Rate = 100
+	rotated.Auth.Key = "rotated-key"
+
+	err = s.reload(rotated)
+	if err == nil || !strings.Contains(err.Error(), "auth.key") {
+		t.Fatalf("reload() error = %v, want auth.key requiring a restart", err)
+	}
+
+	invalid := options.New()
+	invalid.Feature.EnableOperationLogging = false
+	invalid.Cors.AllowOrigins = []string{"https://example.com"}
+	invalid.Log.Level = "verbose"
+
+	if err := s.reload(invalid); err == nil {
+		t.Fatal("reload() error = nil, want the invalid log.level rejected")
+	}
+
+	if !s.operationLogging.On() || s.opts != reloaded {
+		t.Fatal("reload() applied the changes along with the invalid ones")
+	}
+}
+
+func TestReloadRedisRateLimit(t *testing.T) {
+	s := newReloadServer()
+	s.rateLimit = nil
+
+	reloaded := options.New()
+	reloaded.Feature.EnableRateLimit = true
+
+	if err := s.reload(reloaded); err == nil {
+		t.Fatal("reload() error = nil, want enabling the redis backend rejected")
+	}
+}
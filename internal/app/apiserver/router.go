@@ -2,31 +2,66 @@ package apiserver
 
 import (
 	"github.com/gin-gonic/gin"
-	"github.com/spf13/viper"
 
 	"gobackend/pkg/core"
 	"gobackend/pkg/errors"
 	"gobackend/pkg/log"
 
 	"gobackend/internal/app/apiserver/controller/operationlog"
+	"gobackend/internal/app/apiserver/controller/v1/policy"
+	"gobackend/internal/app/apiserver/controller/v1/secret"
 	"gobackend/internal/app/apiserver/controller/v1/user"
+	"gobackend/internal/app/apiserver/secretcache"
 	"gobackend/internal/app/apiserver/store/mysql"
 	"gobackend/internal/pkg/code"
 	"gobackend/internal/pkg/middleware"
+	"gobackend/internal/pkg/middleware/auth"
+	genericoptions "gobackend/internal/pkg/options"
 
 	// Custom gin validators.
 	_ "gobackend/internal/pkg/validator"
 )
 
-func initRouter(g *gin.Engine) {
+// initRouter installs the routes, and returns the authentication strategy of the routes,
+// which is shared with the gRPC services. Operation logging is turned on and off by
+// operationLogging, and the routes are not rate limited if limit and authLimit are nil.
+func initRouter(
+	g *gin.Engine,
+	authOpts *genericoptions.AuthOptions,
+	operationLogs middleware.OperationLogQueue,
+	operationLogging *middleware.Switch,
+	limit gin.HandlerFunc,
+	authLimit gin.HandlerFunc,
+) auth.Strategy {
 	installMiddleware(g)
-	installController(g)
+
+	next := func(c *gin.Context) { c.Next() }
+
+	if limit == nil {
+		limit = next
+	}
+
+	if authLimit == nil {
+		authLimit = next
+	}
+
+	return installController(g, authOpts, operationLogs, operationLogging, limit, authLimit)
 }
 
 func installMiddleware(g *gin.Engine) {
 }
 
-func installController(g *gin.Engine) *gin.Engine {
+// installController installs the routes, limit is installed right after the authentication,
+// so that the authenticated clients are rate limited by their identities. authLimit is installed
+// right before the authentication to limit the failed ones, which never reach limit.
+func installController(
+	g *gin.Engine,
+	authOpts *genericoptions.AuthOptions,
+	operationLogs middleware.OperationLogQueue,
+	operationLogging *middleware.Switch,
+	limit gin.HandlerFunc,
+	authLimit gin.HandlerFunc,
+) auth.Strategy {
 	g.NoRoute(func(c *gin.Context) {
 		core.WriteResponse(c, errors.WithCode(code.ErrPageNotFound, "URL path not found"), nil)
 	})
@@ -35,33 +70,92 @@ func installController(g *gin.Engine) *gin.Engine {
 
 	log.Infof("get mysql factory instance: %v", storeIns)
 
-	// Operation logging.
-	if viper.GetBool("feature.operation-logging") {
-		g.Use(middleware.OperationLog(storeIns))
+	jwtStrategy, err := newJWTAuth(authOpts, storeIns)
+	if err != nil {
+		log.Fatalf("init jwt authentication failed: %s", err)
+	}
 
-		ol := g.Group("/operation-logs")
-		{
-			olController := operationlog.NewController(storeIns)
+	secrets := secretcache.New(storeIns, secretcache.DefaultTTL)
+	authStrategy := newAuthStrategy(authOpts, storeIns, jwtStrategy, secrets)
 
-			ol.GET("", olController.List)
-			ol.DELETE(":id", olController.Delete)
-		}
+	g.POST("/login", authLimit, limit, jwtStrategy.LoginHandler)
+	g.POST("/logout", limit, jwtStrategy.LogoutHandler)
+	// Refresh time can be longer than token timeout.
+	g.POST("/refresh", authLimit, limit, jwtStrategy.RefreshHandler)
+
+	// Operation logging, the routes are not found while it is turned off.
+	g.Use(operationLogging.Wrap(middleware.OperationLog(operationLogs)))
+
+	ol := g.Group(
+		"/operation-logs",
+		operationLogging.Guard(),
+		authLimit,
+		authStrategy.AuthFunc(),
+		limit,
+		middleware.Authz(storeIns),
+	)
+	{
+		olController := operationlog.NewController(storeIns)
+
+		ol.GET("", olController.List)
+		ol.GET("export", olController.Export)
+		ol.GET("stats", olController.Stats)
+		ol.DELETE(":id", olController.Delete)
 	}
 
 	v1 := g.Group("/v1")
 	{
+		userController := user.NewController(storeIns, secrets)
+
+		// Sign up is the only anonymous API in v1,
+		// all the routes registered after v1.Use need authentication.
+		v1.POST("/users", limit, userController.Create)
+		v1.Use(authLimit, authStrategy.AuthFunc(), limit)
+
 		userv1 := v1.Group("/users")
 		{
-			userController := user.NewController(storeIns)
-
-			userv1.POST("", userController.Create)
-			userv1.GET(":name", userController.Get)
-			userv1.GET("", userController.List)
-			userv1.PUT(":name", userController.Update)
-			userv1.DELETE(":name", userController.Delete)
-			userv1.DELETE("", userController.DeleteCollection)
+			self := middleware.Authz(storeIns, middleware.AllowSelf("name"))
+			admin := middleware.Authz(storeIns)
+
+			userv1.GET(":name", self, userController.Get)
+			userv1.GET("", admin, userController.List)
+			userv1.PUT(":name", self, userController.Update)
+			userv1.PATCH(":name", self, userController.Patch)
+			userv1.DELETE(":name", admin, userController.Delete)
+			userv1.DELETE("", admin, userController.DeleteCollection)
+		}
+
+		// Secrets are always scoped to the requester.
+		secretv1 := v1.Group("/secrets")
+		{
+			secretController := secret.NewController(storeIns, secrets)
+
+			secretv1.POST("", secretController.Create)
+			secretv1.GET(":name", secretController.Get)
+			secretv1.GET("", secretController.List)
+			secretv1.PUT(":name", secretController.Update)
+			secretv1.DELETE(":name", secretController.Delete)
+			secretv1.DELETE("", secretController.DeleteCollection)
 		}
+
+		policyController := policy.NewController(storeIns)
+
+		// Policies are scoped to the requester too, but they take part in the authorization of everyone,
+		// so only the administrators can write them.
+		policyv1 := v1.Group("/policies")
+		{
+			admin := middleware.Authz(storeIns)
+
+			policyv1.POST("", admin, policyController.Create)
+			policyv1.GET(":name", policyController.Get)
+			policyv1.GET("", policyController.List)
+			policyv1.PUT(":name", admin, policyController.Update)
+			policyv1.DELETE(":name", admin, policyController.Delete)
+			policyv1.DELETE("", admin, policyController.DeleteCollection)
+		}
+
+		v1.POST("/authz", policyController.Authorize)
 	}
 
-	return g
+	return authStrategy
 }
@@ -0,0 +1,116 @@
+package apiserver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	metav1 "gobackend/pkg/meta/v1"
+
+	"gobackend/internal/app/apiserver/store"
+	"gobackend/internal/app/apiserver/store/fake"
+	"gobackend/internal/pkg/code"
+	v1 "gobackend/internal/pkg/entity/apiserver/v1"
+	"gobackend/internal/pkg/middleware"
+	genericoptions "gobackend/internal/pkg/options"
+)
+
+// newRouter returns the routes of the apiserver authenticated by the basic strategy, with the store
+// of alice, an administrator, bob and carol.
+func newRouter(t *testing.T) *gin.Engine {
+	t.Helper()
+
+	saved := store.Client()
+	t.Cleanup(func() { store.SetClient(saved) })
+
+	ds := fake.New()
+	store.SetClient(ds)
+
+	for _, name := range []string{"alice", "bob", "carol"} {
+		user := &v1.User{Nickname: name, Password: "Passw0rd!", Email: name + "@example.com"}
+		user.Name = name
+
+		if name == "alice" {
+			user.IsAdmin = 1
+		}
+
+		if err := ds.Users().Create(context.Background(), user, metav1.CreateOptions{}); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	opts := genericoptions.NewAuthOptions()
+	opts.Strategy = genericoptions.AuthStrategyBasic
+	opts.Key = "test-key"
+
+	gin.SetMode(gin.TestMode)
+	g := gin.New()
+	initRouter(g, opts, nil, middleware.NewSwitch(false), nil, nil)
+
+	return g
+}
+
+func TestRouterAuthz(t *testing.T) {
+	const policy = `{"metadata":{"name":"allow-bob"},"effect":"allow","subjects":["bob"],` +
+		`"actions":["get"],"resources":["users:carol"]}`
+
+	tests := []struct {
+		name     string
+		username string
+		method   string
+		target   string
+		body     string
+		status   int
+		code     int
+	}{
+		{"anonymous", "", http.MethodGet, "/v1/users/bob", "", http.StatusUnauthorized, 0},
+		{"self", "bob", http.MethodGet, "/v1/users/bob", "", http.StatusOK, 0},
+		{"other user", "bob", http.MethodGet, "/v1/users/carol", "", http.StatusForbidden, code.ErrPermissionDenied},
+		{
+			"update other user", "bob", http.MethodPut, "/v1/users/carol", `{"nickname":"bob"}`,
+			http.StatusForbidden, code.ErrPermissionDenied,
+		},
+		{"delete self", "bob", http.MethodDelete, "/v1/users/bob", "", http.StatusForbidden, code.ErrPermissionDenied},
+		{"list users", "bob", http.MethodGet, "/v1/users", "", http.StatusForbidden, code.ErrPermissionDenied},
+		{"write policy", "bob", http.MethodPost, "/v1/policies", policy, http.StatusForbidden, code.ErrPermissionDenied},
+		{
+			"delete policy", "bob", http.MethodDelete, "/v1/policies/allow-bob", "",
+			http.StatusForbidden, code.ErrPermissionDenied,
+		},
+		{"read policies", "bob", http.MethodGet, "/v1/policies", "", http.StatusOK, 0},
+		{"operation logs", "bob", http.MethodGet, "/operation-logs", "", http.StatusNotFound, code.ErrPageNotFound},
+		{"admin gets other user", "alice", http.MethodGet, "/v1/users/carol", "", http.StatusOK, 0},
+		{"admin lists users", "alice", http.MethodGet, "/v1/users", "", http.StatusOK, 0},
+		{"admin writes policy", "alice", http.MethodPost, "/v1/policies", policy, http.StatusOK, 0},
+	}
+
+	g := newRouter(t)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+
+			if tt.username != "" {
+				req.SetBasicAuth(tt.username, "Passw0rd!")
+			}
+
+			w := httptest.NewRecorder()
+			g.ServeHTTP(w, req)
+
+			if w.Code != tt.status {
+				t.Fatalf("%s %s status = %d, want %d, body = %s", tt.method, tt.target, w.Code, tt.status, w.Body)
+			}
+
+			if tt.code != 0 {
+				if got := errorCode(t, w); got != tt.code {
+					t.Errorf("%s %s code = %d, want %d", tt.method, tt.target, got, tt.code)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package rpc
+
+import (
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	apiv1 "gobackend/api/apiserver/v1"
+	metav1 "gobackend/pkg/meta/v1"
+
+	"gobackend/internal/pkg/entity/apiserver/operationlog"
+	v1 "gobackend/internal/pkg/entity/apiserver/v1"
+)
+
+// toListOptions converts the list options of a request, the unset options are left nil
+// so that the defaults of the REST list calls apply.
+func toListOptions(in *apiv1.ListOptions) metav1.ListOptions {
+	if in == nil {
+		return metav1.ListOptions{}
+	}
+
+	return metav1.ListOptions{
+		LabelSelector: in.GetLabelSelector(),
+		FieldSelector: in.GetFieldSelector(),
+		Offset:        in.Offset,
+		Limit:         in.Limit,
+		SortBy:        in.GetSortBy(),
+		Fields:        in.GetFields(),
+		Continue:      in.GetContinue(),
+		TotalCount:    in.TotalCount,
+	}
+}
+
+func toListMeta(in metav1.ListMeta) *apiv1.ListMeta {
+	return &apiv1.ListMeta{
+		TotalCount: in.TotalCount,
+		Continue:   in.Continue,
+	}
+}
+
+// toTimestamp converts a time, the zero time, e.g. a field not selected, is converted to nil.
+func toTimestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+
+	return timestamppb.New(t)
+}
+
+func toObjectMeta(in metav1.ObjectMeta) *apiv1.ObjectMeta {
+	return &apiv1.ObjectMeta{
+		Id:              in.ID,
+		InstanceId:      in.InstanceID,
+		Name:            in.Name,
+		Labels:          in.Labels,
+		ResourceVersion: in.ResourceVersion,
+		CreatedAt:       toTimestamp(in.CreatedAt),
+		UpdatedAt:       toTimestamp(in.UpdatedAt),
+	}
+}
+
+// toUser converts a user, the password is never returned.
+func toUser(in *v1.User) *apiv1.User {
+	return &apiv1.User{
+		Metadata:    toObjectMeta(in.ObjectMeta),
+		Nickname:    in.Nickname,
+		Email:       in.Email,
+		Phone:       in.Phone,
+		IsAdmin:     in.IsAdministrator(),
+		TotalPolicy: in.TotalPolicy,
+	}
+}
+
+func toOperationLog(in *operationlog.OperationLog) *apiv1.OperationLog {
+	return &apiv1.OperationLog{
+		Id:         in.ID,
+		CreatedAt:  toTimestamp(in.CreatedAt),
+		Username:   in.Username,
+		UserAgent:  in.UserAgent,
+		ClientIp:   in.ClientIP,
+		ReqMethod:  in.ReqMethod,
+		ReqPath:    in.ReqPath,
+		ReqBody:    in.ReqBody,
+		ReqReferer: in.ReqReferer,
+		ReqTime:    toTimestamp(in.ReqTime),
+		ReqLatency: in.ReqLatency,
+		HttpStatus: int32(in.HTTPStatus),
+		ResData:    in.ResData,
+	}
+}
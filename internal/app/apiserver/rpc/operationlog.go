@@ -0,0 +1,44 @@
+package rpc
+
+import (
+	"context"
+
+	apiv1 "gobackend/api/apiserver/v1"
+
+	srvv1 "gobackend/internal/app/apiserver/service/v1"
+	"gobackend/internal/app/apiserver/store"
+)
+
+type operationLogServer struct {
+	apiv1.UnimplementedOperationLogServiceServer
+
+	srv srvv1.Service
+}
+
+// ListOperationLogs lists the operation logs.
+func (o *operationLogServer) ListOperationLogs(
+	ctx context.Context,
+	r *apiv1.ListOperationLogsRequest,
+) (*apiv1.ListOperationLogsResponse, error) {
+	opts := toListOptions(r.GetOptions())
+
+	if _, err := store.OperationLogFields.Requirements(opts.FieldSelector); err != nil {
+		return nil, err
+	}
+
+	operationLogs, err := o.srv.OperationLogs().List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &apiv1.ListOperationLogsResponse{
+		Metadata: toListMeta(operationLogs.ListMeta),
+		Items:    make([]*apiv1.OperationLog, 0, len(operationLogs.Items)),
+	}
+
+	for _, item := range operationLogs.Items {
+		resp.Items = append(resp.Items, toOperationLog(item))
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,20 @@
+// Package rpc implements the gRPC services of the apiserver on top of the service layer,
+// which is shared with the RESTful controllers.
+package rpc
+
+import (
+	"google.golang.org/grpc"
+
+	apiv1 "gobackend/api/apiserver/v1"
+
+	srvv1 "gobackend/internal/app/apiserver/service/v1"
+	"gobackend/internal/app/apiserver/store"
+)
+
+// Register registers the gRPC services backed by the store.
+func Register(r grpc.ServiceRegistrar, storeIns store.Factory) {
+	srv := srvv1.NewService(storeIns)
+
+	apiv1.RegisterUserServiceServer(r, &userServer{srv: srv})
+	apiv1.RegisterOperationLogServiceServer(r, &operationLogServer{srv: srv})
+}
@@ -0,0 +1,167 @@
+package rpc
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/AlekSi/pointer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	apiv1 "gobackend/api/apiserver/v1"
+	metav1 "gobackend/pkg/meta/v1"
+
+	"gobackend/internal/app/apiserver/store/fake"
+	"gobackend/internal/pkg/entity/apiserver/operationlog"
+	v1 "gobackend/internal/pkg/entity/apiserver/v1"
+	"gobackend/internal/pkg/middleware"
+	genericserver "gobackend/internal/pkg/server"
+)
+
+// dial serves the services backed by a fake store with alice, an administrator, and bob.
+// The requester is the `username` metadata of the calls.
+func dial(t *testing.T) *grpc.ClientConn {
+	t.Helper()
+
+	ctx := context.Background()
+	ds := fake.New()
+
+	for _, name := range []string{"alice", "bob"} {
+		user := &v1.User{Nickname: name, Password: "Passw0rd!", Email: name + "@example.com"}
+		user.Name = name
+
+		if name == "alice" {
+			user.IsAdmin = 1
+		}
+
+		if err := ds.Users().Create(ctx, user, metav1.CreateOptions{}); err != nil {
+			t.Fatalf("create user error = %v", err)
+		}
+	}
+
+	logs := []*operationlog.OperationLog{
+		{Username: "alice", ReqMethod: "POST", ReqPath: "/v1/users", HTTPStatus: 200},
+		{Username: "bob", ReqMethod: "DELETE", ReqPath: "/v1/secrets/a", HTTPStatus: 404},
+	}
+	if err := ds.OperationLogs().CreateCollection(ctx, logs, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("create operation logs error = %v", err)
+	}
+
+	lis := bufconn.Listen(1 << 20)
+
+	s := grpc.NewServer(grpc.UnaryInterceptor(func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		if values := md.Get("username"); len(values) > 0 {
+			ctx = context.WithValue(ctx, middleware.UsernameKey, values[0]) //nolint:staticcheck
+		}
+
+		resp, err := handler(ctx, req)
+
+		return resp, genericserver.GRPCError(err)
+	}))
+	Register(s, ds)
+
+	go func() {
+		_ = s.Serve(lis)
+	}()
+
+	conn, err := grpc.DialContext(ctx, "bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithInsecure(),
+	)
+	if err != nil {
+		t.Fatalf("dial error = %v", err)
+	}
+
+	t.Cleanup(func() {
+		conn.Close()
+		s.Stop()
+	})
+
+	return conn
+}
+
+func as(username string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "username", username)
+}
+
+func TestUserService(t *testing.T) {
+	client := apiv1.NewUserServiceClient(dial(t))
+
+	user, err := client.GetUser(as("bob"), &apiv1.GetUserRequest{Name: "bob"})
+	if err != nil {
+		t.Fatalf("GetUser() error = %v", err)
+	}
+
+	if user.GetMetadata().GetName() != "bob" || user.GetEmail() != "bob@example.com" || user.GetIsAdmin() {
+		t.Errorf("GetUser() = %v", user)
+	}
+
+	if user.GetMetadata().GetCreatedAt() == nil {
+		t.Error("GetUser() created_at is nil")
+	}
+
+	_, err = client.GetUser(as("bob"), &apiv1.GetUserRequest{Name: "alice"})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("GetUser() of another user error = %v, want PermissionDenied", err)
+	}
+
+	_, err = client.GetUser(as("alice"), &apiv1.GetUserRequest{Name: "carol"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("GetUser() of a missing user error = %v, want NotFound", err)
+	}
+
+	_, err = client.ListUsers(as("bob"), &apiv1.ListUsersRequest{})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("ListUsers() by a normal user error = %v, want PermissionDenied", err)
+	}
+
+	list, err := client.ListUsers(as("alice"), &apiv1.ListUsersRequest{
+		Options: &apiv1.ListOptions{SortBy: "name", Limit: pointer.ToInt64(1)},
+	})
+	if err != nil {
+		t.Fatalf("ListUsers() error = %v", err)
+	}
+
+	if len(list.GetItems()) != 1 || list.GetItems()[0].GetMetadata().GetName() != "alice" ||
+		list.GetMetadata().GetTotalCount() != 2 || list.GetMetadata().GetContinue() == "" {
+		t.Errorf("ListUsers() = %v", list)
+	}
+
+	_, err = client.ListUsers(as("alice"), &apiv1.ListUsersRequest{
+		Options: &apiv1.ListOptions{FieldSelector: "password=x"},
+	})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("ListUsers() with an invalid selector error = %v, want InvalidArgument", err)
+	}
+}
+
+func TestOperationLogService(t *testing.T) {
+	client := apiv1.NewOperationLogServiceClient(dial(t))
+
+	_, err := client.ListOperationLogs(as("bob"), &apiv1.ListOperationLogsRequest{})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("ListOperationLogs() by a normal user error = %v, want PermissionDenied", err)
+	}
+
+	list, err := client.ListOperationLogs(as("alice"), &apiv1.ListOperationLogsRequest{
+		Options: &apiv1.ListOptions{FieldSelector: "http_status=404"},
+	})
+	if err != nil {
+		t.Fatalf("ListOperationLogs() error = %v", err)
+	}
+
+	if len(list.GetItems()) != 1 || list.GetItems()[0].GetUsername() != "bob" ||
+		list.GetItems()[0].GetHttpStatus() != 404 {
+		t.Errorf("ListOperationLogs() = %v", list)
+	}
+}
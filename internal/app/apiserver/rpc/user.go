@@ -0,0 +1,63 @@
+package rpc
+
+import (
+	"context"
+
+	apiv1 "gobackend/api/apiserver/v1"
+	"gobackend/pkg/errors"
+	"gobackend/pkg/labels"
+	metav1 "gobackend/pkg/meta/v1"
+
+	srvv1 "gobackend/internal/app/apiserver/service/v1"
+	"gobackend/internal/app/apiserver/store"
+	"gobackend/internal/pkg/code"
+)
+
+type userServer struct {
+	apiv1.UnimplementedUserServiceServer
+
+	srv srvv1.Service
+}
+
+// GetUser returns a user.
+func (u *userServer) GetUser(ctx context.Context, r *apiv1.GetUserRequest) (*apiv1.User, error) {
+	if r.GetName() == "" {
+		return nil, errors.WithCode(code.ErrValidation, "name is required")
+	}
+
+	user, err := u.srv.Users().Get(ctx, r.GetName(), metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	return toUser(user), nil
+}
+
+// ListUsers lists the users.
+func (u *userServer) ListUsers(ctx context.Context, r *apiv1.ListUsersRequest) (*apiv1.ListUsersResponse, error) {
+	opts := toListOptions(r.GetOptions())
+
+	if _, err := store.UserFields.Requirements(opts.FieldSelector); err != nil {
+		return nil, err
+	}
+
+	if _, err := labels.Parse(opts.LabelSelector); err != nil {
+		return nil, errors.WithCode(code.ErrLabelSelectorValidation, err.Error())
+	}
+
+	users, err := u.srv.Users().List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &apiv1.ListUsersResponse{
+		Metadata: toListMeta(users.ListMeta),
+		Items:    make([]*apiv1.User, 0, len(users.Items)),
+	}
+
+	for _, user := range users.Items {
+		resp.Items = append(resp.Items, toUser(user))
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,175 @@
+// Package secretcache caches user secrets in memory, it is used by auth.CacheStrategy
+// to verify the tokens signed by secrets.
+package secretcache
+
+import (
+	"context"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/AlekSi/pointer"
+
+	"gobackend/pkg/errors"
+	metav1 "gobackend/pkg/meta/v1"
+
+	"gobackend/internal/app/apiserver/store"
+	"gobackend/internal/pkg/code"
+	"gobackend/internal/pkg/middleware/auth"
+)
+
+// DefaultTTL defines how long a cached secret is trusted before reloading it from the store.
+const DefaultTTL = time.Minute
+
+const (
+	// notFoundTTL defines how long a secret id is known to be not found, so that the tokens
+	// with unknown ids do not hit the store on every request.
+	notFoundTTL = 5 * time.Second
+
+	// maxNotFound limits the number of the cached not found secret ids, the ids come from
+	// the untrusted tokens.
+	maxNotFound = 10000
+)
+
+var secretIDPattern = regexp.MustCompile(`^[a-zA-Z0-9]{1,64}$`)
+
+type item struct {
+	secret   auth.Secret
+	loadedAt time.Time
+}
+
+// Cache is a read-through in-memory cache of secrets keyed by secret id.
+type Cache struct {
+	store store.Factory
+	ttl   time.Duration
+
+	lock     sync.RWMutex
+	items    map[string]item
+	notFound map[string]time.Time
+}
+
+// New creates a secret cache backed by the given store.
+func New(storeIns store.Factory, ttl time.Duration) *Cache {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+
+	return &Cache{
+		store:    storeIns,
+		ttl:      ttl,
+		items:    make(map[string]item),
+		notFound: make(map[string]time.Time),
+	}
+}
+
+// Get returns the secret of the given secret id, it has the signature required by auth.NewCacheStrategy.
+func (c *Cache) Get(ctx context.Context, kid string) (auth.Secret, error) {
+	c.lock.RLock()
+	it, ok := c.items[kid]
+	notFoundAt, notFound := c.notFound[kid]
+	c.lock.RUnlock()
+
+	if ok && time.Since(it.loadedAt) < c.ttl {
+		return it.secret, nil
+	}
+
+	if notFound && time.Since(notFoundAt) < notFoundTTL {
+		return auth.Secret{}, errors.WithCode(code.ErrSecretNotFound, "secret `%s` not found", kid)
+	}
+
+	secret, err := c.load(ctx, kid)
+	if err != nil {
+		c.Invalidate(kid)
+
+		if errors.IsCode(err, code.ErrSecretNotFound) {
+			c.setNotFound(kid)
+		}
+
+		return auth.Secret{}, err
+	}
+
+	c.lock.Lock()
+	c.items[kid] = item{secret: secret, loadedAt: time.Now()}
+	delete(c.notFound, kid)
+	c.lock.Unlock()
+
+	return secret, nil
+}
+
+// Invalidate removes the secret from cache, it should be called after the secret is created, changed or deleted.
+func (c *Cache) Invalidate(kids ...string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for _, kid := range kids {
+		delete(c.items, kid)
+		delete(c.notFound, kid)
+	}
+}
+
+// InvalidateUsers removes the secrets of the users from cache, it should be called after the users
+// are deleted, which deletes their secrets.
+func (c *Cache) InvalidateUsers(usernames ...string) {
+	deleted := make(map[string]bool, len(usernames))
+	for _, username := range usernames {
+		deleted[username] = true
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for kid, it := range c.items {
+		if deleted[it.secret.Username] {
+			delete(c.items, kid)
+		}
+	}
+}
+
+func (c *Cache) setNotFound(kid string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if len(c.notFound) >= maxNotFound {
+		for id, notFoundAt := range c.notFound {
+			if time.Since(notFoundAt) >= notFoundTTL {
+				delete(c.notFound, id)
+			}
+		}
+
+		// All of them are recent, the lookups are not cached until they expire.
+		if len(c.notFound) >= maxNotFound {
+			return
+		}
+	}
+
+	c.notFound[kid] = time.Now()
+}
+
+func (c *Cache) load(ctx context.Context, kid string) (auth.Secret, error) {
+	// kid comes from the untrusted token header, only ids generated by idtool.NewSecretID are accepted.
+	if !secretIDPattern.MatchString(kid) {
+		return auth.Secret{}, errors.WithCode(code.ErrSecretNotFound, "invalid secret id")
+	}
+
+	secrets, err := c.store.Secrets().List(ctx, "", metav1.ListOptions{
+		FieldSelector: "secret_id==" + kid,
+		Limit:         pointer.ToInt64(1),
+		TotalCount:    pointer.ToBool(false),
+	})
+	if err != nil {
+		return auth.Secret{}, err
+	}
+
+	if len(secrets.Items) == 0 {
+		return auth.Secret{}, errors.WithCode(code.ErrSecretNotFound, "secret `%s` not found", kid)
+	}
+
+	s := secrets.Items[0]
+
+	return auth.Secret{
+		Username: s.Username,
+		ID:       s.SecretID,
+		Key:      s.SecretKey,
+		Expires:  s.Expires,
+	}, nil
+}
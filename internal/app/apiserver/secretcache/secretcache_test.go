@@ -0,0 +1,84 @@
+package secretcache
+
+import (
+	"context"
+	"testing"
+
+	"gobackend/pkg/errors"
+	metav1 "gobackend/pkg/meta/v1"
+
+	"gobackend/internal/app/apiserver/store"
+	"gobackend/internal/app/apiserver/store/fake"
+	"gobackend/internal/pkg/code"
+	v1 "gobackend/internal/pkg/entity/apiserver/v1"
+)
+
+func createSecret(t *testing.T, ds store.Factory, username, kid string) {
+	t.Helper()
+
+	secret := &v1.Secret{Username: username, SecretID: kid, SecretKey: "key"}
+	secret.Name = kid
+
+	if err := ds.Secrets().Create(context.Background(), secret, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+}
+
+func TestCacheNotFound(t *testing.T) {
+	ds := fake.New()
+	c := New(ds, DefaultTTL)
+
+	if _, err := c.Get(context.Background(), "abc"); !errors.IsCode(err, code.ErrSecretNotFound) {
+		t.Fatalf("Get() error = %v, want ErrSecretNotFound", err)
+	}
+
+	// The lookup is cached as not found until the id is invalidated.
+	createSecret(t, ds, "alice", "abc")
+
+	if _, err := c.Get(context.Background(), "abc"); !errors.IsCode(err, code.ErrSecretNotFound) {
+		t.Errorf("Get() error = %v, want the cached ErrSecretNotFound", err)
+	}
+
+	c.Invalidate("abc")
+
+	if secret, err := c.Get(context.Background(), "abc"); err != nil || secret.Username != "alice" {
+		t.Errorf("Get() after Invalidate() = %+v, %v", secret, err)
+	}
+}
+
+func TestCacheInvalidateUsers(t *testing.T) {
+	ds := fake.New()
+	c := New(ds, DefaultTTL)
+
+	createSecret(t, ds, "alice", "abc")
+	createSecret(t, ds, "bob", "def")
+
+	for _, kid := range []string{"abc", "def"} {
+		if _, err := c.Get(context.Background(), kid); err != nil {
+			t.Fatalf("Get(%s) error = %v", kid, err)
+		}
+	}
+
+	// The secrets are deleted with the users.
+	ctx := context.Background()
+	opts := metav1.DeleteOptions{Unscoped: true}
+
+	if err := ds.Secrets().DeleteCollection(ctx, "alice", []string{"abc"}, opts); err != nil {
+		t.Fatalf("DeleteCollection() error = %v", err)
+	}
+
+	if err := ds.Secrets().DeleteCollection(ctx, "bob", []string{"def"}, opts); err != nil {
+		t.Fatalf("DeleteCollection() error = %v", err)
+	}
+
+	c.InvalidateUsers("alice")
+
+	if _, err := c.Get(context.Background(), "abc"); !errors.IsCode(err, code.ErrSecretNotFound) {
+		t.Errorf("Get() of a deleted user error = %v, want ErrSecretNotFound", err)
+	}
+
+	// The secrets of the other users are kept until they expire.
+	if _, err := c.Get(context.Background(), "def"); err != nil {
+		t.Errorf("Get() of another user error = %v", err)
+	}
+}
@@ -1,12 +1,29 @@
 package apiserver
 
 import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
+	"github.com/spf13/viper"
+
 	"gobackend/pkg/log"
 	"gobackend/pkg/shutdown"
 	"gobackend/pkg/shutdown/shutdownmanagers/posixsignal"
+	"gobackend/pkg/storage"
 
 	"gobackend/internal/app/apiserver/config"
+	"gobackend/internal/app/apiserver/oplog"
+	"gobackend/internal/app/apiserver/options"
+	"gobackend/internal/app/apiserver/rpc"
+	"gobackend/internal/app/apiserver/store"
+	"gobackend/internal/app/apiserver/store/cache"
+	"gobackend/internal/app/apiserver/store/fake"
 	"gobackend/internal/app/apiserver/store/mysql"
+	"gobackend/internal/pkg/middleware"
+	"gobackend/internal/pkg/middleware/ratelimit"
 	genericoptions "gobackend/internal/pkg/options"
 	genericserver "gobackend/internal/pkg/server"
 )
@@ -15,6 +32,23 @@ type apiServer struct {
 	gs               *shutdown.GracefulShutdown
 	genericAPIServer *genericserver.GenericAPIServer
 	mysqlOptions     *genericoptions.MySQLOptions
+	redisOptions     *genericoptions.RedisOptions
+	rateLimitOptions *genericoptions.RateLimitOptions
+	authOptions      *genericoptions.AuthOptions
+	operationLogOpts *genericoptions.OperationLogOptions
+	janitor          *oplog.Janitor
+	pipeline         *oplog.Pipeline
+	redisClient      redis.UniversalClient
+
+	// The settings changed by reloading the configuration, see reload.
+	reloadMu         sync.Mutex
+	opts             *options.Options
+	operationLogging *middleware.Switch
+	rateLimiting     *middleware.Switch
+	rateLimit        *ratelimit.Middleware
+
+	// stopped is closed when the shutdown callback returns.
+	stopped chan struct{}
 }
 
 type preparedAPIServer struct {
@@ -39,38 +73,168 @@ func createAPIServer(cfg *config.Config) (*apiServer, error) {
 		gs:               gs,
 		genericAPIServer: genericServer,
 		mysqlOptions:     cfg.MySQL,
+		redisOptions:     cfg.Redis,
+		rateLimitOptions: cfg.RateLimit,
+		authOptions:      cfg.Auth,
+		operationLogOpts: cfg.OperationLog,
+		opts:             cfg.Options,
+		stopped:          make(chan struct{}),
 	}
 
 	return server, nil
 }
 
 func (s *apiServer) PrepareRun() preparedAPIServer {
-	if err := mysql.InitMySQLFactory(s.mysqlOptions); err != nil {
-		log.Fatalf("init mysql failed: %s", err)
+	if err := initStore(s.mysqlOptions); err != nil {
+		log.Fatalf("init store failed: %s", err)
+	}
+
+	rateLimit := viper.GetBool("feature.rate-limit")
+
+	if viper.GetBool("feature.store-cache") ||
+		(rateLimit && s.rateLimitOptions.Backend == genericoptions.RateLimitBackendRedis) {
+		client, err := newRedisClient(s.redisOptions)
+		if err != nil {
+			log.Fatalf("init redis client failed: %s", err)
+		}
+
+		s.redisClient = client
+	}
+
+	if viper.GetBool("feature.store-cache") {
+		store.SetClient(cache.New(store.Client(), s.redisClient, s.redisOptions.CacheTTL))
+	}
+
+	// The pipeline is created even if operation logging is disabled,
+	// so that it can be enabled by reloading the configuration.
+	sinks, err := oplog.NewSinks(store.Client(), s.operationLogOpts)
+	if err != nil {
+		log.Fatalf("init operation log sinks failed: %s", err)
 	}
 
-	initRouter(s.genericAPIServer.Engine)
+	s.pipeline = oplog.NewPipeline(sinks, s.operationLogOpts)
+	s.pipeline.Start()
+
+	s.operationLogging = middleware.NewSwitch(viper.GetBool("feature.operation-logging"))
+	s.rateLimiting = middleware.NewSwitch(rateLimit)
+
+	// The redis backend can not be enabled while serving if there is no redis client.
+	var limit, authLimit gin.HandlerFunc
+	if s.rateLimitOptions.Backend != genericoptions.RateLimitBackendRedis || s.redisClient != nil {
+		s.rateLimit = newRateLimit(s.rateLimitOptions, s.redisClient)
+		limit = s.rateLimiting.Wrap(s.rateLimit.Handler())
+		authLimit = s.rateLimiting.Wrap(s.rateLimit.AuthFailureHandler())
+	}
+
+	authStrategy := initRouter(s.genericAPIServer.Engine, s.authOptions, s.pipeline, s.operationLogging, limit, authLimit)
+
+	// The gRPC services share the service layer and the authentication with the routes.
+	rpc.Register(s.genericAPIServer, store.Client())
+	s.genericAPIServer.SetGRPCAuthFunc(newGRPCAuth(authStrategy))
+
+	// The exports are streamed, they may take longer than the request timeout.
+	s.genericAPIServer.AddLongRunningPaths("/operation-logs/export")
+
+	s.janitor = oplog.NewJanitor(store.Client(), s.operationLogOpts)
+	s.janitor.Start()
+
+	if err := s.addHealthChecks(); err != nil {
+		log.Fatalf("add health checks failed: %s", err)
+	}
 
 	s.gs.AddShutdownCallback(shutdown.Func(func(string) error {
+		defer close(s.stopped)
+
+		// Stop serving first so that no more operation logs are queued, then write the queued ones
+		// and stop the background jobs before the store they use is closed.
+		s.genericAPIServer.Close()
+
+		if err := s.pipeline.Close(); err != nil {
+			log.Errorf("close operation log pipeline error: %s", err)
+		}
+
+		s.janitor.Stop()
+
+		if s.redisClient != nil {
+			if err := s.redisClient.Close(); err != nil {
+				log.Errorf("close redis client error: %s", err)
+			}
+		}
+
 		mysqlStore := mysql.GetMysqlFactory()
 		if mysqlStore != nil {
 			return mysqlStore.Close()
 		}
 
-		s.genericAPIServer.Close()
-
 		return nil
 	}))
 
 	return preparedAPIServer{s}
 }
 
+// addHealthChecks adds the checks of the dependencies to the readiness of the server.
+func (s *apiServer) addHealthChecks() error {
+	checks := []genericserver.HealthChecker{
+		genericserver.NamedCheck("mysql", store.Client().Ping),
+		genericserver.NamedCheck("operation-log-queue", s.pipeline.Check),
+	}
+
+	if s.redisClient != nil {
+		checks = append(checks, genericserver.NamedCheck("redis", func(ctx context.Context) error {
+			return s.redisClient.Ping(ctx).Err()
+		}))
+	}
+
+	return s.genericAPIServer.AddReadyzChecks(checks...)
+}
+
+// initStore creates the store.Factory selected by mysql.driver.
+func initStore(opts *genericoptions.MySQLOptions) error {
+	if opts.Driver == genericoptions.DriverMemory {
+		log.Warn("using in-memory store, all the data will be lost after the server exits")
+		store.SetClient(fake.New())
+
+		return nil
+	}
+
+	return mysql.InitMySQLFactory(opts)
+}
+
+// newRedisClient creates the redis client selected by the redis options.
+func newRedisClient(opts *genericoptions.RedisOptions) (redis.UniversalClient, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	log.Infof("start connecting redis %v ...", opts.Addresses())
+
+	return storage.NewRedisClient(ctx, &storage.RedisOptions{
+		Addrs:                 opts.Addresses(),
+		Username:              opts.Username,
+		Password:              opts.Password,
+		Database:              opts.Database,
+		MasterName:            opts.MasterName,
+		EnableCluster:         opts.EnableCluster,
+		PoolSize:              opts.MaxActive,
+		MinIdleConns:          opts.MaxIdle,
+		DialTimeout:           time.Duration(opts.Timeout) * time.Second,
+		UseSSL:                opts.UseSSL,
+		SSLInsecureSkipVerify: opts.SSLInsecureSkipVerify,
+	})
+}
+
 func (s preparedAPIServer) Run() error {
 	if err := s.gs.Start(); err != nil {
 		log.Fatalf("start shutdown manager failed: %s", err.Error())
 	}
 
-	return s.genericAPIServer.Run()
+	if err := s.genericAPIServer.Run(); err != nil {
+		return err
+	}
+
+	// The servers are closed by the shutdown callback, wait for it to clean up.
+	<-s.stopped
+
+	return nil
 }
 
 func buildGenericConfig(cfg *config.Config) (genericConfig *genericserver.Config, lastErr error) {
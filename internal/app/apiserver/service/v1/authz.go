@@ -0,0 +1,56 @@
+package v1
+
+import (
+	"context"
+
+	"gobackend/pkg/errors"
+	metav1 "gobackend/pkg/meta/v1"
+
+	"gobackend/internal/app/apiserver/store"
+	"gobackend/internal/pkg/code"
+	v1 "gobackend/internal/pkg/entity/apiserver/v1"
+	"gobackend/internal/pkg/middleware"
+)
+
+// currentUser returns the user who sends the request, nil means the request is anonymous.
+func currentUser(ctx context.Context, s store.Factory) (*v1.User, error) {
+	if user, ok := ctx.Value(middleware.UserKey).(*v1.User); ok {
+		return user, nil
+	}
+
+	username, _ := ctx.Value(middleware.UsernameKey).(string)
+	if username == "" {
+		return nil, nil
+	}
+
+	return s.Users().Get(ctx, username, metav1.GetOptions{})
+}
+
+// requireAdmin returns ErrPermissionDenied if the requester is not an administrator.
+func requireAdmin(ctx context.Context, s store.Factory) error {
+	user, err := currentUser(ctx, s)
+	if err != nil {
+		return err
+	}
+
+	if user == nil || !user.IsAdministrator() {
+		return errors.WithCode(code.ErrPermissionDenied, "administrator role is required")
+	}
+
+	return nil
+}
+
+// requireSelfOrAdmin returns ErrPermissionDenied if the requester is neither the owner
+// of the named resource nor an administrator.
+func requireSelfOrAdmin(ctx context.Context, s store.Factory, username string) error {
+	user, err := currentUser(ctx, s)
+	if err != nil {
+		return err
+	}
+
+	if user == nil || (!user.IsAdministrator() && user.Name != username) {
+		return errors.WithCode(code.ErrPermissionDenied, "can not access user `%s`", username)
+	}
+
+	return nil
+}
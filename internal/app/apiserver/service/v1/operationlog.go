@@ -0,0 +1,38 @@
+package v1
+
+import (
+	"context"
+
+	metav1 "gobackend/pkg/meta/v1"
+
+	"gobackend/internal/app/apiserver/store"
+	"gobackend/internal/pkg/entity/apiserver/operationlog"
+)
+
+// OperationLogSrv defines functions used to handle operation log request.
+type OperationLogSrv interface {
+	List(ctx context.Context, opts metav1.ListOptions) (*operationlog.List, error)
+}
+
+type operationLogService struct {
+	store store.Factory
+}
+
+var _ OperationLogSrv = (*operationLogService)(nil)
+
+func newOperationLogs(srv *service) *operationLogService {
+	return &operationLogService{store: srv.store}
+}
+
+func (o *operationLogService) List(ctx context.Context, opts metav1.ListOptions) (*operationlog.List, error) {
+	if err := requireAdmin(ctx, o.store); err != nil {
+		return nil, err
+	}
+
+	operationLogs, err := o.store.OperationLogs().List(ctx, opts)
+	if err != nil {
+		return nil, listError(err)
+	}
+
+	return operationLogs, nil
+}
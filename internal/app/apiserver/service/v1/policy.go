@@ -0,0 +1,172 @@
+package v1
+
+import (
+	"context"
+
+	"gobackend/pkg/errors"
+	metav1 "gobackend/pkg/meta/v1"
+	"gobackend/pkg/util/gormtool"
+
+	"gobackend/internal/app/apiserver/store"
+	"gobackend/internal/pkg/authorizer"
+	"gobackend/internal/pkg/code"
+	v1 "gobackend/internal/pkg/entity/apiserver/v1"
+)
+
+// PolicySrv defines functions used to handle policy request.
+type PolicySrv interface {
+	Create(ctx context.Context, policy *v1.Policy, opts metav1.CreateOptions) error
+	Update(ctx context.Context, policy *v1.Policy, opts metav1.UpdateOptions) error
+	Delete(ctx context.Context, username, name string, opts metav1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, username string, names []string, opts metav1.DeleteOptions) error
+	Get(ctx context.Context, username, name string, opts metav1.GetOptions) (*v1.Policy, error)
+	List(ctx context.Context, username string, opts metav1.ListOptions) (*v1.PolicyList, error)
+	Authorize(ctx context.Context, req *v1.AuthzRequest) (*v1.AuthzResponse, error)
+}
+
+type policyService struct {
+	store store.Factory
+}
+
+var _ PolicySrv = (*policyService)(nil)
+
+func newPolicies(srv *service) *policyService {
+	return &policyService{store: srv.store}
+}
+
+func (s *policyService) Create(ctx context.Context, policy *v1.Policy, opts metav1.CreateOptions) error {
+	if err := requireAdmin(ctx, s.store); err != nil {
+		return err
+	}
+
+	if _, err := s.store.Policies().Get(ctx, policy.Username, policy.Name, metav1.GetOptions{}); err == nil {
+		return errors.WithCode(code.ErrPolicyAlreadyExist, "policy `%s` already exist", policy.Name)
+	} else if !errors.IsCode(err, code.ErrPolicyNotFound) {
+		return err
+	}
+
+	if err := s.store.Policies().Create(ctx, policy, opts); err != nil {
+		if errors.IsCode(err, code.ErrPolicyAlreadyExist) {
+			return err
+		}
+
+		return errors.WithCode(code.ErrDatabase, err.Error())
+	}
+
+	return nil
+}
+
+func (s *policyService) Update(ctx context.Context, policy *v1.Policy, opts metav1.UpdateOptions) error {
+	if err := requireAdmin(ctx, s.store); err != nil {
+		return err
+	}
+
+	if err := s.store.Policies().Update(ctx, policy, opts); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *policyService) Delete(ctx context.Context, username, name string, opts metav1.DeleteOptions) error {
+	if err := requireAdmin(ctx, s.store); err != nil {
+		return err
+	}
+
+	if err := s.store.Policies().Delete(ctx, username, name, opts); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *policyService) DeleteCollection(
+	ctx context.Context,
+	username string,
+	names []string,
+	opts metav1.DeleteOptions,
+) error {
+	if err := requireAdmin(ctx, s.store); err != nil {
+		return err
+	}
+
+	if err := s.store.Policies().DeleteCollection(ctx, username, names, opts); err != nil {
+		return errors.WithCode(code.ErrDatabase, err.Error())
+	}
+
+	return nil
+}
+
+func (s *policyService) Get(ctx context.Context, username, name string, opts metav1.GetOptions) (*v1.Policy, error) {
+	policy, err := s.store.Policies().Get(ctx, username, name, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+func (s *policyService) List(ctx context.Context, username string, opts metav1.ListOptions) (*v1.PolicyList, error) {
+	policies, err := s.store.Policies().List(ctx, username, opts)
+	if err != nil {
+		return nil, listError(err)
+	}
+
+	return policies, nil
+}
+
+// Authorize decides the request with the policies of all the administrators. Only the administrators
+// can write policies, the policies of the other users, e.g. written before they were demoted, are
+// ignored, otherwise they could grant or deny anything to anyone.
+func (s *policyService) Authorize(ctx context.Context, req *v1.AuthzRequest) (*v1.AuthzResponse, error) {
+	var (
+		policies []*v1.Policy
+		offset   int64
+		limit    int64 = gormtool.DefaultLimit
+
+		// owners caches whether the owners of the policies are administrators.
+		owners = map[string]bool{}
+	)
+
+	for {
+		page, err := s.store.Policies().List(ctx, "", metav1.ListOptions{Offset: &offset, Limit: &limit})
+		if err != nil {
+			return nil, errors.WithCode(code.ErrDatabase, err.Error())
+		}
+
+		for _, policy := range page.Items {
+			admin, err := s.ownedByAdmin(ctx, owners, policy)
+			if err != nil {
+				return nil, err
+			}
+
+			if admin {
+				policies = append(policies, policy)
+			}
+		}
+
+		if int64(len(page.Items)) < limit {
+			break
+		}
+
+		offset += limit
+	}
+
+	return authorizer.Authorize(policies, req), nil
+}
+
+// ownedByAdmin returns whether the owner of the policy is an administrator, the results are cached in owners.
+func (s *policyService) ownedByAdmin(ctx context.Context, owners map[string]bool, policy *v1.Policy) (bool, error) {
+	if admin, ok := owners[policy.Username]; ok {
+		return admin, nil
+	}
+
+	user, err := s.store.Users().Get(ctx, policy.Username, metav1.GetOptions{})
+	if err != nil && !errors.IsCode(err, code.ErrUserNotFound) {
+		return false, err
+	}
+
+	owners[policy.Username] = err == nil && user.IsAdministrator()
+
+	return owners[policy.Username], nil
+}
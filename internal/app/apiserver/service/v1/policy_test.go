@@ -0,0 +1,125 @@
+package v1
+
+import (
+	"context"
+	"testing"
+
+	"gobackend/pkg/errors"
+	metav1 "gobackend/pkg/meta/v1"
+
+	"gobackend/internal/app/apiserver/store"
+	"gobackend/internal/app/apiserver/store/fake"
+	"gobackend/internal/pkg/code"
+	v1 "gobackend/internal/pkg/entity/apiserver/v1"
+	"gobackend/internal/pkg/middleware"
+)
+
+// newPolicyStore returns a fake store with alice, an administrator, and bob.
+func newPolicyStore(t *testing.T) store.Factory {
+	t.Helper()
+
+	ds := fake.New()
+
+	for _, name := range []string{"alice", "bob"} {
+		user := &v1.User{Nickname: name, Password: "Passw0rd!", Email: name + "@example.com"}
+		user.Name = name
+
+		if name == "alice" {
+			user.IsAdmin = 1
+		}
+
+		if err := ds.Users().Create(context.Background(), user, metav1.CreateOptions{}); err != nil {
+			t.Fatalf("create user error = %v", err)
+		}
+	}
+
+	return ds
+}
+
+func newPolicy(owner, name, effect string, subjects ...string) *v1.Policy {
+	policy := &v1.Policy{
+		Username:  owner,
+		Effect:    effect,
+		Subjects:  subjects,
+		Actions:   []string{"*"},
+		Resources: []string{"*"},
+	}
+	policy.Name = name
+
+	return policy
+}
+
+func TestPolicyAuthorizeIgnoresNonAdminPolicies(t *testing.T) {
+	ctx := context.Background()
+	req := &v1.AuthzRequest{Subject: "carol", Action: "delete", Resource: "users:carol"}
+
+	tests := []struct {
+		name     string
+		policies []*v1.Policy
+		want     bool
+	}{
+		{"no policy", nil, false},
+		{"admin allows", []*v1.Policy{newPolicy("alice", "allow-carol", v1.AllowAccess, "carol")}, true},
+		{"admin allows another subject", []*v1.Policy{newPolicy("alice", "allow-bob", v1.AllowAccess, "bob")}, false},
+		{
+			"admin denies",
+			[]*v1.Policy{
+				newPolicy("alice", "allow-carol", v1.AllowAccess, "carol"),
+				newPolicy("alice", "deny-all", v1.DenyAccess, "*"),
+			},
+			false,
+		},
+		{"non-admin allows everyone", []*v1.Policy{newPolicy("bob", "allow-all", v1.AllowAccess, "*")}, false},
+		{
+			"non-admin denies everyone",
+			[]*v1.Policy{
+				newPolicy("alice", "allow-carol", v1.AllowAccess, "carol"),
+				newPolicy("bob", "deny-all", v1.DenyAccess, "*"),
+			},
+			true,
+		},
+		{"deleted owner allows everyone", []*v1.Policy{newPolicy("dave", "allow-all", v1.AllowAccess, "*")}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ds := newPolicyStore(t)
+
+			// The policies are written to the store directly, as if they were written before
+			// the policy writes required the administrators.
+			for _, policy := range tt.policies {
+				if err := ds.Policies().Create(ctx, policy, metav1.CreateOptions{}); err != nil {
+					t.Fatalf("create policy error = %v", err)
+				}
+			}
+
+			resp, err := NewService(ds).Policies().Authorize(ctx, req)
+			if err != nil {
+				t.Fatalf("Authorize() error = %v", err)
+			}
+
+			if resp.Allowed != tt.want {
+				t.Errorf("Authorize() = %+v, want allowed %v", resp, tt.want)
+			}
+		})
+	}
+}
+
+func TestPolicyCreateRequiresAdmin(t *testing.T) {
+	srv := NewService(newPolicyStore(t)).Policies()
+
+	for _, username := range []string{"", "bob"} {
+		ctx := context.WithValue(context.Background(), middleware.UsernameKey, username) //nolint:staticcheck
+
+		err := srv.Create(ctx, newPolicy(username, "allow-all", v1.AllowAccess, "*"), metav1.CreateOptions{})
+		if !errors.IsCode(err, code.ErrPermissionDenied) {
+			t.Errorf("Create() by %q error = %v, want ErrPermissionDenied", username, err)
+		}
+	}
+
+	ctx := context.WithValue(context.Background(), middleware.UsernameKey, "alice") //nolint:staticcheck
+
+	if err := srv.Create(ctx, newPolicy("alice", "allow-all", v1.AllowAccess, "*"), metav1.CreateOptions{}); err != nil {
+		t.Errorf("Create() by alice error = %v", err)
+	}
+}
@@ -0,0 +1,120 @@
+package v1
+
+import (
+	"context"
+
+	"gobackend/pkg/errors"
+	metav1 "gobackend/pkg/meta/v1"
+	"gobackend/pkg/util/idtool"
+
+	"gobackend/internal/app/apiserver/store"
+	"gobackend/internal/pkg/code"
+	v1 "gobackend/internal/pkg/entity/apiserver/v1"
+)
+
+// MaxSecretCount defines the maximum number of secrets a user can own.
+const MaxSecretCount = 10
+
+// SecretSrv defines functions used to handle secret request.
+type SecretSrv interface {
+	Create(ctx context.Context, secret *v1.Secret, opts metav1.CreateOptions) error
+	Update(ctx context.Context, secret *v1.Secret, opts metav1.UpdateOptions) error
+	Delete(ctx context.Context, username, name string, opts metav1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, username string, names []string, opts metav1.DeleteOptions) error
+	Get(ctx context.Context, username, name string, opts metav1.GetOptions) (*v1.Secret, error)
+	List(ctx context.Context, username string, opts metav1.ListOptions) (*v1.SecretList, error)
+}
+
+type secretService struct {
+	store store.Factory
+}
+
+var _ SecretSrv = (*secretService)(nil)
+
+func newSecrets(srv *service) *secretService {
+	return &secretService{store: srv.store}
+}
+
+func (s *secretService) Create(ctx context.Context, secret *v1.Secret, opts metav1.CreateOptions) error {
+	secret.SecretID = idtool.NewSecretID()
+	secret.SecretKey = idtool.NewSecretKey()
+
+	// The owner is locked so that the concurrent creations of the owner can not exceed the limit.
+	return s.store.Tx(ctx, func(tx store.Factory) error {
+		if _, err := tx.Users().Get(ctx, secret.Username, metav1.GetOptions{ForUpdate: true}); err != nil {
+			return err
+		}
+
+		secrets, err := tx.Secrets().List(ctx, secret.Username, metav1.ListOptions{})
+		if err != nil {
+			return errors.WithCode(code.ErrDatabase, err.Error())
+		}
+
+		if secrets.TotalCount >= MaxSecretCount {
+			return errors.WithCode(code.ErrReachMaxCount, "secret count: %d", secrets.TotalCount)
+		}
+
+		for _, item := range secrets.Items {
+			if item.Name == secret.Name {
+				return errors.WithCode(code.ErrSecretAlreadyExist, "secret `%s` already exist", secret.Name)
+			}
+		}
+
+		if err := tx.Secrets().Create(ctx, secret, opts); err != nil {
+			if errors.IsCode(err, code.ErrSecretAlreadyExist) {
+				return err
+			}
+
+			return errors.WithCode(code.ErrDatabase, err.Error())
+		}
+
+		return nil
+	})
+}
+
+func (s *secretService) Update(ctx context.Context, secret *v1.Secret, opts metav1.UpdateOptions) error {
+	if err := s.store.Secrets().Update(ctx, secret, opts); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *secretService) Delete(ctx context.Context, username, name string, opts metav1.DeleteOptions) error {
+	if err := s.store.Secrets().Delete(ctx, username, name, opts); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *secretService) DeleteCollection(
+	ctx context.Context,
+	username string,
+	names []string,
+	opts metav1.DeleteOptions,
+) error {
+	if err := s.store.Secrets().DeleteCollection(ctx, username, names, opts); err != nil {
+		return errors.WithCode(code.ErrDatabase, err.Error())
+	}
+
+	return nil
+}
+
+func (s *secretService) Get(ctx context.Context, username, name string, opts metav1.GetOptions) (*v1.Secret, error) {
+	secret, err := s.store.Secrets().Get(ctx, username, name, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return secret, nil
+}
+
+func (s *secretService) List(ctx context.Context, username string, opts metav1.ListOptions) (*v1.SecretList, error) {
+	secrets, err := s.store.Secrets().List(ctx, username, opts)
+	if err != nil {
+		return nil, listError(err)
+	}
+
+	return secrets, nil
+}
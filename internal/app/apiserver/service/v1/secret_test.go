@@ -0,0 +1,85 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"gobackend/pkg/errors"
+	metav1 "gobackend/pkg/meta/v1"
+
+	"gobackend/internal/pkg/code"
+	v1 "gobackend/internal/pkg/entity/apiserver/v1"
+)
+
+func newSecret(owner, name string) *v1.Secret {
+	secret := &v1.Secret{Username: owner}
+	secret.Name = name
+
+	return secret
+}
+
+func TestSecretCreate(t *testing.T) {
+	ctx := context.Background()
+	srv := NewService(newPolicyStore(t)).Secrets()
+
+	if err := srv.Create(ctx, newSecret("bob", "first"), metav1.CreateOptions{}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	err := srv.Create(ctx, newSecret("bob", "first"), metav1.CreateOptions{})
+	if !errors.IsCode(err, code.ErrSecretAlreadyExist) {
+		t.Errorf("Create() duplicate error = %v, want ErrSecretAlreadyExist", err)
+	}
+
+	err = srv.Create(ctx, newSecret("carol", "first"), metav1.CreateOptions{})
+	if !errors.IsCode(err, code.ErrUserNotFound) {
+		t.Errorf("Create() unknown owner error = %v, want ErrUserNotFound", err)
+	}
+}
+
+func TestSecretCreateConcurrently(t *testing.T) {
+	ctx := context.Background()
+	srv := NewService(newPolicyStore(t)).Secrets()
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		created int
+	)
+
+	for i := 0; i < 2*MaxSecretCount; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			err := srv.Create(ctx, newSecret("bob", fmt.Sprintf("secret-%d", i)), metav1.CreateOptions{})
+			if err != nil && !errors.IsCode(err, code.ErrReachMaxCount) {
+				t.Errorf("Create() error = %v", err)
+			}
+
+			if err == nil {
+				mu.Lock()
+				created++
+				mu.Unlock()
+			}
+		}(i)
+	}
+
+	wg.Wait()
+
+	if created != MaxSecretCount {
+		t.Errorf("created %d secrets, want %d", created, MaxSecretCount)
+	}
+
+	secrets, err := srv.List(ctx, "bob", metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+
+	if secrets.TotalCount != MaxSecretCount {
+		t.Errorf("List() total = %d, want %d", secrets.TotalCount, MaxSecretCount)
+	}
+}
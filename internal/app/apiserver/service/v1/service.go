@@ -1,10 +1,18 @@
 package v1
 
-import "gobackend/internal/app/apiserver/store"
+import (
+	"gobackend/pkg/errors"
+
+	"gobackend/internal/app/apiserver/store"
+	"gobackend/internal/pkg/code"
+)
 
 // Service defines functions used to return resource interface.
 type Service interface {
 	Users() UserSrv
+	Secrets() SecretSrv
+	Policies() PolicySrv
+	OperationLogs() OperationLogSrv
 }
 
 type service struct {
@@ -21,3 +29,28 @@ func NewService(store store.Factory) Service {
 func (s *service) Users() UserSrv {
 	return newUsers(s)
 }
+
+func (s *service) Secrets() SecretSrv {
+	return newSecrets(s)
+}
+
+func (s *service) Policies() PolicySrv {
+	return newPolicies(s)
+}
+
+func (s *service) OperationLogs() OperationLogSrv {
+	return newOperationLogs(s)
+}
+
+// listError returns the error of a list call of the store, the invalid list options are
+// reported as they are, other errors are database errors.
+func listError(err error) error {
+	if errors.IsCode(err, code.ErrValidation) ||
+		errors.IsCode(err, code.ErrFieldSelectorValidation) ||
+		errors.IsCode(err, code.ErrLabelSelectorValidation) ||
+		errors.IsCode(err, code.ErrContinueValidation) {
+		return err
+	}
+
+	return errors.WithCode(code.ErrDatabase, err.Error())
+}
@@ -36,7 +36,18 @@ func newUsers(srv *service) *userService {
 }
 
 func (u *userService) Create(ctx context.Context, user *v1.User, opts metav1.CreateOptions) error {
+	// Only administrators can grant the administrator role.
+	if user.IsAdmin != 0 {
+		if err := requireAdmin(ctx, u.store); err != nil {
+			return err
+		}
+	}
+
 	if err := u.store.Users().Create(ctx, user, opts); err != nil {
+		if errors.IsCode(err, code.ErrUserAlreadyExist) {
+			return err
+		}
+
 		return errors.WithCode(code.ErrDatabase, err.Error())
 	}
 
@@ -44,22 +55,94 @@ func (u *userService) Create(ctx context.Context, user *v1.User, opts metav1.Cre
 }
 
 func (u *userService) DeleteCollection(ctx context.Context, usernames []string, opts metav1.DeleteOptions) error {
-	if err := u.store.Users().DeleteCollection(ctx, usernames, opts); err != nil {
-		return errors.WithCode(code.ErrDatabase, err.Error())
+	if err := requireAdmin(ctx, u.store); err != nil {
+		return err
 	}
 
-	return nil
+	return u.store.Tx(ctx, func(tx store.Factory) error {
+		for _, username := range usernames {
+			if err := deleteUserResources(ctx, tx, username, opts); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Users().DeleteCollection(ctx, usernames, opts); err != nil {
+			return errors.WithCode(code.ErrDatabase, err.Error())
+		}
+
+		return nil
+	})
 }
 
 func (u *userService) Delete(ctx context.Context, username string, opts metav1.DeleteOptions) error {
-	if err := u.store.Users().Delete(ctx, username, opts); err != nil {
+	if err := requireAdmin(ctx, u.store); err != nil {
 		return err
 	}
 
+	return u.store.Tx(ctx, func(tx store.Factory) error {
+		if err := deleteUserResources(ctx, tx, username, opts); err != nil {
+			return err
+		}
+
+		return tx.Users().Delete(ctx, username, opts)
+	})
+}
+
+// deleteUserResources deletes the secrets and policies owned by the user.
+func deleteUserResources(ctx context.Context, f store.Factory, username string, opts metav1.DeleteOptions) error {
+	// Nothing is deleted by a dry run, the loops below would never end.
+	if metav1.IsDryRun(opts.DryRun) {
+		return nil
+	}
+
+	for {
+		secrets, err := f.Secrets().List(ctx, username, metav1.ListOptions{})
+		if err != nil {
+			return errors.WithCode(code.ErrDatabase, err.Error())
+		}
+
+		if len(secrets.Items) == 0 {
+			break
+		}
+
+		names := make([]string, 0, len(secrets.Items))
+		for _, secret := range secrets.Items {
+			names = append(names, secret.Name)
+		}
+
+		if err := f.Secrets().DeleteCollection(ctx, username, names, opts); err != nil {
+			return errors.WithCode(code.ErrDatabase, err.Error())
+		}
+	}
+
+	for {
+		policies, err := f.Policies().List(ctx, username, metav1.ListOptions{})
+		if err != nil {
+			return errors.WithCode(code.ErrDatabase, err.Error())
+		}
+
+		if len(policies.Items) == 0 {
+			break
+		}
+
+		names := make([]string, 0, len(policies.Items))
+		for _, policy := range policies.Items {
+			names = append(names, policy.Name)
+		}
+
+		if err := f.Policies().DeleteCollection(ctx, username, names, opts); err != nil {
+			return errors.WithCode(code.ErrDatabase, err.Error())
+		}
+	}
+
 	return nil
 }
 
 func (u *userService) Get(ctx context.Context, username string, opts metav1.GetOptions) (*v1.User, error) {
+	if err := requireSelfOrAdmin(ctx, u.store, username); err != nil {
+		return nil, err
+	}
+
 	user, err := u.store.Users().Get(ctx, username, opts)
 	if err != nil {
 		return nil, err
@@ -69,17 +152,46 @@ func (u *userService) Get(ctx context.Context, username string, opts metav1.GetO
 }
 
 func (u *userService) List(ctx context.Context, opts metav1.ListOptions) (*v1.UserList, error) {
+	if err := requireAdmin(ctx, u.store); err != nil {
+		return nil, err
+	}
+
 	users, err := u.store.Users().List(ctx, opts)
 	if err != nil {
-		return nil, errors.WithCode(code.ErrDatabase, err.Error())
+		return nil, listError(err)
+	}
+
+	usernames := make([]string, 0, len(users.Items))
+	for _, user := range users.Items {
+		usernames = append(usernames, user.Name)
+	}
+
+	counts, err := u.store.Policies().CountByUsers(ctx, usernames)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, user := range users.Items {
+		user.TotalPolicy = counts[user.Name]
 	}
 
 	return users, nil
 }
 
 func (u *userService) Update(ctx context.Context, user *v1.User, opts metav1.UpdateOptions) error {
+	if err := requireSelfOrAdmin(ctx, u.store, user.Name); err != nil {
+		return err
+	}
+
+	// Normal users can not grant the administrator role to themselves.
+	if user.IsAdmin != 0 {
+		if err := requireAdmin(ctx, u.store); err != nil {
+			return err
+		}
+	}
+
 	if err := u.store.Users().Update(ctx, user, opts); err != nil {
-		return errors.WithCode(code.ErrDatabase, err.Error())
+		return err
 	}
 
 	return nil
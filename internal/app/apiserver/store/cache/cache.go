@@ -0,0 +1,177 @@
+// Package cache implements a read-through cache of store.Factory in redis. The users got and
+// listed are cached, every write of the users invalidates all the cached ones by increasing
+// the generation in the keys, so that a value read before a write is never served after it.
+package cache
+
+import (
+	"context"
+	"crypto/sha1" // nolint: gosec
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+
+	"gobackend/pkg/json"
+	"gobackend/pkg/log"
+
+	"gobackend/internal/app/apiserver/store"
+)
+
+// keyPrefix is the prefix of all the keys of the cache.
+const keyPrefix = "gobackend:apiserver:"
+
+// usersGenerationKey holds the generation of the cached users, it never expires.
+const usersGenerationKey = keyPrefix + "users:generation"
+
+// invalidateTimeout limits the invalidation of the cache after a write.
+const invalidateTimeout = 3 * time.Second
+
+type datastore struct {
+	store.Factory
+
+	client redis.UniversalClient
+	ttl    time.Duration
+}
+
+var _ store.Factory = (*datastore)(nil)
+
+// New wraps the store with a cache in redis, the cached entries expire after ttl.
+// The cache is best effort, the store is read when redis fails.
+func New(s store.Factory, client redis.UniversalClient, ttl time.Duration) store.Factory {
+	return &datastore{
+		Factory: s,
+		client:  client,
+		ttl:     ttl,
+	}
+}
+
+func (ds *datastore) Users() store.UserStore {
+	return &users{ds: ds, store: ds.Factory.Users()}
+}
+
+// Tx runs fn in a transaction of the store. The reads in the transaction bypass the cache,
+// and the cache is invalidated after the transaction if the users are written.
+func (ds *datastore) Tx(ctx context.Context, fn func(store.Factory) error) error {
+	tx := &txDatastore{ds: ds}
+
+	defer func() {
+		if tx.usersWritten {
+			ds.invalidateUsers(ctx)
+		}
+	}()
+
+	return ds.Factory.Tx(ctx, func(f store.Factory) error {
+		tx.Factory = f
+
+		return fn(tx)
+	})
+}
+
+// txDatastore is the store passed to the functions run in transactions.
+type txDatastore struct {
+	store.Factory
+
+	ds           *datastore
+	usersWritten bool
+}
+
+func (tx *txDatastore) Users() store.UserStore {
+	return &users{ds: tx.ds, store: tx.Factory.Users(), tx: tx}
+}
+
+// Tx runs fn in the same transaction, as the stores do.
+func (tx *txDatastore) Tx(ctx context.Context, fn func(store.Factory) error) error {
+	return fn(tx)
+}
+
+// generation returns the current generation of the cached users.
+func (ds *datastore) generation(ctx context.Context) (int64, error) {
+	gen, err := ds.client.Get(ctx, usersGenerationKey).Int64()
+	if err == redis.Nil {
+		return 0, nil
+	}
+
+	return gen, err
+}
+
+// detachedContext keeps the values of the parent context, e.g. the request id logged, but not
+// its deadline and cancellation.
+type detachedContext struct {
+	context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (detachedContext) Err() error {
+	return nil
+}
+
+// invalidateUsers invalidates all the cached users. It runs after the write is committed, so it
+// is not canceled with ctx, e.g. when the client of the request goes away, otherwise the stale
+// users would be served until they expire.
+func (ds *datastore) invalidateUsers(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(detachedContext{ctx}, invalidateTimeout)
+	defer cancel()
+
+	if err := ds.client.Incr(ctx, usersGenerationKey).Err(); err != nil {
+		log.C(ctx).Warnf("invalidate the cached users failed, they may be stale for %s: %s", ds.ttl, err)
+	}
+}
+
+// usersKey returns the key of the cached users in the generation, parts identify the entry.
+func usersKey(gen int64, kind string, id string) string {
+	return fmt.Sprintf("%susers:%d:%s:%s", keyPrefix, gen, kind, id)
+}
+
+// hash returns the hex encoded hash of the JSON encoding of v.
+func hash(v interface{}) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha1.Sum(data) // nolint: gosec
+
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// get reads the cached value of key into v, it reports whether the value is found.
+func (ds *datastore) get(ctx context.Context, key string, v interface{}) bool {
+	data, err := ds.client.Get(ctx, key).Bytes()
+	if err != nil {
+		if err != redis.Nil {
+			log.C(ctx).Warnf("read cache %s failed: %s", key, err)
+		}
+
+		return false
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		log.C(ctx).Warnf("decode cache %s failed: %s", key, err)
+
+		return false
+	}
+
+	return true
+}
+
+// set caches v as the value of key.
+func (ds *datastore) set(ctx context.Context, key string, v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		log.C(ctx).Warnf("encode cache %s failed: %s", key, err)
+
+		return
+	}
+
+	if err := ds.client.Set(ctx, key, data, ds.ttl).Err(); err != nil {
+		log.C(ctx).Warnf("write cache %s failed: %s", key, err)
+	}
+}
@@ -0,0 +1,120 @@
+package cache
+
+import (
+	"context"
+
+	"gobackend/pkg/log"
+	metav1 "gobackend/pkg/meta/v1"
+
+	"gobackend/internal/app/apiserver/store"
+	v1 "gobackend/internal/pkg/entity/apiserver/v1"
+)
+
+// users caches the users got and listed. The cached users have the password hashes,
+// which are compared by the authentication.
+type users struct {
+	ds    *datastore
+	store store.UserStore
+
+	// tx is set in transactions, the cache is bypassed and invalidated after the transaction.
+	tx *txDatastore
+}
+
+var _ store.UserStore = (*users)(nil)
+
+func (u *users) Create(ctx context.Context, user *v1.User, opts metav1.CreateOptions) error {
+	return u.write(ctx, u.store.Create(ctx, user, opts))
+}
+
+func (u *users) Update(ctx context.Context, user *v1.User, opts metav1.UpdateOptions) error {
+	return u.write(ctx, u.store.Update(ctx, user, opts))
+}
+
+func (u *users) Delete(ctx context.Context, username string, opts metav1.DeleteOptions) error {
+	return u.write(ctx, u.store.Delete(ctx, username, opts))
+}
+
+func (u *users) DeleteCollection(ctx context.Context, usernames []string, opts metav1.DeleteOptions) error {
+	return u.write(ctx, u.store.DeleteCollection(ctx, usernames, opts))
+}
+
+// write invalidates the cache after a write of the store. The dry runs are invalidated too,
+// which is harmless.
+func (u *users) write(ctx context.Context, err error) error {
+	if err != nil {
+		return err
+	}
+
+	if u.tx != nil {
+		u.tx.usersWritten = true
+
+		return nil
+	}
+
+	u.ds.invalidateUsers(ctx)
+
+	return nil
+}
+
+func (u *users) Get(ctx context.Context, username string, opts metav1.GetOptions) (*v1.User, error) {
+	if u.tx != nil {
+		return u.store.Get(ctx, username, opts)
+	}
+
+	gen, err := u.ds.generation(ctx)
+	if err != nil {
+		log.C(ctx).Warnf("read the generation of the cached users failed: %s", err)
+
+		return u.store.Get(ctx, username, opts)
+	}
+
+	key := usersKey(gen, "get", username)
+
+	var user v1.User
+	if u.ds.get(ctx, key, &user) {
+		return &user, nil
+	}
+
+	got, err := u.store.Get(ctx, username, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	u.ds.set(ctx, key, got)
+
+	return got, nil
+}
+
+func (u *users) List(ctx context.Context, opts metav1.ListOptions) (*v1.UserList, error) {
+	if u.tx != nil {
+		return u.store.List(ctx, opts)
+	}
+
+	gen, err := u.ds.generation(ctx)
+	if err != nil {
+		log.C(ctx).Warnf("read the generation of the cached users failed: %s", err)
+
+		return u.store.List(ctx, opts)
+	}
+
+	id, err := hash(opts)
+	if err != nil {
+		return u.store.List(ctx, opts)
+	}
+
+	key := usersKey(gen, "list", id)
+
+	var list v1.UserList
+	if u.ds.get(ctx, key, &list) {
+		return &list, nil
+	}
+
+	got, err := u.store.List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	u.ds.set(ctx, key, got)
+
+	return got, nil
+}
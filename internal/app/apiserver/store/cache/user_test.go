@@ -0,0 +1,187 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+
+	metav1 "gobackend/pkg/meta/v1"
+
+	"gobackend/internal/app/apiserver/store"
+	"gobackend/internal/app/apiserver/store/fake"
+	v1 "gobackend/internal/pkg/entity/apiserver/v1"
+)
+
+func newUser(name string) *v1.User {
+	user := &v1.User{
+		Nickname: name,
+		Password: "Passw0rd!",
+		Email:    name + "@example.com",
+	}
+	user.Name = name
+
+	return user
+}
+
+// newStore returns the fake store with alice, and the store wrapped with the cache in miniredis.
+func newStore(t *testing.T) (store.Factory, store.Factory, *miniredis.Miniredis) {
+	t.Helper()
+
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("miniredis.Run() error = %v", err)
+	}
+
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+
+	t.Cleanup(func() {
+		client.Close()
+		mr.Close()
+	})
+
+	ds := fake.New()
+	if err := ds.Users().Create(context.Background(), newUser("alice"), metav1.CreateOptions{}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	return ds, New(ds, client, time.Minute), mr
+}
+
+func TestUsersGet(t *testing.T) {
+	ctx := context.Background()
+	ds, cached, mr := newStore(t)
+
+	if _, err := cached.Users().Get(ctx, "alice", metav1.GetOptions{}); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	// The cached user is served even if the store is changed behind the cache.
+	if err := ds.Users().Delete(ctx, "alice", metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	user, err := cached.Users().Get(ctx, "alice", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Get() cached error = %v", err)
+	}
+
+	if user.Name != "alice" || user.Compare("Passw0rd!") != nil {
+		t.Errorf("Get() cached = %+v", user)
+	}
+
+	// The entries expire.
+	mr.FastForward(2 * time.Minute)
+
+	if _, err := cached.Users().Get(ctx, "alice", metav1.GetOptions{}); err == nil {
+		t.Error("Get() after expiration error = nil")
+	}
+}
+
+func TestUsersInvalidate(t *testing.T) {
+	ctx := context.Background()
+	_, cached, _ := newStore(t)
+	s := cached.Users()
+
+	count := func() int64 {
+		list, err := s.List(ctx, metav1.ListOptions{})
+		if err != nil {
+			t.Fatalf("List() error = %v", err)
+		}
+
+		return list.TotalCount
+	}
+
+	if got := count(); got != 1 {
+		t.Fatalf("List() total = %d, want 1", got)
+	}
+
+	if err := s.Create(ctx, newUser("bob"), metav1.CreateOptions{}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	if got := count(); got != 2 {
+		t.Errorf("List() after Create() total = %d, want 2", got)
+	}
+
+	user, _ := s.Get(ctx, "bob", metav1.GetOptions{})
+	user.Nickname = "robert"
+
+	if err := s.Update(ctx, user, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	if got, _ := s.Get(ctx, "bob", metav1.GetOptions{}); got.Nickname != "robert" {
+		t.Errorf("Get() after Update() nickname = %s, want robert", got.Nickname)
+	}
+
+	if err := s.Delete(ctx, "bob", metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	if _, err := s.Get(ctx, "bob", metav1.GetOptions{}); err == nil {
+		t.Error("Get() after Delete() error = nil")
+	}
+
+	if got := count(); got != 1 {
+		t.Errorf("List() after Delete() total = %d, want 1", got)
+	}
+}
+
+func TestUsersInvalidateCanceled(t *testing.T) {
+	_, cached, _ := newStore(t)
+
+	if _, err := cached.Users().Get(context.Background(), "alice", metav1.GetOptions{}); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	// The request is canceled after the write is committed, the cache is invalidated anyway.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := cached.Users().Delete(ctx, "alice", metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	if _, err := cached.Users().Get(context.Background(), "alice", metav1.GetOptions{}); err == nil {
+		t.Error("Get() after Delete() with a canceled context error = nil")
+	}
+}
+
+func TestUsersTx(t *testing.T) {
+	ctx := context.Background()
+	_, cached, _ := newStore(t)
+
+	if _, err := cached.Users().Get(ctx, "alice", metav1.GetOptions{}); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	err := cached.Tx(ctx, func(tx store.Factory) error {
+		return tx.Users().Delete(ctx, "alice", metav1.DeleteOptions{})
+	})
+	if err != nil {
+		t.Fatalf("Tx() error = %v", err)
+	}
+
+	if _, err := cached.Users().Get(ctx, "alice", metav1.GetOptions{}); err == nil {
+		t.Error("Get() after the transaction error = nil")
+	}
+}
+
+func TestUsersRedisDown(t *testing.T) {
+	ctx := context.Background()
+	_, cached, mr := newStore(t)
+
+	mr.Close()
+
+	// The store is read when redis fails.
+	if _, err := cached.Users().Get(ctx, "alice", metav1.GetOptions{}); err != nil {
+		t.Errorf("Get() error = %v", err)
+	}
+
+	if err := cached.Users().Create(ctx, newUser("bob"), metav1.CreateOptions{}); err != nil {
+		t.Errorf("Create() error = %v", err)
+	}
+}
@@ -0,0 +1,296 @@
+// Package fake implements store.Factory in memory, it is used by tests and
+// local development which don't want to depend on a live database.
+package fake
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"sync"
+
+	"gobackend/pkg/errors"
+	"gobackend/pkg/labels"
+	metav1 "gobackend/pkg/meta/v1"
+	"gobackend/pkg/util/gormtool"
+
+	"gobackend/internal/app/apiserver/store"
+	"gobackend/internal/pkg/code"
+	"gobackend/internal/pkg/entity/apiserver/operationlog"
+	v1 "gobackend/internal/pkg/entity/apiserver/v1"
+)
+
+type datastore struct {
+	lock sync.RWMutex
+
+	// txLock serializes the transactions.
+	txLock sync.Mutex
+
+	// ids holds the last auto increment id of each table.
+	ids map[string]uint64
+
+	// Records are ordered by id ascending, soft deleted records are kept with a valid DeletedAt.
+	users         []*v1.User
+	secrets       []*v1.Secret
+	policies      []*v1.Policy
+	operationLogs []*operationlog.OperationLog
+}
+
+var _ store.Factory = (*datastore)(nil)
+
+// New returns an empty in-memory store.Factory.
+func New() store.Factory {
+	return &datastore{
+		ids: map[string]uint64{},
+	}
+}
+
+func (ds *datastore) Users() store.UserStore {
+	return newUsers(ds)
+}
+
+func (ds *datastore) Secrets() store.SecretStore {
+	return newSecrets(ds)
+}
+
+func (ds *datastore) Policies() store.PolicyStore {
+	return newPolicies(ds)
+}
+
+func (ds *datastore) OperationLogs() store.OperationLogStore {
+	return newOperationLogs(ds)
+}
+
+// Tx runs fn with the datastore and restores the data if fn fails.
+// Transactions are serialized, but they are not isolated from the calls outside
+// of transactions, which are rolled back together on failure.
+func (ds *datastore) Tx(ctx context.Context, fn func(store.Factory) error) (err error) {
+	ds.txLock.Lock()
+	defer ds.txLock.Unlock()
+
+	saved := ds.snapshot()
+
+	defer func() {
+		if r := recover(); r != nil {
+			ds.restore(saved)
+			panic(r)
+		}
+	}()
+
+	if err = fn(ds); err != nil {
+		ds.restore(saved)
+	}
+
+	return err
+}
+
+func (ds *datastore) Ping(ctx context.Context) error {
+	return nil
+}
+
+func (ds *datastore) Close() error {
+	return nil
+}
+
+// snapshot returns a deep copy of the data.
+func (ds *datastore) snapshot() *datastore {
+	ds.lock.RLock()
+	defer ds.lock.RUnlock()
+
+	saved := &datastore{
+		ids:           make(map[string]uint64, len(ds.ids)),
+		users:         make([]*v1.User, 0, len(ds.users)),
+		secrets:       make([]*v1.Secret, 0, len(ds.secrets)),
+		policies:      make([]*v1.Policy, 0, len(ds.policies)),
+		operationLogs: make([]*operationlog.OperationLog, 0, len(ds.operationLogs)),
+	}
+
+	for table, id := range ds.ids {
+		saved.ids[table] = id
+	}
+
+	for _, item := range ds.users {
+		record := *item
+		saved.users = append(saved.users, &record)
+	}
+
+	for _, item := range ds.secrets {
+		record := *item
+		saved.secrets = append(saved.secrets, &record)
+	}
+
+	for _, item := range ds.policies {
+		record := *item
+		saved.policies = append(saved.policies, &record)
+	}
+
+	for _, item := range ds.operationLogs {
+		record := *item
+		saved.operationLogs = append(saved.operationLogs, &record)
+	}
+
+	return saved
+}
+
+// restore replaces the data with a snapshot.
+func (ds *datastore) restore(saved *datastore) {
+	ds.lock.Lock()
+	defer ds.lock.Unlock()
+
+	ds.ids = saved.ids
+	ds.users = saved.users
+	ds.secrets = saved.secrets
+	ds.policies = saved.policies
+	ds.operationLogs = saved.operationLogs
+}
+
+// nextID returns the next auto increment id of the table, the caller must hold the write lock.
+func (ds *datastore) nextID(table string) uint64 {
+	ds.ids[table]++
+
+	return ds.ids[table]
+}
+
+// matcher filters records with a field selector the same way as the database store does,
+// the fields must be in the whitelist, see store.Fields for the supported operators.
+type matcher struct {
+	requirements []store.Requirement
+}
+
+func newMatcher(fieldSelector string, whitelist store.Fields) (*matcher, error) {
+	requirements, err := whitelist.Requirements(fieldSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	return &matcher{requirements: requirements}, nil
+}
+
+// Matches returns true if the field values satisfy all the requirements,
+// values must have all the fields in the whitelist.
+func (m *matcher) Matches(values map[string]interface{}) bool {
+	for _, require := range m.requirements {
+		if !require.Matches(values[require.Field]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// parseLabelSelector parses the label selector the same way as the database store does.
+func parseLabelSelector(labelSelector string) (labels.Selector, error) {
+	selector, err := labels.Parse(labelSelector)
+	if err != nil {
+		return nil, errors.WithCode(code.ErrLabelSelectorValidation, err.Error())
+	}
+
+	return selector, nil
+}
+
+// labelSet returns the labels of a stored record, which only keeps the shadow of them.
+func labelSet(meta *metav1.ObjectMeta) labels.Set {
+	record := *meta
+	if err := record.UnmarshalLabelsShadow(); err != nil {
+		return nil
+	}
+
+	return record.Labels
+}
+
+// page sorts the matched records, a slice of the records, returns the range of the records
+// in the page and fills meta, the same way as the database store does. fields returns
+// the field values of the i-th record. The fields option is validated, but the records
+// are not trimmed.
+func page(
+	matched interface{},
+	fields func(i int) map[string]interface{},
+	opts metav1.ListOptions,
+	whitelist store.Fields,
+	columns store.Columns,
+	meta *metav1.ListMeta,
+) (start, end int, err error) {
+	order, err := whitelist.Sort(opts.SortBy)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	position, err := order.ParseContinue(opts)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if _, err := columns.Select(opts.Fields); err != nil {
+		return 0, 0, err
+	}
+
+	s := &sorter{order: order, swap: reflect.Swapper(matched)}
+	for i := 0; i < reflect.ValueOf(matched).Len(); i++ {
+		s.values = append(s.values, order.Values(fields(i)))
+	}
+
+	sort.Stable(s)
+
+	total := len(s.values)
+	if store.CountTotal(opts) {
+		meta.TotalCount = int64(total)
+	}
+
+	ol := gormtool.Unpointer(opts.Offset, opts.Limit)
+
+	start, end = ol.Offset, total
+	if position != nil {
+		start = sort.Search(total, func(i int) bool { return order.Compare(s.values[i], position) > 0 })
+	}
+
+	if start < 0 {
+		start = 0
+	}
+
+	if start > total {
+		start = total
+	}
+
+	// Non-positive limit means no limit, the same as gorm.
+	if ol.Limit > 0 && start+ol.Limit < end {
+		end = start + ol.Limit
+		meta.Continue = order.Continue(s.values[end-1])
+	}
+
+	return start, end, nil
+}
+
+// sorter sorts the records by the values of their sort fields.
+type sorter struct {
+	order  store.Sort
+	values [][]interface{}
+	swap   func(i, j int)
+}
+
+func (s *sorter) Len() int           { return len(s.values) }
+func (s *sorter) Less(i, j int) bool { return s.order.Compare(s.values[i], s.values[j]) < 0 }
+
+func (s *sorter) Swap(i, j int) {
+	s.values[i], s.values[j] = s.values[j], s.values[i]
+	s.swap(i, j)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// conflict returns the error of an update whose resource version is out of date,
+// the same as the one returned by the database store.
+func conflict(meta *metav1.ObjectMeta) error {
+	return errors.WithCode(
+		code.ErrResourceConflict,
+		"resource version %d of `%s` is out of date",
+		meta.ResourceVersion,
+		meta.Name,
+	)
+}
@@ -0,0 +1,248 @@
+package fake
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	metav1 "gobackend/pkg/meta/v1"
+
+	"gobackend/internal/app/apiserver/store"
+	"gobackend/internal/pkg/entity/apiserver/operationlog"
+)
+
+type operationLogs struct {
+	ds *datastore
+}
+
+func newOperationLogs(ds *datastore) *operationLogs {
+	return &operationLogs{ds: ds}
+}
+
+// Create creates a new OperationLog.
+func (o *operationLogs) Create(
+	ctx context.Context,
+	operationLog *operationlog.OperationLog,
+	opts metav1.CreateOptions,
+) error {
+	o.ds.lock.Lock()
+	defer o.ds.lock.Unlock()
+
+	now := time.Now()
+	operationLog.ID = o.ds.nextID(operationLog.TableName())
+	operationLog.CreatedAt, operationLog.UpdatedAt = now, now
+
+	record := *operationLog
+	o.ds.operationLogs = append(o.ds.operationLogs, &record)
+
+	return nil
+}
+
+// CreateCollection batch creates the OperationLog records.
+func (o *operationLogs) CreateCollection(
+	ctx context.Context,
+	operationLogs []*operationlog.OperationLog,
+	opts metav1.CreateOptions,
+) error {
+	for _, operationLog := range operationLogs {
+		if err := o.Create(ctx, operationLog, opts); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Delete an OperationLog record.
+func (o *operationLogs) Delete(
+	ctx context.Context,
+	id string,
+	opts metav1.DeleteOptions,
+) error {
+	// The id never matches any record if it is not a number, the same as the mysql store.
+	n, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return nil
+	}
+
+	if metav1.IsDryRun(opts.DryRun) {
+		return nil
+	}
+
+	o.ds.lock.Lock()
+	defer o.ds.lock.Unlock()
+
+	for i, item := range o.ds.operationLogs {
+		if item.ID != n || (item.DeletedAt.Valid && !opts.Unscoped) {
+			continue
+		}
+
+		if opts.Unscoped {
+			o.ds.operationLogs = append(o.ds.operationLogs[:i], o.ds.operationLogs[i+1:]...)
+		} else {
+			item.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+		}
+
+		break
+	}
+
+	return nil
+}
+
+// DeleteCollection batch deletes the OperationLog records.
+func (o *operationLogs) DeleteCollection(
+	ctx context.Context,
+	ids []uint64,
+	opts metav1.DeleteOptions,
+) error {
+	if metav1.IsDryRun(opts.DryRun) {
+		return nil
+	}
+
+	o.ds.lock.Lock()
+	defer o.ds.lock.Unlock()
+
+	deleted := make(map[uint64]bool, len(ids))
+	for _, id := range ids {
+		deleted[id] = true
+	}
+
+	kept := make([]*operationlog.OperationLog, 0, len(o.ds.operationLogs))
+
+	for _, item := range o.ds.operationLogs {
+		if !deleted[item.ID] || (item.DeletedAt.Valid && !opts.Unscoped) {
+			kept = append(kept, item)
+
+			continue
+		}
+
+		if !opts.Unscoped {
+			item.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+			kept = append(kept, item)
+		}
+	}
+
+	o.ds.operationLogs = kept
+
+	return nil
+}
+
+// List OperationLog records.
+func (o *operationLogs) List(
+	ctx context.Context,
+	opts metav1.ListOptions,
+) (*operationlog.List, error) {
+	m, err := newMatcher(opts.FieldSelector, store.OperationLogFields)
+	if err != nil {
+		return nil, err
+	}
+
+	o.ds.lock.RLock()
+	defer o.ds.lock.RUnlock()
+
+	var matched []*operationlog.OperationLog
+
+	for i := len(o.ds.operationLogs) - 1; i >= 0; i-- {
+		item := o.ds.operationLogs[i]
+		if item.DeletedAt.Valid || !m.Matches(operationLogFields(item)) {
+			continue
+		}
+
+		matched = append(matched, item)
+	}
+
+	ret := &operationlog.List{}
+
+	start, end, err := page(
+		matched,
+		func(i int) map[string]interface{} { return operationLogFields(matched[i]) },
+		opts,
+		store.OperationLogFields,
+		store.OperationLogColumns,
+		&ret.ListMeta,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	ret.Items = make([]*operationlog.OperationLog, 0, end-start)
+
+	for _, item := range matched[start:end] {
+		operationLog := *item
+		ret.Items = append(ret.Items, &operationLog)
+	}
+
+	return ret, nil
+}
+
+// Aggregate groups the OperationLog records by the dimensions.
+func (o *operationLogs) Aggregate(
+	ctx context.Context,
+	opts metav1.ListOptions,
+	dimensions []string,
+) ([]*operationlog.Aggregate, error) {
+	m, err := newMatcher(opts.FieldSelector, store.OperationLogFields)
+	if err != nil {
+		return nil, err
+	}
+
+	o.ds.lock.RLock()
+	defer o.ds.lock.RUnlock()
+
+	groups := make(map[string]*operationlog.Aggregate)
+	ret := []*operationlog.Aggregate{}
+
+	for _, item := range o.ds.operationLogs {
+		if item.DeletedAt.Valid || !m.Matches(operationLogFields(item)) {
+			continue
+		}
+
+		values := make([]string, len(dimensions))
+		for i, dimension := range dimensions {
+			values[i] = store.OperationLogDimension(dimension, item)
+		}
+
+		key := strings.Join(values, "\x00")
+
+		group, ok := groups[key]
+		if !ok {
+			group = &operationlog.Aggregate{Group: values}
+			groups[key] = group
+			ret = append(ret, group)
+		}
+
+		latencies := make([]int64, len(store.LatencyBuckets)+1)
+		latencies[store.LatencyBucket(item.ReqLatency)] = 1
+
+		aggregate := &operationlog.Aggregate{
+			Count:      1,
+			SumLatency: item.ReqLatency,
+			MaxLatency: item.ReqLatency,
+			Latencies:  latencies,
+		}
+		if item.HTTPStatus >= 400 {
+			aggregate.ErrorCount = 1
+		}
+
+		group.Merge(aggregate)
+	}
+
+	return ret, nil
+}
+
+// operationLogFields returns the values of the fields in store.OperationLogFields.
+func operationLogFields(operationLog *operationlog.OperationLog) map[string]interface{} {
+	return map[string]interface{}{
+		"id":          operationLog.ID,
+		"username":    operationLog.Username,
+		"client_ip":   operationLog.ClientIP,
+		"req_method":  operationLog.ReqMethod,
+		"req_path":    operationLog.ReqPath,
+		"http_status": operationLog.HTTPStatus,
+		"req_time":    operationLog.ReqTime,
+		"req_latency": operationLog.ReqLatency,
+	}
+}
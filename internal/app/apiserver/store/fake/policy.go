@@ -0,0 +1,255 @@
+package fake
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"gobackend/pkg/errors"
+	metav1 "gobackend/pkg/meta/v1"
+	"gobackend/pkg/util/idtool"
+
+	"gobackend/internal/app/apiserver/store"
+	"gobackend/internal/pkg/code"
+	v1 "gobackend/internal/pkg/entity/apiserver/v1"
+)
+
+type policies struct {
+	ds *datastore
+}
+
+func newPolicies(ds *datastore) *policies {
+	return &policies{ds: ds}
+}
+
+// Create creates a new policy.
+func (p *policies) Create(ctx context.Context, policy *v1.Policy, opts metav1.CreateOptions) error {
+	p.ds.lock.Lock()
+	defer p.ds.lock.Unlock()
+
+	// (username, name) is unique, soft deleted records take part in the check too.
+	for _, item := range p.ds.policies {
+		if item.Username == policy.Username && item.Name == policy.Name {
+			return errors.WithCode(
+				code.ErrPolicyAlreadyExist,
+				"Duplicate entry '%s-%s' for key 'uk_policy_username_name'",
+				policy.Username,
+				policy.Name,
+			)
+		}
+	}
+
+	if err := policy.BeforeCreate(nil); err != nil {
+		return err
+	}
+
+	// A dry run goes through the checks and the hooks, but the record is not stored.
+	if metav1.IsDryRun(opts.DryRun) {
+		policy.ResourceVersion = 0
+
+		return nil
+	}
+
+	now := time.Now()
+	policy.ID = p.ds.nextID(policy.TableName())
+	policy.CreatedAt, policy.UpdatedAt = now, now
+	policy.InstanceID = idtool.GetInstanceID(policy.ID, "policy-")
+
+	if err := policy.BeforeUpdate(nil); err != nil {
+		return err
+	}
+
+	record := *policy
+	record.Extend = nil
+	record.Labels = nil
+	p.ds.policies = append(p.ds.policies, &record)
+
+	return nil
+}
+
+// Update updates a policy if its resource version is not changed.
+func (p *policies) Update(ctx context.Context, policy *v1.Policy, opts metav1.UpdateOptions) error {
+	p.ds.lock.Lock()
+	defer p.ds.lock.Unlock()
+
+	for i, item := range p.ds.policies {
+		if item.ID != policy.ID || item.DeletedAt.Valid {
+			continue
+		}
+
+		if item.ResourceVersion != policy.ResourceVersion {
+			break
+		}
+
+		if err := policy.BeforeUpdate(nil); err != nil {
+			return err
+		}
+
+		if metav1.IsDryRun(opts.DryRun) {
+			return nil
+		}
+
+		policy.UpdatedAt = time.Now()
+		policy.ResourceVersion++
+		record := *policy
+		record.Extend = nil
+		record.Labels = nil
+		p.ds.policies[i] = &record
+
+		return nil
+	}
+
+	return conflict(&policy.ObjectMeta)
+}
+
+// Delete deletes the policy by the policy name.
+func (p *policies) Delete(ctx context.Context, username, name string, opts metav1.DeleteOptions) error {
+	return p.DeleteCollection(ctx, username, []string{name}, opts)
+}
+
+// DeleteCollection batch deletes the policies.
+func (p *policies) DeleteCollection(
+	ctx context.Context,
+	username string,
+	names []string,
+	opts metav1.DeleteOptions,
+) error {
+	if metav1.IsDryRun(opts.DryRun) {
+		return nil
+	}
+
+	p.ds.lock.Lock()
+	defer p.ds.lock.Unlock()
+
+	now := time.Now()
+	kept := p.ds.policies[:0]
+
+	for _, item := range p.ds.policies {
+		if item.Username != username || !contains(names, item.Name) || (item.DeletedAt.Valid && !opts.Unscoped) {
+			kept = append(kept, item)
+
+			continue
+		}
+
+		if !opts.Unscoped {
+			item.DeletedAt = gorm.DeletedAt{Time: now, Valid: true}
+			kept = append(kept, item)
+		}
+	}
+
+	p.ds.policies = kept
+
+	return nil
+}
+
+// Get return a policy by the policy name.
+func (p *policies) Get(ctx context.Context, username, name string, opts metav1.GetOptions) (*v1.Policy, error) {
+	p.ds.lock.RLock()
+	defer p.ds.lock.RUnlock()
+
+	for _, item := range p.ds.policies {
+		if item.Username == username && item.Name == name && !item.DeletedAt.Valid {
+			return copyPolicy(item)
+		}
+	}
+
+	return nil, errors.WithCode(code.ErrPolicyNotFound, gorm.ErrRecordNotFound.Error())
+}
+
+// List returns the policies of the user, all the policies are returned if username is empty.
+func (p *policies) List(ctx context.Context, username string, opts metav1.ListOptions) (*v1.PolicyList, error) {
+	m, err := newMatcher(opts.FieldSelector, store.PolicyFields)
+	if err != nil {
+		return nil, err
+	}
+
+	selector, err := parseLabelSelector(opts.LabelSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	p.ds.lock.RLock()
+	defer p.ds.lock.RUnlock()
+
+	var matched []*v1.Policy
+
+	for i := len(p.ds.policies) - 1; i >= 0; i-- {
+		item := p.ds.policies[i]
+		if item.DeletedAt.Valid || (username != "" && item.Username != username) ||
+			!m.Matches(policyFields(item)) ||
+			!selector.Matches(labelSet(&item.ObjectMeta)) {
+			continue
+		}
+
+		matched = append(matched, item)
+	}
+
+	ret := &v1.PolicyList{}
+
+	start, end, err := page(
+		matched,
+		func(i int) map[string]interface{} { return policyFields(matched[i]) },
+		opts,
+		store.PolicyFields,
+		store.PolicyColumns,
+		&ret.ListMeta,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	ret.Items = make([]*v1.Policy, 0, end-start)
+
+	for _, item := range matched[start:end] {
+		policy, err := copyPolicy(item)
+		if err != nil {
+			return nil, err
+		}
+
+		ret.Items = append(ret.Items, policy)
+	}
+
+	return ret, nil
+}
+
+// CountByUsers returns the number of policies owned by each of the given users.
+func (p *policies) CountByUsers(ctx context.Context, usernames []string) (map[string]int64, error) {
+	p.ds.lock.RLock()
+	defer p.ds.lock.RUnlock()
+
+	ret := make(map[string]int64, len(usernames))
+
+	for _, item := range p.ds.policies {
+		if !item.DeletedAt.Valid && contains(usernames, item.Username) {
+			ret[item.Username]++
+		}
+	}
+
+	return ret, nil
+}
+
+// policyFields returns the values of the fields in store.PolicyFields.
+func policyFields(policy *v1.Policy) map[string]interface{} {
+	return map[string]interface{}{
+		"id":          policy.ID,
+		"name":        policy.Name,
+		"username":    policy.Username,
+		"effect":      policy.Effect,
+		"description": policy.Description,
+		"created_at":  policy.CreatedAt,
+		"updated_at":  policy.UpdatedAt,
+	}
+}
+
+// copyPolicy returns a copy of the stored record so that callers can not modify the store directly.
+func copyPolicy(record *v1.Policy) (*v1.Policy, error) {
+	policy := *record
+	policy.Extend = nil
+
+	if err := policy.AfterFind(nil); err != nil {
+		return nil, errors.WithCode(code.ErrDatabase, err.Error())
+	}
+
+	return &policy, nil
+}
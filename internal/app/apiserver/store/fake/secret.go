@@ -0,0 +1,248 @@
+package fake
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"gobackend/pkg/errors"
+	metav1 "gobackend/pkg/meta/v1"
+	"gobackend/pkg/util/idtool"
+
+	"gobackend/internal/app/apiserver/store"
+	"gobackend/internal/pkg/code"
+	v1 "gobackend/internal/pkg/entity/apiserver/v1"
+)
+
+type secrets struct {
+	ds *datastore
+}
+
+func newSecrets(ds *datastore) *secrets {
+	return &secrets{ds: ds}
+}
+
+// Create creates a new secret.
+func (s *secrets) Create(ctx context.Context, secret *v1.Secret, opts metav1.CreateOptions) error {
+	s.ds.lock.Lock()
+	defer s.ds.lock.Unlock()
+
+	// secret_id and (username, name) are unique, soft deleted records take part in the check too.
+	for _, item := range s.ds.secrets {
+		if item.SecretID == secret.SecretID {
+			return errors.WithCode(
+				code.ErrSecretAlreadyExist,
+				"Duplicate entry '%s' for key 'secret_id'",
+				secret.SecretID,
+			)
+		}
+
+		if item.Username == secret.Username && item.Name == secret.Name {
+			return errors.WithCode(
+				code.ErrSecretAlreadyExist,
+				"Duplicate entry '%s-%s' for key 'uk_secret_username_name'",
+				secret.Username,
+				secret.Name,
+			)
+		}
+	}
+
+	if err := secret.BeforeCreate(nil); err != nil {
+		return err
+	}
+
+	// A dry run goes through the checks and the hooks, but the record is not stored.
+	if metav1.IsDryRun(opts.DryRun) {
+		secret.ResourceVersion = 0
+
+		return nil
+	}
+
+	now := time.Now()
+	secret.ID = s.ds.nextID(secret.TableName())
+	secret.CreatedAt, secret.UpdatedAt = now, now
+	secret.InstanceID = idtool.GetInstanceID(secret.ID, "secret-")
+
+	if err := secret.BeforeUpdate(nil); err != nil {
+		return err
+	}
+
+	record := *secret
+	record.Extend = nil
+	record.Labels = nil
+	s.ds.secrets = append(s.ds.secrets, &record)
+
+	return nil
+}
+
+// Update updates a secret if its resource version is not changed.
+func (s *secrets) Update(ctx context.Context, secret *v1.Secret, opts metav1.UpdateOptions) error {
+	s.ds.lock.Lock()
+	defer s.ds.lock.Unlock()
+
+	for i, item := range s.ds.secrets {
+		if item.ID != secret.ID || item.DeletedAt.Valid {
+			continue
+		}
+
+		if item.ResourceVersion != secret.ResourceVersion {
+			break
+		}
+
+		if err := secret.BeforeUpdate(nil); err != nil {
+			return err
+		}
+
+		if metav1.IsDryRun(opts.DryRun) {
+			return nil
+		}
+
+		secret.UpdatedAt = time.Now()
+		secret.ResourceVersion++
+		record := *secret
+		record.Extend = nil
+		record.Labels = nil
+		s.ds.secrets[i] = &record
+
+		return nil
+	}
+
+	return conflict(&secret.ObjectMeta)
+}
+
+// Delete deletes the secret by the secret name.
+func (s *secrets) Delete(ctx context.Context, username, name string, opts metav1.DeleteOptions) error {
+	return s.DeleteCollection(ctx, username, []string{name}, opts)
+}
+
+// DeleteCollection batch deletes the secrets.
+func (s *secrets) DeleteCollection(
+	ctx context.Context,
+	username string,
+	names []string,
+	opts metav1.DeleteOptions,
+) error {
+	if metav1.IsDryRun(opts.DryRun) {
+		return nil
+	}
+
+	s.ds.lock.Lock()
+	defer s.ds.lock.Unlock()
+
+	now := time.Now()
+	kept := s.ds.secrets[:0]
+
+	for _, item := range s.ds.secrets {
+		if item.Username != username || !contains(names, item.Name) || (item.DeletedAt.Valid && !opts.Unscoped) {
+			kept = append(kept, item)
+
+			continue
+		}
+
+		if !opts.Unscoped {
+			item.DeletedAt = gorm.DeletedAt{Time: now, Valid: true}
+			kept = append(kept, item)
+		}
+	}
+
+	s.ds.secrets = kept
+
+	return nil
+}
+
+// Get return a secret by the secret name.
+func (s *secrets) Get(ctx context.Context, username, name string, opts metav1.GetOptions) (*v1.Secret, error) {
+	s.ds.lock.RLock()
+	defer s.ds.lock.RUnlock()
+
+	for _, item := range s.ds.secrets {
+		if item.Username == username && item.Name == name && !item.DeletedAt.Valid {
+			return copySecret(item)
+		}
+	}
+
+	return nil, errors.WithCode(code.ErrSecretNotFound, gorm.ErrRecordNotFound.Error())
+}
+
+// List returns the secrets of the user, all the secrets are returned if username is empty.
+func (s *secrets) List(ctx context.Context, username string, opts metav1.ListOptions) (*v1.SecretList, error) {
+	m, err := newMatcher(opts.FieldSelector, store.SecretFields)
+	if err != nil {
+		return nil, err
+	}
+
+	selector, err := parseLabelSelector(opts.LabelSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	s.ds.lock.RLock()
+	defer s.ds.lock.RUnlock()
+
+	var matched []*v1.Secret
+
+	for i := len(s.ds.secrets) - 1; i >= 0; i-- {
+		item := s.ds.secrets[i]
+		if item.DeletedAt.Valid || (username != "" && item.Username != username) ||
+			!m.Matches(secretFields(item)) ||
+			!selector.Matches(labelSet(&item.ObjectMeta)) {
+			continue
+		}
+
+		matched = append(matched, item)
+	}
+
+	ret := &v1.SecretList{}
+
+	start, end, err := page(
+		matched,
+		func(i int) map[string]interface{} { return secretFields(matched[i]) },
+		opts,
+		store.SecretFields,
+		store.SecretColumns,
+		&ret.ListMeta,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	ret.Items = make([]*v1.Secret, 0, end-start)
+
+	for _, item := range matched[start:end] {
+		secret, err := copySecret(item)
+		if err != nil {
+			return nil, err
+		}
+
+		ret.Items = append(ret.Items, secret)
+	}
+
+	return ret, nil
+}
+
+// secretFields returns the values of the fields in store.SecretFields.
+func secretFields(secret *v1.Secret) map[string]interface{} {
+	return map[string]interface{}{
+		"id":          secret.ID,
+		"name":        secret.Name,
+		"username":    secret.Username,
+		"secret_id":   secret.SecretID,
+		"description": secret.Description,
+		"expires":     secret.Expires,
+		"created_at":  secret.CreatedAt,
+		"updated_at":  secret.UpdatedAt,
+	}
+}
+
+// copySecret returns a copy of the stored record so that callers can not modify the store directly.
+func copySecret(record *v1.Secret) (*v1.Secret, error) {
+	secret := *record
+	secret.Extend = nil
+
+	if err := secret.AfterFind(nil); err != nil {
+		return nil, errors.WithCode(code.ErrDatabase, err.Error())
+	}
+
+	return &secret, nil
+}
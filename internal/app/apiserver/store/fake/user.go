@@ -0,0 +1,240 @@
+package fake
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"gobackend/pkg/errors"
+	metav1 "gobackend/pkg/meta/v1"
+	"gobackend/pkg/util/idtool"
+
+	"gobackend/internal/app/apiserver/store"
+	"gobackend/internal/pkg/code"
+	v1 "gobackend/internal/pkg/entity/apiserver/v1"
+)
+
+type users struct {
+	ds *datastore
+}
+
+func newUsers(ds *datastore) *users {
+	return &users{ds: ds}
+}
+
+// Create creates a new user account.
+func (u *users) Create(ctx context.Context, user *v1.User, opts metav1.CreateOptions) error {
+	u.ds.lock.Lock()
+	defer u.ds.lock.Unlock()
+
+	// The name is a unique column, soft deleted records take part in the check too.
+	for _, item := range u.ds.users {
+		if item.Name == user.Name {
+			return errors.WithCode(code.ErrUserAlreadyExist, "Duplicate entry '%s' for key 'uk_user_name'", user.Name)
+		}
+	}
+
+	// Run the same hooks as gorm does when creating a record.
+	if err := user.BeforeCreate(nil); err != nil {
+		return err
+	}
+
+	// A dry run goes through the checks and the hooks, but the record is not stored.
+	if metav1.IsDryRun(opts.DryRun) {
+		user.ResourceVersion = 0
+
+		return nil
+	}
+
+	now := time.Now()
+	user.ID = u.ds.nextID(user.TableName())
+	user.CreatedAt, user.UpdatedAt = now, now
+	user.InstanceID = idtool.GetInstanceID(user.ID, "user-")
+
+	if err := user.BeforeUpdate(nil); err != nil {
+		return err
+	}
+
+	record := *user
+	record.Extend = nil
+	record.Labels = nil
+	u.ds.users = append(u.ds.users, &record)
+
+	return nil
+}
+
+// Update updates an user account information if its resource version is not changed.
+func (u *users) Update(ctx context.Context, user *v1.User, opts metav1.UpdateOptions) error {
+	u.ds.lock.Lock()
+	defer u.ds.lock.Unlock()
+
+	for i, item := range u.ds.users {
+		if item.ID != user.ID || item.DeletedAt.Valid {
+			continue
+		}
+
+		if item.ResourceVersion != user.ResourceVersion {
+			break
+		}
+
+		if err := user.BeforeUpdate(nil); err != nil {
+			return err
+		}
+
+		if metav1.IsDryRun(opts.DryRun) {
+			return nil
+		}
+
+		user.UpdatedAt = time.Now()
+		user.ResourceVersion++
+		record := *user
+		record.Extend = nil
+		record.Labels = nil
+		u.ds.users[i] = &record
+
+		return nil
+	}
+
+	return conflict(&user.ObjectMeta)
+}
+
+// Delete deletes the user by the user identifier.
+func (u *users) Delete(ctx context.Context, username string, opts metav1.DeleteOptions) error {
+	return u.DeleteCollection(ctx, []string{username}, opts)
+}
+
+// DeleteCollection batch deletes the users.
+func (u *users) DeleteCollection(ctx context.Context, usernames []string, opts metav1.DeleteOptions) error {
+	if metav1.IsDryRun(opts.DryRun) {
+		return nil
+	}
+
+	u.ds.lock.Lock()
+	defer u.ds.lock.Unlock()
+
+	now := time.Now()
+	kept := u.ds.users[:0]
+
+	for _, item := range u.ds.users {
+		if !contains(usernames, item.Name) || (item.DeletedAt.Valid && !opts.Unscoped) {
+			kept = append(kept, item)
+
+			continue
+		}
+
+		if !opts.Unscoped {
+			item.DeletedAt = gorm.DeletedAt{Time: now, Valid: true}
+			kept = append(kept, item)
+		}
+	}
+
+	u.ds.users = kept
+
+	return nil
+}
+
+// Get return an user by the user identifier.
+func (u *users) Get(ctx context.Context, username string, opts metav1.GetOptions) (*v1.User, error) {
+	u.ds.lock.RLock()
+	defer u.ds.lock.RUnlock()
+
+	item := u.find(username)
+	if item == nil {
+		return nil, errors.WithCode(code.ErrUserNotFound, gorm.ErrRecordNotFound.Error())
+	}
+
+	return copyUser(item)
+}
+
+// List users.
+func (u *users) List(ctx context.Context, opts metav1.ListOptions) (*v1.UserList, error) {
+	m, err := newMatcher(opts.FieldSelector, store.UserFields)
+	if err != nil {
+		return nil, err
+	}
+
+	selector, err := parseLabelSelector(opts.LabelSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	u.ds.lock.RLock()
+	defer u.ds.lock.RUnlock()
+
+	var matched []*v1.User
+
+	for i := len(u.ds.users) - 1; i >= 0; i-- {
+		item := u.ds.users[i]
+		if item.DeletedAt.Valid || !m.Matches(userFields(item)) ||
+			!selector.Matches(labelSet(&item.ObjectMeta)) {
+			continue
+		}
+
+		matched = append(matched, item)
+	}
+
+	ret := &v1.UserList{}
+
+	start, end, err := page(
+		matched,
+		func(i int) map[string]interface{} { return userFields(matched[i]) },
+		opts,
+		store.UserFields,
+		store.UserColumns,
+		&ret.ListMeta,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	ret.Items = make([]*v1.User, 0, end-start)
+
+	for _, item := range matched[start:end] {
+		user, err := copyUser(item)
+		if err != nil {
+			return nil, err
+		}
+
+		ret.Items = append(ret.Items, user)
+	}
+
+	return ret, nil
+}
+
+// userFields returns the values of the fields in store.UserFields.
+func userFields(user *v1.User) map[string]interface{} {
+	return map[string]interface{}{
+		"id":         user.ID,
+		"name":       user.Name,
+		"nickname":   user.Nickname,
+		"email":      user.Email,
+		"phone":      user.Phone,
+		"is_admin":   user.IsAdmin,
+		"created_at": user.CreatedAt,
+		"updated_at": user.UpdatedAt,
+	}
+}
+
+// find returns the live user with the given name, the caller must hold the lock.
+func (u *users) find(username string) *v1.User {
+	for _, item := range u.ds.users {
+		if item.Name == username && !item.DeletedAt.Valid {
+			return item
+		}
+	}
+
+	return nil
+}
+
+// copyUser returns a copy of the stored record so that callers can not modify the store directly.
+func copyUser(record *v1.User) (*v1.User, error) {
+	user := *record
+	user.Extend = nil
+
+	if err := user.AfterFind(nil); err != nil {
+		return nil, errors.WithCode(code.ErrDatabase, err.Error())
+	}
+
+	return &user, nil
+}
@@ -0,0 +1,412 @@
+package fake
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/AlekSi/pointer"
+
+	"gobackend/pkg/errors"
+	metav1 "gobackend/pkg/meta/v1"
+
+	"gobackend/internal/app/apiserver/store"
+	"gobackend/internal/pkg/code"
+	v1 "gobackend/internal/pkg/entity/apiserver/v1"
+)
+
+func newUser(name string) *v1.User {
+	user := &v1.User{
+		Nickname: name,
+		Password: "Passw0rd!",
+		Email:    name + "@example.com",
+	}
+	user.Name = name
+
+	return user
+}
+
+func TestUsersCreate(t *testing.T) {
+	ctx := context.Background()
+	s := New().Users()
+
+	if err := s.Create(ctx, newUser("alice"), metav1.CreateOptions{}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	err := s.Create(ctx, newUser("alice"), metav1.CreateOptions{})
+	if !errors.IsCode(err, code.ErrUserAlreadyExist) {
+		t.Fatalf("Create() duplicate error = %v, want ErrUserAlreadyExist", err)
+	}
+
+	user, err := s.Get(ctx, "alice", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	if user.InstanceID == "" {
+		t.Errorf("Get() instance id is empty")
+	}
+
+	if err := user.Compare("Passw0rd!"); err != nil {
+		t.Errorf("Get() password is not encrypted: %v", err)
+	}
+}
+
+func TestUsersDelete(t *testing.T) {
+	ctx := context.Background()
+	ds := New().(*datastore)
+	s := ds.Users()
+
+	for _, name := range []string{"alice", "bob"} {
+		if err := s.Create(ctx, newUser(name), metav1.CreateOptions{}); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	if err := s.Delete(ctx, "alice", metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	if _, err := s.Get(ctx, "alice", metav1.GetOptions{}); !errors.IsCode(err, code.ErrUserNotFound) {
+		t.Errorf("Get() soft deleted user error = %v, want ErrUserNotFound", err)
+	}
+
+	if len(ds.users) != 2 {
+		t.Errorf("soft delete removed the record, got %d records", len(ds.users))
+	}
+
+	// The name of a soft deleted user can not be used again.
+	if err := s.Create(ctx, newUser("alice"), metav1.CreateOptions{}); !errors.IsCode(err, code.ErrUserAlreadyExist) {
+		t.Fatalf("Create() error = %v, want ErrUserAlreadyExist", err)
+	}
+
+	if err := s.DeleteCollection(ctx, []string{"alice", "bob"}, metav1.DeleteOptions{Unscoped: true}); err != nil {
+		t.Fatalf("DeleteCollection() error = %v", err)
+	}
+
+	if len(ds.users) != 0 {
+		t.Errorf("unscoped delete kept %d records", len(ds.users))
+	}
+}
+
+func TestUsersList(t *testing.T) {
+	ctx := context.Background()
+	s := New().Users()
+
+	labels := map[string]map[string]string{
+		"alice": {"env": "prod", "tier": "web"},
+		"bob":   {"env": "prod", "tier": "api", "deprecated": "true"},
+		"carol": {"env": "dev", "tier": "web"},
+	}
+
+	for _, name := range []string{"alice", "bob", "carol", "dave"} {
+		user := newUser(name)
+		user.Labels = labels[name]
+		if name == "bob" {
+			user.IsAdmin = 1
+		}
+
+		if err := s.Create(ctx, user, metav1.CreateOptions{}); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		name  string
+		opts  metav1.ListOptions
+		total int64
+		want  []string
+	}{
+		{
+			name:  "all",
+			total: 4,
+			want:  []string{"dave", "carol", "bob", "alice"},
+		},
+		{
+			name:  "offset and limit",
+			opts:  metav1.ListOptions{Offset: pointer.ToInt64(1), Limit: pointer.ToInt64(2)},
+			total: 4,
+			want:  []string{"carol", "bob"},
+		},
+		{
+			name:  "offset out of range",
+			opts:  metav1.ListOptions{Offset: pointer.ToInt64(10)},
+			total: 4,
+			want:  []string{},
+		},
+		{
+			name:  "exact match",
+			opts:  metav1.ListOptions{FieldSelector: "name==bob"},
+			total: 1,
+			want:  []string{"bob"},
+		},
+		{
+			name:  "fuzzy match",
+			opts:  metav1.ListOptions{FieldSelector: "name=a"},
+			total: 3,
+			want:  []string{"dave", "carol", "alice"},
+		},
+		{
+			name:  "not equal",
+			opts:  metav1.ListOptions{FieldSelector: "name!=alice"},
+			total: 3,
+			want:  []string{"dave", "carol", "bob"},
+		},
+		{
+			name:  "set membership",
+			opts:  metav1.ListOptions{FieldSelector: "name in (alice,carol,erin)"},
+			total: 2,
+			want:  []string{"carol", "alice"},
+		},
+		{
+			name:  "number comparison",
+			opts:  metav1.ListOptions{FieldSelector: "is_admin>=1"},
+			total: 1,
+			want:  []string{"bob"},
+		},
+		{
+			name:  "time range",
+			opts:  metav1.ListOptions{FieldSelector: "created_at>=2000-01-01,created_at<2000-01-02"},
+			total: 0,
+			want:  []string{},
+		},
+		{
+			name:  "sort by",
+			opts:  metav1.ListOptions{SortBy: "-is_admin,name"},
+			total: 4,
+			want:  []string{"bob", "alice", "carol", "dave"},
+		},
+		{
+			name:  "sort by and limit",
+			opts:  metav1.ListOptions{SortBy: "email", Limit: pointer.ToInt64(2), Fields: "id,name"},
+			total: 4,
+			want:  []string{"alice", "bob"},
+		},
+		{
+			name:  "label selector",
+			opts:  metav1.ListOptions{LabelSelector: "env=prod,tier in (web,api),!deprecated"},
+			total: 1,
+			want:  []string{"alice"},
+		},
+		{
+			name:  "label not in",
+			opts:  metav1.ListOptions{LabelSelector: "env notin (prod)"},
+			total: 2,
+			want:  []string{"dave", "carol"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list, err := s.List(ctx, tt.opts)
+			if err != nil {
+				t.Fatalf("List() error = %v", err)
+			}
+
+			if list.TotalCount != tt.total {
+				t.Errorf("List() total = %d, want %d", list.TotalCount, tt.total)
+			}
+
+			got := make([]string, 0, len(list.Items))
+			for _, user := range list.Items {
+				got = append(got, user.Name)
+			}
+
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("List() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUsersListContinue(t *testing.T) {
+	ctx := context.Background()
+	s := New().Users()
+
+	for _, name := range []string{"alice", "bob", "carol", "dave", "erin"} {
+		if err := s.Create(ctx, newUser(name), metav1.CreateOptions{}); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	opts := metav1.ListOptions{Limit: pointer.ToInt64(2), TotalCount: pointer.ToBool(false)}
+
+	var pages []string
+
+	for i := 0; ; i++ {
+		list, err := s.List(ctx, opts)
+		if err != nil {
+			t.Fatalf("List() error = %v", err)
+		}
+
+		if list.TotalCount != 0 {
+			t.Errorf("List() total = %d, want 0", list.TotalCount)
+		}
+
+		names := make([]string, 0, len(list.Items))
+		for _, user := range list.Items {
+			names = append(names, user.Name)
+		}
+
+		pages = append(pages, fmt.Sprint(names))
+
+		if list.Continue == "" || i > 5 {
+			break
+		}
+
+		// The records created or deleted before the position of the token do not shift the pages.
+		if i == 0 {
+			if err := s.Delete(ctx, "erin", metav1.DeleteOptions{}); err != nil {
+				t.Fatalf("Delete() error = %v", err)
+			}
+		}
+
+		opts.Continue = list.Continue
+	}
+
+	if want := "[[erin dave] [carol bob] [alice]]"; fmt.Sprint(pages) != want {
+		t.Errorf("List() pages = %v, want %v", pages, want)
+	}
+
+	// The token keeps the position in the sort order.
+	opts = metav1.ListOptions{SortBy: "-nickname", Limit: pointer.ToInt64(2)}
+
+	list, err := s.List(ctx, opts)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+
+	opts.Continue = list.Continue
+
+	list, err = s.List(ctx, opts)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+
+	if got := fmt.Sprint([]string{list.Items[0].Name, list.Items[1].Name}); got != "[bob alice]" {
+		t.Errorf("List() = %v, want [bob alice]", got)
+	}
+
+	opts.SortBy = "nickname,email"
+	if _, err := s.List(ctx, opts); !errors.IsCode(err, code.ErrContinueValidation) {
+		t.Errorf("List() error = %v, want code %d", err, code.ErrContinueValidation)
+	}
+
+	opts = metav1.ListOptions{Continue: "bogus"}
+	if _, err := s.List(ctx, opts); !errors.IsCode(err, code.ErrContinueValidation) {
+		t.Errorf("List() error = %v, want code %d", err, code.ErrContinueValidation)
+	}
+
+	list, _ = s.List(ctx, metav1.ListOptions{Limit: pointer.ToInt64(1)})
+	opts = metav1.ListOptions{Continue: list.Continue, Offset: pointer.ToInt64(1)}
+
+	if _, err := s.List(ctx, opts); !errors.IsCode(err, code.ErrContinueValidation) {
+		t.Errorf("List() error = %v, want code %d", err, code.ErrContinueValidation)
+	}
+}
+
+func TestUsersListInvalidSelector(t *testing.T) {
+	s := New().Users()
+
+	tests := []struct {
+		name string
+		opts metav1.ListOptions
+		code int
+	}{
+		{"unknown selector field", metav1.ListOptions{FieldSelector: "password==x"}, code.ErrFieldSelectorValidation},
+		{"string comparison", metav1.ListOptions{FieldSelector: "name>a"}, code.ErrFieldSelectorValidation},
+		{"invalid number", metav1.ListOptions{FieldSelector: "is_admin==yes"}, code.ErrFieldSelectorValidation},
+		{"invalid time", metav1.ListOptions{FieldSelector: "created_at>yesterday"}, code.ErrFieldSelectorValidation},
+		{"unknown sort field", metav1.ListOptions{SortBy: "password"}, code.ErrValidation},
+		{"duplicate sort field", metav1.ListOptions{SortBy: "name,-name"}, code.ErrValidation},
+		{"unknown field", metav1.ListOptions{Fields: "name,password"}, code.ErrValidation},
+		{"invalid label selector", metav1.ListOptions{LabelSelector: "env in (prod"}, code.ErrLabelSelectorValidation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := s.List(context.Background(), tt.opts); !errors.IsCode(err, tt.code) {
+				t.Errorf("List() error = %v, want code %d", err, tt.code)
+			}
+		})
+	}
+}
+
+func TestTxRollback(t *testing.T) {
+	ctx := context.Background()
+	ds := New()
+
+	if err := ds.Users().Create(ctx, newUser("alice"), metav1.CreateOptions{}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	want := fmt.Errorf("rollback")
+	err := ds.Tx(ctx, func(tx store.Factory) error {
+		if err := tx.Users().Delete(ctx, "alice", metav1.DeleteOptions{}); err != nil {
+			return err
+		}
+
+		if err := tx.Users().Create(ctx, newUser("bob"), metav1.CreateOptions{}); err != nil {
+			return err
+		}
+
+		return want
+	})
+	if err != want {
+		t.Fatalf("Tx() error = %v, want %v", err, want)
+	}
+
+	if _, err := ds.Users().Get(ctx, "alice", metav1.GetOptions{}); err != nil {
+		t.Errorf("Get(alice) error = %v, want restored user", err)
+	}
+
+	if _, err := ds.Users().Get(ctx, "bob", metav1.GetOptions{}); !errors.IsCode(err, code.ErrUserNotFound) {
+		t.Errorf("Get(bob) error = %v, want ErrUserNotFound", err)
+	}
+
+	if err := ds.Tx(ctx, func(tx store.Factory) error {
+		return tx.Users().Create(ctx, newUser("bob"), metav1.CreateOptions{})
+	}); err != nil {
+		t.Fatalf("Tx() error = %v", err)
+	}
+
+	if _, err := ds.Users().Get(ctx, "bob", metav1.GetOptions{}); err != nil {
+		t.Errorf("Get(bob) error = %v, want committed user", err)
+	}
+}
+
+func TestUsersUpdateConflict(t *testing.T) {
+	ctx := context.Background()
+	s := New().Users()
+
+	if err := s.Create(ctx, newUser("alice"), metav1.CreateOptions{}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	first, _ := s.Get(ctx, "alice", metav1.GetOptions{})
+	second, _ := s.Get(ctx, "alice", metav1.GetOptions{})
+
+	first.Nickname = "first"
+	if err := s.Update(ctx, first, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	if first.ResourceVersion != 2 {
+		t.Errorf("ResourceVersion = %d, want 2", first.ResourceVersion)
+	}
+
+	second.Nickname = "second"
+	if err := s.Update(ctx, second, metav1.UpdateOptions{}); !errors.IsCode(err, code.ErrResourceConflict) {
+		t.Fatalf("Update() stale error = %v, want ErrResourceConflict", err)
+	}
+
+	got, _ := s.Get(ctx, "alice", metav1.GetOptions{})
+	if got.Nickname != "first" {
+		t.Errorf("Nickname = %s, want first", got.Nickname)
+	}
+
+	if err := got.Compare("Passw0rd!"); err != nil {
+		t.Errorf("Compare() after update error = %v", err)
+	}
+}
@@ -0,0 +1,367 @@
+package store
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"gobackend/pkg/errors"
+	"gobackend/pkg/fields"
+	"gobackend/pkg/selection"
+
+	"gobackend/internal/pkg/code"
+)
+
+// FieldType is the type of a field which can be used in field selectors,
+// the values in field selectors are converted to it.
+type FieldType int
+
+const (
+	// StringField values are strings, `=` means fuzzy match and `==` means exact match.
+	// They can not be compared with `>`, `>=`, `<` and `<=`.
+	StringField FieldType = iota
+
+	// NumberField values are numbers, they are converted to float64.
+	NumberField
+
+	// TimeField values are RFC 3339 times, e.g. `2006-01-02T15:04:05Z`, or dates, e.g. `2006-01-02`,
+	// they are converted to time.Time.
+	TimeField
+)
+
+// Fields is the whitelist of the fields of a resource which can be used in field selectors
+// and to sort lists, it maps the field names, which are also the column names, to their types.
+type Fields map[string]FieldType
+
+// The whitelists of the resources.
+var (
+	UserFields = Fields{
+		"id":         NumberField,
+		"name":       StringField,
+		"nickname":   StringField,
+		"email":      StringField,
+		"phone":      StringField,
+		"is_admin":   NumberField,
+		"created_at": TimeField,
+		"updated_at": TimeField,
+	}
+
+	SecretFields = Fields{
+		"id":          NumberField,
+		"name":        StringField,
+		"username":    StringField,
+		"secret_id":   StringField,
+		"description": StringField,
+		"expires":     NumberField,
+		"created_at":  TimeField,
+		"updated_at":  TimeField,
+	}
+
+	PolicyFields = Fields{
+		"id":          NumberField,
+		"name":        StringField,
+		"username":    StringField,
+		"effect":      StringField,
+		"description": StringField,
+		"created_at":  TimeField,
+		"updated_at":  TimeField,
+	}
+
+	OperationLogFields = Fields{
+		"id":          NumberField,
+		"username":    StringField,
+		"client_ip":   StringField,
+		"req_method":  StringField,
+		"req_path":    StringField,
+		"http_status": NumberField,
+		"req_time":    TimeField,
+		"req_latency": NumberField,
+	}
+)
+
+// Columns is the whitelist of the fields of a resource which can be requested with the `fields`
+// option of list calls, it maps the field names to the column names.
+type Columns map[string]string
+
+// The columns of the metadata, see metav1.ObjectMeta.
+var metaColumns = Columns{
+	"id":               "id",
+	"instance_id":      "instance_id",
+	"resource_version": "resource_version",
+	"name":             "name",
+	"labels":           "labels_shadow",
+	"extend":           "extend_shadow",
+	"created_at":       "created_at",
+	"updated_at":       "updated_at",
+}
+
+// The field whitelists of the resources.
+var (
+	UserColumns = metaColumns.with(Columns{
+		"nickname": "nickname",
+		"email":    "email",
+		"phone":    "phone",
+		"is_admin": "is_admin",
+	})
+
+	SecretColumns = metaColumns.with(Columns{
+		"username":    "username",
+		"secret_id":   "secret_id",
+		"secret_key":  "secret_key",
+		"expires":     "expires",
+		"description": "description",
+	})
+
+	PolicyColumns = metaColumns.with(Columns{
+		"username":    "username",
+		"description": "description",
+		"effect":      "effect",
+		"subjects":    "policy_shadow",
+		"actions":     "policy_shadow",
+		"resources":   "policy_shadow",
+		"conditions":  "policy_shadow",
+	})
+
+	OperationLogColumns = Columns{
+		"id":          "id",
+		"created_at":  "created_at",
+		"updated_at":  "updated_at",
+		"username":    "username",
+		"user_agent":  "user_agent",
+		"client_ip":   "client_ip",
+		"req_method":  "req_method",
+		"req_path":    "req_path",
+		"req_body":    "req_body",
+		"req_referer": "req_referer",
+		"req_time":    "req_time",
+		"req_latency": "req_latency",
+		"http_status": "http_status",
+		"res_data":    "res_data",
+	}
+)
+
+func (c Columns) with(columns Columns) Columns {
+	ret := make(Columns, len(c)+len(columns))
+	for field, column := range c {
+		ret[field] = column
+	}
+
+	for field, column := range columns {
+		ret[field] = column
+	}
+
+	return ret
+}
+
+// Select returns the columns of the comma separated fields, e.g. `id,name,email`, and the
+// required columns, id is always required. Nil is returned if fields is empty, which means
+// all the columns. An error with code ErrValidation is returned if a field is not in the whitelist.
+func (c Columns) Select(fields string, required ...string) ([]string, error) {
+	if fields == "" {
+		return nil, nil
+	}
+
+	columns := []string{"id"}
+	for _, column := range required {
+		if !containsString(columns, column) {
+			columns = append(columns, column)
+		}
+	}
+
+	for _, field := range strings.Split(fields, ",") {
+		column, ok := c[strings.TrimSpace(field)]
+		if !ok {
+			return nil, errors.WithCode(code.ErrValidation, "unsupported field '%s' in fields", field)
+		}
+
+		if !containsString(columns, column) {
+			columns = append(columns, column)
+		}
+	}
+
+	return columns, nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Requirement is a requirement of a field selector which has been validated against a whitelist.
+type Requirement struct {
+	Field    string
+	Type     FieldType
+	Operator selection.Operator
+
+	// Values are converted to the field type: string, float64 or time.Time.
+	// There is exactly one value except for the In and NotIn operators.
+	Values []interface{}
+}
+
+// Requirements parses the field selector and validates the requirements against the whitelist.
+// An error with code ErrFieldSelectorValidation is returned if the selector can not be parsed,
+// or it has a field not in the whitelist, an operator not supported by the field type,
+// or a value which can not be converted to the field type.
+func (f Fields) Requirements(fieldSelector string) ([]Requirement, error) {
+	selector, err := fields.ParseSelector(fieldSelector)
+	if err != nil {
+		return nil, errors.WithCode(code.ErrFieldSelectorValidation, err.Error())
+	}
+
+	requirements := make([]Requirement, 0, len(selector.Requirements()))
+
+	for _, require := range selector.Requirements() {
+		typ, ok := f[require.Field]
+		if !ok {
+			return nil, errors.WithCode(code.ErrFieldSelectorValidation, "unsupported field '%s'", require.Field)
+		}
+
+		switch require.Operator {
+		case selection.GreaterThan, selection.GreaterThanOrEquals, selection.LessThan, selection.LessThanOrEquals:
+			if typ == StringField {
+				return nil, errors.WithCode(
+					code.ErrFieldSelectorValidation,
+					"field '%s' can not be compared with operator '%s'",
+					require.Field,
+					require.Operator,
+				)
+			}
+		}
+
+		values := require.Values
+		if require.Operator != selection.In && require.Operator != selection.NotIn {
+			values = []string{require.Value}
+		}
+
+		r := Requirement{Field: require.Field, Type: typ, Operator: require.Operator}
+
+		for _, v := range values {
+			value, err := typ.convert(v)
+			if err != nil {
+				return nil, errors.WithCode(
+					code.ErrFieldSelectorValidation,
+					"invalid value '%s' of field '%s': %s",
+					v,
+					require.Field,
+					err.Error(),
+				)
+			}
+
+			r.Values = append(r.Values, value)
+		}
+
+		requirements = append(requirements, r)
+	}
+
+	return requirements, nil
+}
+
+// convert converts a value in field selectors to the field type.
+func (t FieldType) convert(value string) (interface{}, error) {
+	switch t {
+	case NumberField:
+		return strconv.ParseFloat(value, 64)
+	case TimeField:
+		if tm, err := time.Parse("2006-01-02", value); err == nil {
+			return tm, nil
+		}
+
+		return time.Parse(time.RFC3339Nano, value)
+	default:
+		return value, nil
+	}
+}
+
+// Matches returns true if the value of the field satisfies the requirement,
+// it is used by the stores which select records in memory.
+// The value must be a string for StringField, an integer or a float for NumberField,
+// and a time.Time for TimeField.
+func (r Requirement) Matches(value interface{}) bool {
+	switch r.Operator {
+	case selection.Equals:
+		if s, ok := value.(string); ok {
+			return strings.Contains(s, r.Values[0].(string))
+		}
+
+		return r.compare(value, r.Values[0]) == 0
+	case selection.DoubleEquals:
+		return r.compare(value, r.Values[0]) == 0
+	case selection.NotEquals:
+		return r.compare(value, r.Values[0]) != 0
+	case selection.In, selection.NotIn:
+		for _, v := range r.Values {
+			if r.compare(value, v) == 0 {
+				return r.Operator == selection.In
+			}
+		}
+
+		return r.Operator == selection.NotIn
+	case selection.GreaterThan:
+		return r.compare(value, r.Values[0]) > 0
+	case selection.GreaterThanOrEquals:
+		return r.compare(value, r.Values[0]) >= 0
+	case selection.LessThan:
+		return r.compare(value, r.Values[0]) < 0
+	case selection.LessThanOrEquals:
+		return r.compare(value, r.Values[0]) <= 0
+	default:
+		return false
+	}
+}
+
+// compare returns an integer comparing the value of a record with a value of the requirement.
+func (r Requirement) compare(value, v interface{}) int {
+	return r.Type.compare(value, v)
+}
+
+// compare returns an integer comparing two values of the field type.
+func (t FieldType) compare(value, v interface{}) int {
+	switch t {
+	case NumberField:
+		x, y := toFloat(value), toFloat(v)
+
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		default:
+			return 0
+		}
+	case TimeField:
+		x, y := value.(time.Time), v.(time.Time)
+
+		switch {
+		case x.Before(y):
+			return -1
+		case x.After(y):
+			return 1
+		default:
+			return 0
+		}
+	default:
+		return strings.Compare(value.(string), v.(string))
+	}
+}
+
+func toFloat(value interface{}) float64 {
+	switch v := value.(type) {
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case uint:
+		return float64(v)
+	case uint64:
+		return float64(v)
+	case float64:
+		return v
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,186 @@
+package store
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+
+	"gobackend/pkg/errors"
+	"gobackend/pkg/json"
+	metav1 "gobackend/pkg/meta/v1"
+
+	"gobackend/internal/pkg/code"
+)
+
+// SortField is a field of the sort order of a list.
+type SortField struct {
+	Field string
+	Type  FieldType
+	Desc  bool
+}
+
+// Sort is the sort order of a list. The last field is always id, so that the order is total
+// and a continue token points to an exact position.
+type Sort []SortField
+
+// defaultSort sorts the objects by id in descending order, i.e. the newest first.
+var defaultSort = Sort{{Field: "id", Type: NumberField, Desc: true}}
+
+// Sort parses the comma separated fields to sort by, e.g. `created_at,-name`,
+// `-` means descending order. The objects are sorted by id in descending order by default,
+// and by id in the same direction as the last field to break the ties.
+// An error with code ErrValidation is returned if a field is not in the whitelist.
+func (f Fields) Sort(sortBy string) (Sort, error) {
+	if sortBy == "" {
+		return defaultSort, nil
+	}
+
+	var sort Sort
+
+	for _, field := range strings.Split(sortBy, ",") {
+		field = strings.TrimSpace(field)
+
+		desc := strings.HasPrefix(field, "-")
+		field = strings.TrimPrefix(field, "-")
+
+		typ, ok := f[field]
+		if !ok {
+			return nil, errors.WithCode(code.ErrValidation, "unsupported field '%s' in sort_by", field)
+		}
+
+		for _, sf := range sort {
+			if sf.Field == field {
+				return nil, errors.WithCode(code.ErrValidation, "duplicate field '%s' in sort_by", field)
+			}
+		}
+
+		sort = append(sort, SortField{Field: field, Type: typ, Desc: desc})
+
+		// Id is unique, the fields after it make no difference.
+		if field == "id" {
+			return sort, nil
+		}
+	}
+
+	return append(sort, SortField{Field: "id", Type: NumberField, Desc: sort[len(sort)-1].Desc}), nil
+}
+
+// Fields returns the names of the sort fields.
+func (s Sort) Fields() []string {
+	fields := make([]string, 0, len(s))
+	for _, sf := range s {
+		fields = append(fields, sf.Field)
+	}
+
+	return fields
+}
+
+// Values returns the values of the sort fields in the field values of an object.
+func (s Sort) Values(values map[string]interface{}) []interface{} {
+	ret := make([]interface{}, 0, len(s))
+	for _, sf := range s {
+		ret = append(ret, values[sf.Field])
+	}
+
+	return ret
+}
+
+// Compare returns an integer comparing the positions of two objects in the sort order,
+// x and y are the values of the sort fields of the objects.
+func (s Sort) Compare(x, y []interface{}) int {
+	for i, sf := range s {
+		c := sf.Type.compare(x[i], y[i])
+		if sf.Desc {
+			c = -c
+		}
+
+		if c != 0 {
+			return c
+		}
+	}
+
+	return 0
+}
+
+// continueToken is the position of the last returned object of a list, the next page starts
+// after it. It is sent to clients as an opaque string, see metav1.ListMeta.Continue.
+type continueToken struct {
+	// ID is the id of the last returned object, it breaks the ties of the sort keys.
+	ID uint64 `json:"id"`
+
+	// SortKeys are the values of the sort fields, except id, of the last returned object.
+	// They are empty if the objects are sorted by id.
+	SortKeys []string `json:"sort_keys,omitempty"`
+}
+
+// Continue returns the continue token of the object with the values of the sort fields.
+func (s Sort) Continue(values []interface{}) string {
+	id, _ := values[len(values)-1].(uint64)
+	token := continueToken{ID: id}
+
+	for i := range s[:len(s)-1] {
+		token.SortKeys = append(token.SortKeys, formatValue(values[i]))
+	}
+
+	data, _ := json.Marshal(token)
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// ParseContinue returns the values of the sort fields in the continue token of the list options,
+// or nil if there is none. An error with code ErrContinueValidation is returned if the token
+// is malformed, it does not match the sort order, or it is used with an offset.
+func (s Sort) ParseContinue(opts metav1.ListOptions) ([]interface{}, error) {
+	if opts.Continue == "" {
+		return nil, nil
+	}
+
+	if opts.Offset != nil && *opts.Offset != 0 {
+		return nil, errors.WithCode(code.ErrContinueValidation, "continue can not be used with offset")
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(opts.Continue)
+	if err != nil {
+		return nil, errors.WithCode(code.ErrContinueValidation, "malformed continue token")
+	}
+
+	var token continueToken
+	if err := json.Unmarshal(data, &token); err != nil || token.ID == 0 {
+		return nil, errors.WithCode(code.ErrContinueValidation, "malformed continue token")
+	}
+
+	if len(token.SortKeys) != len(s)-1 {
+		return nil, errors.WithCode(code.ErrContinueValidation, "continue token does not match sort_by")
+	}
+
+	values := make([]interface{}, 0, len(s))
+
+	for i, key := range token.SortKeys {
+		value, err := s[i].Type.convert(key)
+		if err != nil {
+			return nil, errors.WithCode(code.ErrContinueValidation, "continue token does not match sort_by")
+		}
+
+		values = append(values, value)
+	}
+
+	return append(values, token.ID), nil
+}
+
+// formatValue formats a field value so that FieldType.convert converts it back.
+func formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	default:
+		return strconv.FormatFloat(toFloat(v), 'f', -1, 64)
+	}
+}
+
+// CountTotal returns true if the total count should be populated for the list call.
+func CountTotal(opts metav1.ListOptions) bool {
+	return opts.TotalCount == nil || *opts.TotalCount
+}
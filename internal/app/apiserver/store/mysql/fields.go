@@ -0,0 +1,68 @@
+package mysql
+
+import (
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"gobackend/pkg/selection"
+
+	"gobackend/internal/app/apiserver/store"
+)
+
+// likeEscaper escapes the wildcards of LIKE patterns with `!`, which is the escape character
+// of all the supported databases, unlike `\` which is special in MySQL string literals.
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
+// selectFields restricts the query to the records matching the field selector, e.g.
+// `name=foo,http_status in (200,201),req_time>=2021-01-01T00:00:00Z`.
+// The fields must be in the whitelist, they are the column names and the values are
+// passed to the database as parameters.
+func selectFields(db *gorm.DB, whitelist store.Fields, fieldSelector string) (*gorm.DB, error) {
+	requirements, err := whitelist.Requirements(fieldSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, require := range requirements {
+		column := clause.Column{Name: require.Field}
+
+		var expr clause.Expression
+
+		switch require.Operator {
+		case selection.Equals:
+			// `=` means fuzzy match for strings.
+			if require.Type == store.StringField {
+				pattern := "%" + likeEscaper.Replace(require.Values[0].(string)) + "%"
+				expr = clause.Expr{SQL: "? LIKE ? ESCAPE '!'", Vars: []interface{}{column, pattern}}
+
+				break
+			}
+
+			expr = clause.Eq{Column: column, Value: require.Values[0]}
+		case selection.DoubleEquals:
+			expr = clause.Eq{Column: column, Value: require.Values[0]}
+		case selection.NotEquals:
+			expr = clause.Neq{Column: column, Value: require.Values[0]}
+		case selection.In:
+			expr = clause.IN{Column: column, Values: require.Values}
+		case selection.NotIn:
+			expr = clause.Not(clause.IN{Column: column, Values: require.Values})
+		case selection.GreaterThan:
+			expr = clause.Gt{Column: column, Value: require.Values[0]}
+		case selection.GreaterThanOrEquals:
+			expr = clause.Gte{Column: column, Value: require.Values[0]}
+		case selection.LessThan:
+			expr = clause.Lt{Column: column, Value: require.Values[0]}
+		case selection.LessThanOrEquals:
+			expr = clause.Lte{Column: column, Value: require.Values[0]}
+		default:
+			continue
+		}
+
+		db = db.Where(expr)
+	}
+
+	return db, nil
+}
@@ -0,0 +1,120 @@
+package mysql
+
+import (
+	"strconv"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+
+	"gobackend/pkg/errors"
+	"gobackend/pkg/labels"
+	metav1 "gobackend/pkg/meta/v1"
+	"gobackend/pkg/selection"
+
+	"gobackend/internal/pkg/code"
+)
+
+// label indexes a label of a resource, so that the resources can be selected by label
+// selectors in the database. The labels of a resource are read from its labels_shadow column,
+// the label table is only used to select resources.
+type label struct {
+	ID         uint64 `gorm:"primary_key;AUTO_INCREMENT;column:id"`
+	Resource   string `gorm:"uniqueIndex:uk_label_resource_key,priority:1;index:idx_label_key_value,priority:1;index:idx_label_key_number,priority:1;column:resource;type:varchar(32);not null"`
+	ResourceID uint64 `gorm:"uniqueIndex:uk_label_resource_key,priority:2;column:resource_id;not null"`
+	Key        string `gorm:"uniqueIndex:uk_label_resource_key,priority:3;index:idx_label_key_value,priority:2;index:idx_label_key_number,priority:2;column:label_key;type:varchar(317);not null"`
+	Value      string `gorm:"index:idx_label_key_value,priority:3;column:label_value;type:varchar(63);not null"`
+
+	// Number is the value of an integer label, it is used by the `gt` and `lt` operators.
+	Number *int64 `gorm:"index:idx_label_key_number,priority:3;column:label_number"`
+}
+
+// TableName maps to mysql table name.
+func (l *label) TableName() string {
+	return "label"
+}
+
+// create creates the record of model and indexes its labels in a transaction.
+func create(db *gorm.DB, model schema.Tabler, meta *metav1.ObjectMeta) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(model).Error; err != nil {
+			return err
+		}
+
+		return saveLabels(tx, model.TableName(), meta)
+	})
+}
+
+// remove deletes the records of model matching the conditions in a transaction,
+// the indexed labels are deleted too if the records are deleted permanently.
+func remove(db *gorm.DB, model schema.Tabler, unscoped bool, query string, args ...interface{}) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if unscoped {
+			ids := tx.Model(model).Select("id").Where(query, args...)
+
+			err := tx.Where("resource = ? and resource_id in (?)", model.TableName(), ids).Delete(&label{}).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		return tx.Where(query, args...).Delete(model).Error
+	})
+}
+
+// saveLabels replaces the indexed labels of the resource with the labels in meta.
+func saveLabels(tx *gorm.DB, resource string, meta *metav1.ObjectMeta) error {
+	err := tx.Where("resource = ? and resource_id = ?", resource, meta.ID).Delete(&label{}).Error
+	if err != nil || len(meta.Labels) == 0 {
+		return err
+	}
+
+	records := make([]*label, 0, len(meta.Labels))
+
+	for key, value := range meta.Labels {
+		record := &label{Resource: resource, ResourceID: meta.ID, Key: key, Value: value}
+		if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+			record.Number = &n
+		}
+
+		records = append(records, record)
+	}
+
+	return tx.Create(&records).Error
+}
+
+// selectLabels restricts the query of the resources to the ones matching the label selector,
+// e.g. `env=prod,tier in (web,api),!deprecated`.
+func selectLabels(db *gorm.DB, resource, labelSelector string) (*gorm.DB, error) {
+	selector, err := labels.Parse(labelSelector)
+	if err != nil {
+		return nil, errors.WithCode(code.ErrLabelSelectorValidation, err.Error())
+	}
+
+	for _, require := range selector.Requirements() {
+		ids := db.Session(&gorm.Session{NewDB: true}).Model(&label{}).Select("resource_id").
+			Where("resource = ? and label_key = ?", resource, require.Key)
+
+		switch require.Operator {
+		case selection.Equals, selection.DoubleEquals, selection.In:
+			db = db.Where("id in (?)", ids.Where("label_value in (?)", require.Values))
+		case selection.NotEquals, selection.NotIn:
+			// The resources without the label match too.
+			db = db.Where("id not in (?)", ids.Where("label_value in (?)", require.Values))
+		case selection.Exists:
+			db = db.Where("id in (?)", ids)
+		case selection.DoesNotExist:
+			db = db.Where("id not in (?)", ids)
+		case selection.GreaterThan, selection.LessThan:
+			n, _ := strconv.ParseInt(require.Values[0], 10, 64)
+
+			op := ">"
+			if require.Operator == selection.LessThan {
+				op = "<"
+			}
+
+			db = db.Where("id in (?)", ids.Where("label_number "+op+" ?", n))
+		}
+	}
+
+	return db, nil
+}
@@ -0,0 +1,298 @@
+package mysql
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"gobackend/pkg/db/migrate"
+)
+
+// The schemas below are snapshots of the models at the time the migration is written,
+// so that a migration always does the same thing no matter how the models change later.
+// DO NOT modify an existing migration, append a new one instead.
+
+type userV1 struct {
+	ID           uint64         `gorm:"primary_key;AUTO_INCREMENT;column:id"`
+	CreatedAt    time.Time      `gorm:"column:created_at"`
+	UpdatedAt    time.Time      `gorm:"column:updated_at"`
+	DeletedAt    gorm.DeletedAt `gorm:"index;column:deleted_at"`
+	InstanceID   string         `gorm:"unique;column:instance_id;type:varchar(32);not null"`
+	Name         string         `gorm:"column:name;type:varchar(64);not null"`
+	ExtendShadow string         `gorm:"column:extend_shadow"`
+
+	Nickname string `gorm:"column:nickname"`
+	Password string `gorm:"column:password"`
+	Email    string `gorm:"column:email"`
+	Phone    string `gorm:"column:phone"`
+	IsAdmin  int    `gorm:"column:is_admin"`
+}
+
+func (u *userV1) TableName() string {
+	return "user"
+}
+
+type secretV1 struct {
+	ID           uint64         `gorm:"primary_key;AUTO_INCREMENT;column:id"`
+	CreatedAt    time.Time      `gorm:"column:created_at"`
+	UpdatedAt    time.Time      `gorm:"column:updated_at"`
+	DeletedAt    gorm.DeletedAt `gorm:"index;column:deleted_at"`
+	InstanceID   string         `gorm:"unique;column:instance_id;type:varchar(32);not null"`
+	Name         string         `gorm:"column:name;type:varchar(64);not null"`
+	ExtendShadow string         `gorm:"column:extend_shadow"`
+
+	Username    string `gorm:"index;column:username;type:varchar(64);not null"`
+	SecretID    string `gorm:"unique;column:secret_id;type:varchar(64);not null"`
+	SecretKey   string `gorm:"column:secret_key;type:varchar(255);not null"`
+	Expires     int64  `gorm:"column:expires"`
+	Description string `gorm:"column:description"`
+}
+
+func (s *secretV1) TableName() string {
+	return "secret"
+}
+
+type policyV1 struct {
+	ID           uint64         `gorm:"primary_key;AUTO_INCREMENT;column:id"`
+	CreatedAt    time.Time      `gorm:"column:created_at"`
+	UpdatedAt    time.Time      `gorm:"column:updated_at"`
+	DeletedAt    gorm.DeletedAt `gorm:"index;column:deleted_at"`
+	InstanceID   string         `gorm:"unique;column:instance_id;type:varchar(32);not null"`
+	Name         string         `gorm:"column:name;type:varchar(64);not null"`
+	ExtendShadow string         `gorm:"column:extend_shadow"`
+
+	Username     string `gorm:"index;column:username;type:varchar(64);not null"`
+	Description  string `gorm:"column:description"`
+	Effect       string `gorm:"column:effect;type:varchar(8);not null"`
+	PolicyShadow string `gorm:"column:policy_shadow;type:text"`
+}
+
+func (p *policyV1) TableName() string {
+	return "policy"
+}
+
+type operationLogV1 struct {
+	ID         uint64         `gorm:"primary_key;AUTO_INCREMENT;column:id"`
+	CreatedAt  time.Time      `gorm:"column:created_at"`
+	UpdatedAt  time.Time      `gorm:"column:updated_at"`
+	DeletedAt  gorm.DeletedAt `gorm:"index;column:deleted_at"`
+	Username   string         `gorm:"index;column:username"`
+	UserAgent  string         `gorm:"column:user_agent"`
+	ClientIP   string         `gorm:"column:client_ip"`
+	ReqMethod  string         `gorm:"index;column:req_method"`
+	ReqPath    string         `gorm:"index;column:req_path"`
+	ReqBody    string         `gorm:"column:req_body"`
+	ReqReferer string         `gorm:"column:req_referer"`
+	ReqTime    time.Time      `gorm:"column:req_time"`
+	ReqLatency float64        `gorm:"column:req_latency"`
+	HTTPStatus int            `gorm:"index;column:http_status"`
+	ResData    string         `gorm:"column:res_data"`
+}
+
+func (o *operationLogV1) TableName() string {
+	return "operation_log"
+}
+
+// The unique indexes make sure the names are unique in the scope of their owner.
+
+type userV2 struct {
+	Name string `gorm:"uniqueIndex:uk_user_name;column:name"`
+}
+
+func (u *userV2) TableName() string {
+	return "user"
+}
+
+type secretV2 struct {
+	Username string `gorm:"uniqueIndex:uk_secret_username_name,priority:1;column:username"`
+	Name     string `gorm:"uniqueIndex:uk_secret_username_name,priority:2;column:name"`
+}
+
+func (s *secretV2) TableName() string {
+	return "secret"
+}
+
+type policyV2 struct {
+	Username string `gorm:"uniqueIndex:uk_policy_username_name,priority:1;column:username"`
+	Name     string `gorm:"uniqueIndex:uk_policy_username_name,priority:2;column:name"`
+}
+
+func (p *policyV2) TableName() string {
+	return "policy"
+}
+
+// The resource version is used for optimistic concurrency control.
+
+type userV3 struct {
+	ResourceVersion uint64 `gorm:"column:resource_version;not null;default:1"`
+}
+
+func (u *userV3) TableName() string {
+	return "user"
+}
+
+type secretV3 struct {
+	ResourceVersion uint64 `gorm:"column:resource_version;not null;default:1"`
+}
+
+func (s *secretV3) TableName() string {
+	return "secret"
+}
+
+type policyV3 struct {
+	ResourceVersion uint64 `gorm:"column:resource_version;not null;default:1"`
+}
+
+func (p *policyV3) TableName() string {
+	return "policy"
+}
+
+// Labels are stored in the labels_shadow columns and indexed by the label table.
+
+type labelV1 struct {
+	ID         uint64 `gorm:"primary_key;AUTO_INCREMENT;column:id"`
+	Resource   string `gorm:"uniqueIndex:uk_label_resource_key,priority:1;index:idx_label_key_value,priority:1;index:idx_label_key_number,priority:1;column:resource;type:varchar(32);not null"`
+	ResourceID uint64 `gorm:"uniqueIndex:uk_label_resource_key,priority:2;column:resource_id;not null"`
+	Key        string `gorm:"uniqueIndex:uk_label_resource_key,priority:3;index:idx_label_key_value,priority:2;index:idx_label_key_number,priority:2;column:label_key;type:varchar(317);not null"`
+	Value      string `gorm:"index:idx_label_key_value,priority:3;column:label_value;type:varchar(63);not null"`
+	Number     *int64 `gorm:"index:idx_label_key_number,priority:3;column:label_number"`
+}
+
+func (l *labelV1) TableName() string {
+	return "label"
+}
+
+type userV4 struct {
+	LabelsShadow string `gorm:"column:labels_shadow"`
+}
+
+func (u *userV4) TableName() string {
+	return "user"
+}
+
+type secretV4 struct {
+	LabelsShadow string `gorm:"column:labels_shadow"`
+}
+
+func (s *secretV4) TableName() string {
+	return "secret"
+}
+
+type policyV4 struct {
+	LabelsShadow string `gorm:"column:labels_shadow"`
+}
+
+func (p *policyV4) TableName() string {
+	return "policy"
+}
+
+// migrations are the versioned schema changes of the apiserver.
+var migrations = []migrate.Migration{
+	createTable(1, "create user table", &userV1{}),
+	createTable(2, "create secret table", &secretV1{}),
+	createTable(3, "create policy table", &policyV1{}),
+	createTable(4, "create operation_log table", &operationLogV1{}),
+	createIndexes(5, "add unique indexes of resource names",
+		index{&userV2{}, "uk_user_name"},
+		index{&secretV2{}, "uk_secret_username_name"},
+		index{&policyV2{}, "uk_policy_username_name"},
+	),
+	addColumns(6, "add resource_version columns",
+		column{&userV3{}, "ResourceVersion"},
+		column{&secretV3{}, "ResourceVersion"},
+		column{&policyV3{}, "ResourceVersion"},
+	),
+	createTable(7, "create label table", &labelV1{}),
+	addColumns(8, "add labels_shadow columns",
+		column{&userV4{}, "LabelsShadow"},
+		column{&secretV4{}, "LabelsShadow"},
+		column{&policyV4{}, "LabelsShadow"},
+	),
+}
+
+// createTable returns a migration which creates the table of model.
+// The table may have been created by the AutoMigrate of older versions,
+// so it is only created if not exists.
+func createTable(version uint64, description string, model interface{}) migrate.Migration {
+	return migrate.Migration{
+		Version:     version,
+		Description: description,
+		Up: func(tx *gorm.DB) error {
+			if tx.Migrator().HasTable(model) {
+				return nil
+			}
+
+			return tx.Migrator().CreateTable(model)
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(model)
+		},
+	}
+}
+
+type index struct {
+	model interface{}
+	name  string
+}
+
+// createIndexes returns a migration which creates the indexes defined by the tags of models.
+func createIndexes(version uint64, description string, indexes ...index) migrate.Migration {
+	return migrate.Migration{
+		Version:     version,
+		Description: description,
+		Up: func(tx *gorm.DB) error {
+			for _, idx := range indexes {
+				if err := tx.Migrator().CreateIndex(idx.model, idx.name); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+		Down: func(tx *gorm.DB) error {
+			for _, idx := range indexes {
+				// sqlite drops the indexes of a table when a column is dropped, which rebuilds the table.
+				if !tx.Migrator().HasIndex(idx.model, idx.name) {
+					continue
+				}
+
+				if err := tx.Migrator().DropIndex(idx.model, idx.name); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+	}
+}
+
+type column struct {
+	model interface{}
+	field string
+}
+
+// addColumns returns a migration which adds the columns defined by the fields of models.
+func addColumns(version uint64, description string, columns ...column) migrate.Migration {
+	return migrate.Migration{
+		Version:     version,
+		Description: description,
+		Up: func(tx *gorm.DB) error {
+			for _, col := range columns {
+				if err := tx.Migrator().AddColumn(col.model, col.field); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+		Down: func(tx *gorm.DB) error {
+			for _, col := range columns {
+				if err := tx.Migrator().DropColumn(col.model, col.field); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+	}
+}
@@ -1,20 +1,25 @@
 package mysql
 
 import (
+	"context"
 	"fmt"
+	"reflect"
 	"sync"
 	"time"
 
-	"github.com/spf13/viper"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
 
 	"gobackend/pkg/db"
+	"gobackend/pkg/db/migrate"
 	"gobackend/pkg/errors"
 	"gobackend/pkg/log"
+	metav1 "gobackend/pkg/meta/v1"
+	"gobackend/pkg/util/gormtool"
 
 	"gobackend/internal/app/apiserver/store"
-	"gobackend/internal/pkg/entity/apiserver/operationlog"
-	v1 "gobackend/internal/pkg/entity/apiserver/v1"
+	"gobackend/internal/pkg/code"
 	"gobackend/internal/pkg/gormlog"
 	genericoptions "gobackend/internal/pkg/options"
 )
@@ -31,10 +36,33 @@ func (ds *datastore) Users() store.UserStore {
 	return newUsers(ds)
 }
 
+func (ds *datastore) Secrets() store.SecretStore {
+	return newSecrets(ds)
+}
+
+func (ds *datastore) Policies() store.PolicyStore {
+	return newPolicies(ds)
+}
+
 func (ds *datastore) OperationLogs() store.OperationLogStore {
 	return newOperationLogs(ds)
 }
 
+func (ds *datastore) Tx(ctx context.Context, fn func(store.Factory) error) error {
+	return ds.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(&datastore{tx})
+	})
+}
+
+func (ds *datastore) Ping(ctx context.Context) error {
+	db, err := ds.db.DB()
+	if err != nil {
+		return errors.Wrap(err, "get gorm db instance failed")
+	}
+
+	return db.PingContext(ctx)
+}
+
 func (ds *datastore) Close() error {
 	db, err := ds.db.DB()
 	if err != nil {
@@ -60,25 +88,13 @@ func InitMySQLFactory(opts *genericoptions.MySQLOptions) error {
 	var dbIns *gorm.DB
 
 	once.Do(func() {
-		options := &db.Options{
-			Host:                  opts.Host,
-			Username:              opts.Username,
-			Password:              opts.Password,
-			Database:              opts.Database,
-			MaxIdleConnections:    opts.MaxIdleConnections,
-			MaxOpenConnections:    opts.MaxOpenConnections,
-			MaxConnectionLifetime: opts.MaxConnectionLifetime * time.Second, //nolint:durationcheck
-			Logger:                gormlog.New(opts.LogLevel),
-		}
-
-		log.Info("start connecting mysql database ...")
-		dbIns, err = db.New(options)
+		dbIns, err = newDB(opts)
 		if err != nil {
 			return
 		}
 
 		if opts.AutoMigrate {
-			log.Info("start auto migrate mysql database ...")
+			log.Infof("start migrating %s database ...", opts.Driver)
 			err = migrateDatabase(dbIns)
 			if err != nil {
 				err = fmt.Errorf("migrate database failed: %w", err)
@@ -105,41 +121,271 @@ func InitMySQLFactory(opts *genericoptions.MySQLOptions) error {
 	return nil
 }
 
-// nolint:unused
-// cleanDatabase tear downs the database tables.
-// may be reused in the feature, or just show a migrate usage.
-func cleanDatabase(db *gorm.DB) error {
-	if err := db.Migrator().DropTable(&v1.User{}); err != nil {
-		return errors.Wrap(err, "drop user table failed")
+// NewMigrator connects to the database and returns the schema migrator of the apiserver,
+// the returned function closes the database connection.
+func NewMigrator(opts *genericoptions.MySQLOptions) (*migrate.Migrator, func() error, error) {
+	dbIns, err := newDB(opts)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	return nil
+	closeDB := (&datastore{dbIns}).Close
+
+	migrator, err := migrate.New(dbIns, migrations)
+	if err != nil {
+		_ = closeDB()
+
+		return nil, nil, err
+	}
+
+	return migrator, closeDB, nil
 }
 
-// migrateDatabase run auto migration for given models, will only add missing fields,
-// won't delete/change current data.
-// nolint:unused // may be reused in the feature, or just show a migrate usage.
+func newDB(opts *genericoptions.MySQLOptions) (*gorm.DB, error) {
+	options := &db.Options{
+		Driver:                opts.Driver,
+		Host:                  opts.Host,
+		Username:              opts.Username,
+		Password:              opts.Password,
+		Database:              opts.Database,
+		SSLMode:               opts.SSLMode,
+		MaxIdleConnections:    opts.MaxIdleConnections,
+		MaxOpenConnections:    opts.MaxOpenConnections,
+		MaxConnectionLifetime: opts.MaxConnectionLifetime * time.Second, //nolint:durationcheck
+		Logger:                gormlog.New(opts.LogLevel),
+	}
+
+	log.Infof("start connecting %s database ...", opts.Driver)
+
+	return db.New(options)
+}
+
+// withCode translates a database error into an error with code regardless of the driver,
+// record not found and duplicate key errors are translated into the given codes.
+func withCode(err error, notFound, alreadyExist int) error {
+	switch {
+	case db.IsNotFound(err):
+		return errors.WithCode(notFound, err.Error())
+	case db.IsDuplicateKey(err):
+		return errors.WithCode(alreadyExist, err.Error())
+	default:
+		return errors.WithCode(code.ErrDatabase, err.Error())
+	}
+}
+
+// migrateDatabase applies all the pending migrations.
 func migrateDatabase(db *gorm.DB) error {
-	tables := []interface{}{
-		&v1.User{},
+	migrator, err := migrate.New(db, migrations)
+	if err != nil {
+		return err
+	}
+
+	n, err := migrator.Up(0)
+	if err != nil {
+		return err
 	}
 
-	if viper.GetBool("feature.operation-logging") {
-		tables = append(tables, &operationlog.OperationLog{})
+	log.Infof("%d migrations applied", n)
+
+	return nil
+}
+
+// listContext bounds the list query with opts.TimeoutSeconds.
+func listContext(ctx context.Context, opts metav1.ListOptions) (context.Context, context.CancelFunc) {
+	if opts.TimeoutSeconds == nil || *opts.TimeoutSeconds <= 0 {
+		return ctx, func() {}
 	}
 
-	return db.AutoMigrate(tables...)
+	return context.WithTimeout(ctx, time.Duration(*opts.TimeoutSeconds)*time.Second)
 }
 
-// resetDatabase resets the database tables.
-// nolint:unused,deadcode // may be reused in the feature, or just show a migrate usage.
-func resetDatabase(db *gorm.DB) error {
-	if err := cleanDatabase(db); err != nil {
+// list finds a page of the records matching the query into items, a pointer to a slice of
+// pointers to the records, and fills meta. The records are sorted by opts.SortBy and only the
+// columns of opts.Fields are read, both are validated against the whitelists.
+// The page starts after the record in the continue token, which is as cheap as the first page
+// even on large tables, or at the offset if there is no token. One more record is read to know
+// whether there is a next page.
+func list(
+	db *gorm.DB,
+	opts metav1.ListOptions,
+	whitelist store.Fields,
+	columns store.Columns,
+	items interface{},
+	meta *metav1.ListMeta,
+) error {
+	sort, err := whitelist.Sort(opts.SortBy)
+	if err != nil {
+		return err
+	}
+
+	position, err := sort.ParseContinue(opts)
+	if err != nil {
 		return err
 	}
-	if err := migrateDatabase(db); err != nil {
+
+	// The sort fields are needed to make the continue token.
+	selected, err := columns.Select(opts.Fields, sort.Fields()...)
+	if err != nil {
 		return err
 	}
 
+	// The count and the find queries share the conditions but nothing else.
+	db = db.Session(&gorm.Session{})
+
+	if store.CountTotal(opts) {
+		if err := db.Model(items).Count(&meta.TotalCount).Error; err != nil {
+			return err
+		}
+	}
+
+	query := db
+	for _, sf := range sort {
+		query = query.Order(clause.OrderByColumn{Column: clause.Column{Name: sf.Field}, Desc: sf.Desc})
+
+	}
+
+	if selected != nil {
+		query = query.Select(selected)
+	}
+
+	ol := gormtool.Unpointer(opts.Offset, opts.Limit)
+	if position != nil {
+		query = query.Where(after(sort, position))
+	} else {
+		query = query.Offset(ol.Offset)
+	}
+
+	// Non-positive limit means no limit.
+	if ol.Limit > 0 {
+		query = query.Limit(ol.Limit + 1)
+	}
+
+	if err := query.Find(items).Error; err != nil {
+		return err
+	}
+
+	v := reflect.ValueOf(items).Elem()
+	if ol.Limit > 0 && v.Len() > ol.Limit {
+		v.Set(v.Slice(0, ol.Limit))
+
+		last := v.Index(ol.Limit - 1).Elem()
+		values := make([]interface{}, 0, len(sort))
+
+		for _, sf := range sort {
+			value, _ := query.Statement.Schema.LookUpField(sf.Field).ValueOf(last)
+			values = append(values, value)
+		}
+
+		meta.Continue = sort.Continue(values)
+	}
+
+	return nil
+}
+
+// after returns the condition of the records after the position in the sort order,
+// i.e. the first sort field is after the position, or it is equal and the second one is after, etc.
+func after(sort store.Sort, position []interface{}) clause.Expression {
+	exprs := make([]clause.Expression, 0, len(sort))
+
+	for i, sf := range sort {
+		and := make([]clause.Expression, 0, i+1)
+		for j := 0; j < i; j++ {
+			and = append(and, clause.Eq{Column: clause.Column{Name: sort[j].Field}, Value: position[j]})
+		}
+
+		column := clause.Column{Name: sf.Field}
+		if sf.Desc {
+			and = append(and, clause.Lt{Column: column, Value: position[i]})
+		} else {
+			and = append(and, clause.Gt{Column: column, Value: position[i]})
+		}
+
+		exprs = append(exprs, clause.And(and...))
+	}
+
+	// A single Or condition would be joined to the other conditions with OR by gorm.
+	if len(exprs) == 1 {
+		return exprs[0]
+	}
+
+	return clause.Or(exprs...)
+}
+
+// update saves all the fields and the labels of model and increases the resource version, only if the
+// resource version in the database is still the one in meta. Otherwise the record has been
+// modified or deleted after it was read, and ErrResourceConflict is returned.
+// The resource version is kept for a dry run.
+func update(db *gorm.DB, model schema.Tabler, meta *metav1.ObjectMeta, opts metav1.UpdateOptions) error {
+	version := meta.ResourceVersion
+	meta.ResourceVersion++
+
+	err := dryRun(db, opts.DryRun, func(tx *gorm.DB) error {
+		return tx.Transaction(func(tx *gorm.DB) error {
+			result := tx.Model(model).Select("*").Where("resource_version = ?", version).Updates(model)
+			if result.Error != nil {
+				return errors.WithCode(code.ErrDatabase, result.Error.Error())
+			}
+
+			if result.RowsAffected == 0 {
+				return errors.WithCode(code.ErrResourceConflict, "resource version %d of `%s` is out of date", version, meta.Name)
+			}
+
+			if err := saveLabels(tx, model.TableName(), meta); err != nil {
+				return errors.WithCode(code.ErrDatabase, err.Error())
+			}
+
+			return nil
+		})
+	})
+	if err != nil || metav1.IsDryRun(opts.DryRun) {
+		meta.ResourceVersion = version
+	}
+
+	return err
+}
+
+// errDryRun rolls back the transaction of a dry run.
+var errDryRun = errors.New("dry run")
+
+// dryRun runs fn with db. If it is a dry run, fn runs in a transaction which is always
+// rolled back, so that it goes through the hooks and the constraints of the database,
+// e.g. the unique indexes, without persisting anything.
+func dryRun(db *gorm.DB, dryRun []string, fn func(tx *gorm.DB) error) error {
+	if !metav1.IsDryRun(dryRun) {
+		return fn(db)
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := fn(tx); err != nil {
+			return err
+		}
+
+		return errDryRun
+	})
+	if errors.Is(err, errDryRun) {
+		return nil
+	}
+
+	return err
+}
+
+// dryRunCreate creates the record of model with dryRun. The fields populated by the database
+// are cleared after a dry run, the record they refer to is rolled back.
+func dryRunCreate(db *gorm.DB, opts metav1.CreateOptions, model schema.Tabler, meta *metav1.ObjectMeta) error {
+	err := dryRun(db, opts.DryRun, func(tx *gorm.DB) error {
+		return create(tx, model, meta)
+	})
+	if err != nil {
+		return err
+	}
+
+	if metav1.IsDryRun(opts.DryRun) {
+		meta.ID = 0
+		meta.InstanceID = ""
+		meta.ResourceVersion = 0
+		meta.CreatedAt = time.Time{}
+		meta.UpdatedAt = time.Time{}
+	}
+
 	return nil
 }
@@ -0,0 +1,38 @@
+//go:build cgo
+// +build cgo
+
+package mysql
+
+import (
+	"path/filepath"
+	"testing"
+
+	"gorm.io/gorm/logger"
+
+	"gobackend/pkg/db"
+)
+
+// newTestStore returns a datastore backed by a migrated sqlite database in a temporary directory.
+func newTestStore(t *testing.T) *datastore {
+	t.Helper()
+
+	dbIns, err := db.New(&db.Options{
+		Driver:             db.SQLite,
+		Database:           filepath.Join(t.TempDir(), "apiserver.db"),
+		MaxIdleConnections: 1,
+		MaxOpenConnections: 1,
+		Logger:             logger.Discard,
+	})
+	if err != nil {
+		t.Fatalf("db.New() error = %v", err)
+	}
+
+	ds := &datastore{dbIns}
+	t.Cleanup(func() { _ = ds.Close() })
+
+	if err := migrateDatabase(dbIns); err != nil {
+		t.Fatalf("migrateDatabase() error = %v", err)
+	}
+
+	return ds
+}
@@ -2,14 +2,18 @@ package mysql
 
 import (
 	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	gorm "gorm.io/gorm"
 
+	"gobackend/pkg/db"
 	"gobackend/pkg/errors"
-	"gobackend/pkg/fields"
 	metav1 "gobackend/pkg/meta/v1"
-	"gobackend/pkg/util/gormtool"
 
+	"gobackend/internal/app/apiserver/store"
 	"gobackend/internal/pkg/code"
 	"gobackend/internal/pkg/entity/apiserver/operationlog"
 )
@@ -28,7 +32,24 @@ func (o *operationLogs) Create(
 	operationLog *operationlog.OperationLog,
 	opts metav1.CreateOptions,
 ) error {
-	return o.db.Create(&operationLog).Error
+	return o.db.WithContext(ctx).Create(&operationLog).Error
+}
+
+// CreateCollection batch creates the OperationLog records in a statement.
+func (o *operationLogs) CreateCollection(
+	ctx context.Context,
+	operationLogs []*operationlog.OperationLog,
+	opts metav1.CreateOptions,
+) error {
+	if len(operationLogs) == 0 {
+		return nil
+	}
+
+	if err := o.db.WithContext(ctx).Create(&operationLogs).Error; err != nil {
+		return errors.WithCode(code.ErrDatabase, err.Error())
+	}
+
+	return nil
 }
 
 // Delete an OperationLog record.
@@ -37,11 +58,14 @@ func (o *operationLogs) Delete(
 	id string,
 	opts metav1.DeleteOptions,
 ) error {
+	db := o.db.WithContext(ctx)
 	if opts.Unscoped {
-		o.db = o.db.Unscoped()
+		db = db.Unscoped()
 	}
 
-	err := o.db.Where("id = ?", id).Delete(&operationlog.OperationLog{}).Error
+	err := dryRun(db, opts.DryRun, func(tx *gorm.DB) error {
+		return tx.Where("id = ?", id).Delete(&operationlog.OperationLog{}).Error
+	})
 	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 		return errors.WithCode(code.ErrDatabase, err.Error())
 	}
@@ -49,51 +73,236 @@ func (o *operationLogs) Delete(
 	return nil
 }
 
+// DeleteCollection batch deletes the OperationLog records.
+func (o *operationLogs) DeleteCollection(
+	ctx context.Context,
+	ids []uint64,
+	opts metav1.DeleteOptions,
+) error {
+	db := o.db.WithContext(ctx)
+	if opts.Unscoped {
+		db = db.Unscoped()
+	}
+
+	err := dryRun(db, opts.DryRun, func(tx *gorm.DB) error {
+		return tx.Where("id in (?)", ids).Delete(&operationlog.OperationLog{}).Error
+	})
+	if err != nil {
+		return errors.WithCode(code.ErrDatabase, err.Error())
+	}
+
+	return nil
+}
+
 // List OperationLog records.
 func (o *operationLogs) List(
 	ctx context.Context,
 	opts metav1.ListOptions,
 ) (*operationlog.List, error) {
-	ret := &operationlog.List{}
-	ol := gormtool.Unpointer(opts.Offset, opts.Limit)
+	ctx, cancel := listContext(ctx, opts)
+	defer cancel()
 
-	var (
-		where    string
-		selector fields.Selector
-		err      error
-	)
-
-	// opt.FieldSelector e.g.:
-	// https://.../?field_selector=req_method==PUT,req_path=/users
+	ret := &operationlog.List{}
+	// opts.FieldSelector e.g.:
+	// https://.../?field_selector=req_method==PUT,http_status>=400,req_time>=2021-01-01T00:00:00Z
 	// == means exact match, and = means fuzzy match.
-	selector, err = fields.ParseSelector(opts.FieldSelector)
+	db, err := selectFields(o.db.WithContext(ctx), store.OperationLogFields, opts.FieldSelector)
 	if err != nil {
 		return nil, err
 	}
 
-	for _, require := range selector.Requirements() {
-		switch require.Field {
-		case "req_method":
-			where, err = buildWhere(require, where)
-		case "req_path":
-			where, err = buildWhere(require, where)
-		case "http_status":
-			where, err = buildWhere(require, where)
+	if err := list(db, opts, store.OperationLogFields, store.OperationLogColumns, &ret.Items, &ret.ListMeta); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+// latencyBucketExpr is the SQL expression of the index of the bucket of req_latency, see store.LatencyBucket.
+var latencyBucketExpr = func() string {
+	var b strings.Builder
+
+	b.WriteString("CASE")
+
+	for i, bound := range store.LatencyBuckets {
+		fmt.Fprintf(&b, " WHEN req_latency <= %s THEN %d", strconv.FormatFloat(bound, 'f', -1, 64), i)
+	}
+
+	fmt.Fprintf(&b, " ELSE %d END", len(store.LatencyBuckets))
+
+	return b.String()
+}()
+
+// aggregateFieldExpr returns the SQL expression of a field the dimensions are derived from.
+// The query strings are cut from req_path, and req_time is truncated to the quarters of an hour
+// since the Unix epoch, so that the days and the hours in UTC are got from it even if it is
+// stored in a time zone with a 30 or 45 minutes offset.
+func aggregateFieldExpr(dialect, field string) (string, error) {
+	switch field {
+	case "req_path":
+		switch dialect {
+		case db.MySQL:
+			return "SUBSTRING_INDEX(req_path, '?', 1)", nil
+		case db.PostgreSQL:
+			return "split_part(req_path, '?', 1)", nil
+		case db.SQLite:
+			return "CASE WHEN instr(req_path, '?') > 0 THEN substr(req_path, 1, instr(req_path, '?') - 1) " +
+				"ELSE req_path END", nil
+		}
+	case "req_time":
+		switch dialect {
+		case db.MySQL:
+			// The times are stored in the local time zone without the offset, see the DSN.
+			return "TIMESTAMPDIFF(MINUTE, '1970-01-01', req_time) DIV 15", nil
+		case db.PostgreSQL:
+			return "CAST(FLOOR(EXTRACT(EPOCH FROM req_time) / 900) AS BIGINT)", nil
+		case db.SQLite:
+			return "CAST(strftime('%s', req_time) AS INTEGER) / 900", nil
+		}
+	default:
+		return field, nil
+	}
+
+	return "", errors.WithCode(code.ErrDatabase, "operation logs can not be aggregated in %s", dialect)
+}
+
+// quarterTime returns the time of the quarters of an hour since the Unix epoch, which are
+// counted on the wall clock of loc.
+func quarterTime(quarters int64, loc *time.Location) time.Time {
+	wall := time.Unix(quarters*15*60, 0).UTC()
+
+	return time.Date(wall.Year(), wall.Month(), wall.Day(), wall.Hour(), wall.Minute(), 0, 0, loc)
+}
+
+// Aggregate groups the OperationLog records by the dimensions and the buckets of the latencies
+// in a query, the rows of a group are merged into an aggregate, so the memory used is bounded by
+// the number of the groups rather than the records.
+func (o *operationLogs) Aggregate(
+	ctx context.Context,
+	opts metav1.ListOptions,
+	dimensions []string,
+) ([]*operationlog.Aggregate, error) {
+	ctx, cancel := listContext(ctx, opts)
+	defer cancel()
+
+	dialect := o.db.Dialector.Name()
+
+	// The fields the dimensions are derived from, a field is selected once, e.g. for day and hour.
+	var fields []string
+
+	selected := make(map[string]bool)
+	selects := []string{}
+
+	for _, dimension := range dimensions {
+		field, ok := store.OperationLogDimensions[dimension]
+		if !ok {
+			return nil, errors.WithCode(code.ErrValidation, "unsupported dimension '%s'", dimension)
 		}
+
+		if selected[field] {
+			continue
+		}
+
+		selected[field] = true
+
+		expr, err := aggregateFieldExpr(dialect, field)
+		if err != nil {
+			return nil, err
+		}
+
+		selects = append(selects, fmt.Sprintf("%s AS f%d", expr, len(fields)))
+		fields = append(fields, field)
 	}
 
+	selects = append(selects,
+		latencyBucketExpr+" AS bucket",
+		"COUNT(*) AS count",
+		"SUM(CASE WHEN http_status >= 400 THEN 1 ELSE 0 END) AS error_count",
+		"SUM(req_latency) AS sum_latency",
+		"MAX(req_latency) AS max_latency",
+	)
+
+	query, err := selectFields(o.db.WithContext(ctx), store.OperationLogFields, opts.FieldSelector)
 	if err != nil {
 		return nil, err
 	}
 
-	r := o.db.Where(where).
-		Offset(ol.Offset).
-		Limit(ol.Limit).
-		Order("id desc").
-		Find(&ret.Items).
-		Offset(-1).
-		Limit(-1).
-		Count(&ret.TotalCount)
+	query = query.Model(&operationlog.OperationLog{}).Select(strings.Join(selects, ", "))
+	for i := range fields {
+		query = query.Group("f" + strconv.Itoa(i))
+	}
+
+	rows, err := query.Group("bucket").Rows()
+	if err != nil {
+		return nil, errors.WithCode(code.ErrDatabase, err.Error())
+	}
+	defer rows.Close()
+
+	loc := time.UTC
+	if dialect == db.MySQL {
+		loc = time.Local
+	}
+
+	groups := make(map[string]*operationlog.Aggregate)
+	ret := []*operationlog.Aggregate{}
+
+	for rows.Next() {
+		var (
+			item     operationlog.OperationLog
+			quarters int64
+			bucket   int
+		)
+
+		row := &operationlog.Aggregate{}
+		dest := make([]interface{}, 0, len(fields)+5)
+
+		for _, field := range fields {
+			switch field {
+			case "username":
+				dest = append(dest, &item.Username)
+			case "client_ip":
+				dest = append(dest, &item.ClientIP)
+			case "req_method":
+				dest = append(dest, &item.ReqMethod)
+			case "req_path":
+				dest = append(dest, &item.ReqPath)
+			case "http_status":
+				dest = append(dest, &item.HTTPStatus)
+			case "req_time":
+				dest = append(dest, &quarters)
+			}
+		}
+
+		dest = append(dest, &bucket, &row.Count, &row.ErrorCount, &row.SumLatency, &row.MaxLatency)
+		if err := rows.Scan(dest...); err != nil {
+			return nil, errors.WithCode(code.ErrDatabase, err.Error())
+		}
+
+		item.ReqTime = quarterTime(quarters, loc)
+
+		row.Latencies = make([]int64, len(store.LatencyBuckets)+1)
+		row.Latencies[bucket] = row.Count
+
+		values := make([]string, len(dimensions))
+		for i, dimension := range dimensions {
+			values[i] = store.OperationLogDimension(dimension, &item)
+		}
+
+		key := strings.Join(values, "\x00")
+
+		group, ok := groups[key]
+		if !ok {
+			group = &operationlog.Aggregate{Group: values}
+			groups[key] = group
+			ret = append(ret, group)
+		}
+
+		group.Merge(row)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.WithCode(code.ErrDatabase, err.Error())
+	}
 
-	return ret, r.Error
+	return ret, nil
 }
@@ -0,0 +1,140 @@
+package mysql
+
+import (
+	"context"
+
+	gorm "gorm.io/gorm"
+
+	"gobackend/pkg/errors"
+	metav1 "gobackend/pkg/meta/v1"
+
+	"gobackend/internal/app/apiserver/store"
+	"gobackend/internal/pkg/code"
+	v1 "gobackend/internal/pkg/entity/apiserver/v1"
+)
+
+type policies struct {
+	db *gorm.DB
+}
+
+func newPolicies(ds *datastore) *policies {
+	return &policies{db: ds.db}
+}
+
+// Create creates a new policy.
+func (p *policies) Create(ctx context.Context, policy *v1.Policy, opts metav1.CreateOptions) error {
+	err := dryRunCreate(p.db.WithContext(ctx), opts, policy, &policy.ObjectMeta)
+	if err != nil {
+		return withCode(err, code.ErrPolicyNotFound, code.ErrPolicyAlreadyExist)
+	}
+
+	return nil
+}
+
+// Update updates a policy if its resource version is not changed.
+func (p *policies) Update(ctx context.Context, policy *v1.Policy, opts metav1.UpdateOptions) error {
+	return update(p.db.WithContext(ctx), policy, &policy.ObjectMeta, opts)
+}
+
+// Delete deletes the policy by the policy name.
+func (p *policies) Delete(ctx context.Context, username, name string, opts metav1.DeleteOptions) error {
+	db := p.db.WithContext(ctx)
+	if opts.Unscoped {
+		db = db.Unscoped()
+	}
+
+	err := dryRun(db, opts.DryRun, func(tx *gorm.DB) error {
+		return remove(tx, &v1.Policy{}, opts.Unscoped, "username = ? and name = ?", username, name)
+	})
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return errors.WithCode(code.ErrDatabase, err.Error())
+	}
+
+	return nil
+}
+
+// DeleteCollection batch deletes the policies.
+func (p *policies) DeleteCollection(
+	ctx context.Context,
+	username string,
+	names []string,
+	opts metav1.DeleteOptions,
+) error {
+	db := p.db.WithContext(ctx)
+	if opts.Unscoped {
+		db = db.Unscoped()
+	}
+
+	return dryRun(db, opts.DryRun, func(tx *gorm.DB) error {
+		return remove(tx, &v1.Policy{}, opts.Unscoped, "username = ? and name in (?)", username, names)
+	})
+}
+
+// Get return a policy by the policy name.
+func (p *policies) Get(ctx context.Context, username, name string, opts metav1.GetOptions) (*v1.Policy, error) {
+	policy := &v1.Policy{}
+	err := p.db.WithContext(ctx).Where("username = ? and name = ?", username, name).First(&policy).Error
+	if err != nil {
+		return nil, withCode(err, code.ErrPolicyNotFound, code.ErrPolicyAlreadyExist)
+	}
+
+	return policy, nil
+}
+
+// List policies.
+func (p *policies) List(ctx context.Context, username string, opts metav1.ListOptions) (*v1.PolicyList, error) {
+	ctx, cancel := listContext(ctx, opts)
+	defer cancel()
+
+	ret := &v1.PolicyList{}
+	// opts.FieldSelector e.g.:
+	// https://.../?field_selector=name=mypolicy,effect==deny
+	// == means exact match, and = means fuzzy match.
+	db, err := selectFields(p.db.WithContext(ctx), store.PolicyFields, opts.FieldSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err = selectLabels(db, (&v1.Policy{}).TableName(), opts.LabelSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	if username != "" {
+		db = db.Where("username = ?", username)
+	}
+
+	if err := list(db, opts, store.PolicyFields, store.PolicyColumns, &ret.Items, &ret.ListMeta); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+// CountByUsers returns the number of policies owned by each of the given users.
+func (p *policies) CountByUsers(ctx context.Context, usernames []string) (map[string]int64, error) {
+	if len(usernames) == 0 {
+		return map[string]int64{}, nil
+	}
+
+	var rows []struct {
+		Username string
+		Total    int64
+	}
+
+	err := p.db.WithContext(ctx).Model(&v1.Policy{}).
+		Select("username, count(*) as total").
+		Where("username in (?)", usernames).
+		Group("username").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, errors.WithCode(code.ErrDatabase, err.Error())
+	}
+
+	ret := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		ret[row.Username] = row.Total
+	}
+
+	return ret, nil
+}
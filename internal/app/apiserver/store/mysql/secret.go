@@ -0,0 +1,112 @@
+package mysql
+
+import (
+	"context"
+
+	gorm "gorm.io/gorm"
+
+	"gobackend/pkg/errors"
+	metav1 "gobackend/pkg/meta/v1"
+
+	"gobackend/internal/app/apiserver/store"
+	"gobackend/internal/pkg/code"
+	v1 "gobackend/internal/pkg/entity/apiserver/v1"
+)
+
+type secrets struct {
+	db *gorm.DB
+}
+
+func newSecrets(ds *datastore) *secrets {
+	return &secrets{db: ds.db}
+}
+
+// Create creates a new secret.
+func (s *secrets) Create(ctx context.Context, secret *v1.Secret, opts metav1.CreateOptions) error {
+	err := dryRunCreate(s.db.WithContext(ctx), opts, secret, &secret.ObjectMeta)
+	if err != nil {
+		return withCode(err, code.ErrSecretNotFound, code.ErrSecretAlreadyExist)
+	}
+
+	return nil
+}
+
+// Update updates a secret information if its resource version is not changed.
+func (s *secrets) Update(ctx context.Context, secret *v1.Secret, opts metav1.UpdateOptions) error {
+	return update(s.db.WithContext(ctx), secret, &secret.ObjectMeta, opts)
+}
+
+// Delete deletes the secret by the secret name.
+func (s *secrets) Delete(ctx context.Context, username, name string, opts metav1.DeleteOptions) error {
+	db := s.db.WithContext(ctx)
+	if opts.Unscoped {
+		db = db.Unscoped()
+	}
+
+	err := dryRun(db, opts.DryRun, func(tx *gorm.DB) error {
+		return remove(tx, &v1.Secret{}, opts.Unscoped, "username = ? and name = ?", username, name)
+	})
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return errors.WithCode(code.ErrDatabase, err.Error())
+	}
+
+	return nil
+}
+
+// DeleteCollection batch deletes the secrets.
+func (s *secrets) DeleteCollection(
+	ctx context.Context,
+	username string,
+	names []string,
+	opts metav1.DeleteOptions,
+) error {
+	db := s.db.WithContext(ctx)
+	if opts.Unscoped {
+		db = db.Unscoped()
+	}
+
+	return dryRun(db, opts.DryRun, func(tx *gorm.DB) error {
+		return remove(tx, &v1.Secret{}, opts.Unscoped, "username = ? and name in (?)", username, names)
+	})
+}
+
+// Get return a secret by the secret name.
+func (s *secrets) Get(ctx context.Context, username, name string, opts metav1.GetOptions) (*v1.Secret, error) {
+	secret := &v1.Secret{}
+	err := s.db.WithContext(ctx).Where("username = ? and name = ?", username, name).First(&secret).Error
+	if err != nil {
+		return nil, withCode(err, code.ErrSecretNotFound, code.ErrSecretAlreadyExist)
+	}
+
+	return secret, nil
+}
+
+// List secrets.
+func (s *secrets) List(ctx context.Context, username string, opts metav1.ListOptions) (*v1.SecretList, error) {
+	ctx, cancel := listContext(ctx, opts)
+	defer cancel()
+
+	ret := &v1.SecretList{}
+	// opts.FieldSelector e.g.:
+	// https://.../?field_selector=name=mykey,secret_id==xxx
+	// == means exact match, and = means fuzzy match.
+	db, err := selectFields(s.db.WithContext(ctx), store.SecretFields, opts.FieldSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err = selectLabels(db, (&v1.Secret{}).TableName(), opts.LabelSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	if username != "" {
+		db = db.Where("username = ?", username)
+	}
+
+	if err := list(db, opts, store.SecretFields, store.SecretColumns, &ret.Items, &ret.ListMeta); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
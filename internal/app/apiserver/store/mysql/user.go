@@ -2,16 +2,14 @@ package mysql
 
 import (
 	"context"
-	"fmt"
-	"regexp"
 
 	gorm "gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"gobackend/pkg/errors"
-	"gobackend/pkg/fields"
 	metav1 "gobackend/pkg/meta/v1"
-	"gobackend/pkg/util/gormtool"
 
+	"gobackend/internal/app/apiserver/store"
 	"gobackend/internal/pkg/code"
 	v1 "gobackend/internal/pkg/entity/apiserver/v1"
 )
@@ -26,30 +24,29 @@ func newUsers(ds *datastore) *users {
 
 // Create creates a new user account.
 func (u *users) Create(ctx context.Context, user *v1.User, opts metav1.CreateOptions) error {
-	if err := u.db.Create(&user).Error; err != nil {
-		ok, _ := regexp.MatchString("^Error 1062:", err.Error())
-		if ok {
-			return errors.WithCode(code.ErrUserAlreadyExist, err.Error())
-		}
-
-		return err
+	err := dryRunCreate(u.db.WithContext(ctx), opts, user, &user.ObjectMeta)
+	if err != nil {
+		return withCode(err, code.ErrUserNotFound, code.ErrUserAlreadyExist)
 	}
 
 	return nil
 }
 
-// Update updates an user account information.
+// Update updates an user account information if its resource version is not changed.
 func (u *users) Update(ctx context.Context, user *v1.User, opts metav1.UpdateOptions) error {
-	return u.db.Save(user).Error
+	return update(u.db.WithContext(ctx), user, &user.ObjectMeta, opts)
 }
 
 // Delete deletes the user by the user identifier.
 func (u *users) Delete(ctx context.Context, username string, opts metav1.DeleteOptions) error {
+	db := u.db.WithContext(ctx)
 	if opts.Unscoped {
-		u.db = u.db.Unscoped()
+		db = db.Unscoped()
 	}
 
-	err := u.db.Where("name = ?", username).Delete(&v1.User{}).Error
+	err := dryRun(db, opts.DryRun, func(tx *gorm.DB) error {
+		return remove(tx, &v1.User{}, opts.Unscoped, "name = ?", username)
+	})
 	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 		return errors.WithCode(code.ErrDatabase, err.Error())
 	}
@@ -59,23 +56,27 @@ func (u *users) Delete(ctx context.Context, username string, opts metav1.DeleteO
 
 // DeleteCollection batch deletes the users.
 func (u *users) DeleteCollection(ctx context.Context, usernames []string, opts metav1.DeleteOptions) error {
+	db := u.db.WithContext(ctx)
 	if opts.Unscoped {
-		u.db = u.db.Unscoped()
+		db = db.Unscoped()
 	}
 
-	return u.db.Where("name in (?)", usernames).Delete(&v1.User{}).Error
+	return dryRun(db, opts.DryRun, func(tx *gorm.DB) error {
+		return remove(tx, &v1.User{}, opts.Unscoped, "name in (?)", usernames)
+	})
 }
 
 // Get return an user by the user identifier.
 func (u *users) Get(ctx context.Context, username string, opts metav1.GetOptions) (*v1.User, error) {
+	db := u.db.WithContext(ctx)
+	if opts.ForUpdate {
+		db = db.Clauses(clause.Locking{Strength: "UPDATE"})
+	}
+
 	user := &v1.User{}
-	err := u.db.Where("name = ?", username).First(&user).Error
+	err := db.Where("name = ?", username).First(&user).Error
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.WithCode(code.ErrUserNotFound, err.Error())
-		}
-
-		return nil, errors.WithCode(code.ErrDatabase, err.Error())
+		return nil, withCode(err, code.ErrUserNotFound, code.ErrUserAlreadyExist)
 	}
 
 	return user, nil
@@ -83,63 +84,26 @@ func (u *users) Get(ctx context.Context, username string, opts metav1.GetOptions
 
 // List users.
 func (u *users) List(ctx context.Context, opts metav1.ListOptions) (*v1.UserList, error) {
-	ret := &v1.UserList{}
-	ol := gormtool.Unpointer(opts.Offset, opts.Limit)
+	ctx, cancel := listContext(ctx, opts)
+	defer cancel()
 
-	var (
-		where    string
-		selector fields.Selector
-		err      error
-	)
-
-	// opt.FieldSelector e.g.:
+	ret := &v1.UserList{}
+	// opts.FieldSelector e.g.:
 	// https://.../?field_selector=name==levin,email=n@gmail.com
 	// == means exact match, and = means fuzzy match.
-	selector, err = fields.ParseSelector(opts.FieldSelector)
+	db, err := selectFields(u.db.WithContext(ctx), store.UserFields, opts.FieldSelector)
 	if err != nil {
 		return nil, err
 	}
 
-	for _, require := range selector.Requirements() {
-		switch require.Field {
-		case "name":
-			where, err = buildWhere(require, where)
-		case "email":
-			where, err = buildWhere(require, where)
-		}
-	}
-
+	db, err = selectLabels(db, (&v1.User{}).TableName(), opts.LabelSelector)
 	if err != nil {
 		return nil, err
 	}
 
-	d := u.db.Where(where).
-		Offset(ol.Offset).
-		Limit(ol.Limit).
-		Order("id desc").
-		Find(&ret.Items).
-		Offset(-1).
-		Limit(-1).
-		Count(&ret.TotalCount)
-
-	return ret, d.Error
-}
-
-func buildWhere(require fields.Requirement, where string) (string, error) {
-	if where != "" {
-		where += " and "
-	}
-
-	switch require.Operator {
-	case "==":
-		where += fmt.Sprintf("%s = '%v'", require.Field, require.Value)
-	case "=":
-		where += fmt.Sprintf("%s like '%%%v%%'", require.Field, require.Value)
-	case "!=":
-		where += fmt.Sprintf("%s %s '%v'", require.Field, require.Operator, require.Value)
-	default:
-		return "", fmt.Errorf("unknown operator '%s'", require.Operator)
+	if err := list(db, opts, store.UserFields, store.UserColumns, &ret.Items, &ret.ListMeta); err != nil {
+		return nil, err
 	}
 
-	return where, nil
+	return ret, nil
 }
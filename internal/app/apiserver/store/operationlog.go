@@ -2,6 +2,10 @@ package store
 
 import (
 	"context"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
 
 	metav1 "gobackend/pkg/meta/v1"
 
@@ -16,6 +20,12 @@ type OperationLogStore interface {
 		opts metav1.CreateOptions,
 	) error
 
+	CreateCollection(
+		ctx context.Context,
+		operationLogs []*operationlog.OperationLog,
+		opts metav1.CreateOptions,
+	) error
+
 	List(
 		ctx context.Context,
 		opts metav1.ListOptions,
@@ -26,4 +36,77 @@ type OperationLogStore interface {
 		id string,
 		opts metav1.DeleteOptions,
 	) error
+
+	DeleteCollection(
+		ctx context.Context,
+		ids []uint64,
+		opts metav1.DeleteOptions,
+	) error
+
+	// Aggregate groups the operation logs matching the field selector of the list options by
+	// the dimensions, see OperationLogDimensions, and returns the aggregates of the groups
+	// in no particular order. The groups are aggregated by the database, the latencies are
+	// counted in the buckets of LatencyBuckets.
+	Aggregate(
+		ctx context.Context,
+		opts metav1.ListOptions,
+		dimensions []string,
+	) ([]*operationlog.Aggregate, error)
+}
+
+// OperationLogDimensions maps the dimensions the operation logs can be aggregated by to the
+// fields they are derived from. The query strings are not a part of req_path, status_class
+// is e.g. `4xx`, day, e.g. `2006-01-02`, and hour, e.g. `2006-01-02T15:00Z`, are in UTC.
+var OperationLogDimensions = map[string]string{
+	"username":     "username",
+	"client_ip":    "client_ip",
+	"req_method":   "req_method",
+	"req_path":     "req_path",
+	"http_status":  "http_status",
+	"status_class": "http_status",
+	"day":          "req_time",
+	"hour":         "req_time",
+}
+
+// OperationLogDimension returns the value of the dimension of the operation log.
+func OperationLogDimension(dimension string, operationLog *operationlog.OperationLog) string {
+	switch dimension {
+	case "username":
+		return operationLog.Username
+	case "client_ip":
+		return operationLog.ClientIP
+	case "req_method":
+		return operationLog.ReqMethod
+	case "req_path":
+		return strings.SplitN(operationLog.ReqPath, "?", 2)[0]
+	case "http_status":
+		return strconv.Itoa(operationLog.HTTPStatus)
+	case "status_class":
+		return strconv.Itoa(operationLog.HTTPStatus/100) + "xx"
+	case "day":
+		return operationLog.ReqTime.UTC().Format("2006-01-02")
+	case "hour":
+		return operationLog.ReqTime.UTC().Format("2006-01-02T15:00Z")
+	default:
+		return ""
+	}
+}
+
+// LatencyBuckets are the upper bounds, in seconds, of the buckets of the latency histograms
+// of the aggregates. They grow by 25% from 1ms to about a minute, so a percentile read from
+// a histogram is at most 25% greater than the exact one. The last bucket of a histogram,
+// after these ones, counts the greater latencies.
+var LatencyBuckets = func() []float64 {
+	var buckets []float64
+	for bound := 0.001; bound < 60; bound *= 1.25 {
+		// Rounded so that the bounds are the same in SQL.
+		buckets = append(buckets, math.Round(bound*1e6)/1e6)
+	}
+
+	return buckets
+}()
+
+// LatencyBucket returns the index of the bucket of the latency in the histograms.
+func LatencyBucket(latency float64) int {
+	return sort.SearchFloat64s(LatencyBuckets, latency)
 }
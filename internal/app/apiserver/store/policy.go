@@ -0,0 +1,24 @@
+package store
+
+import (
+	"context"
+
+	metav1 "gobackend/pkg/meta/v1"
+
+	v1 "gobackend/internal/pkg/entity/apiserver/v1"
+)
+
+// PolicyStore defines the policy storage interface.
+// All the methods are scoped to the policies of the given username,
+// an empty username of List means policies of all users.
+type PolicyStore interface {
+	Create(ctx context.Context, policy *v1.Policy, opts metav1.CreateOptions) error
+	Update(ctx context.Context, policy *v1.Policy, opts metav1.UpdateOptions) error
+	Delete(ctx context.Context, username, name string, opts metav1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, username string, names []string, opts metav1.DeleteOptions) error
+	Get(ctx context.Context, username, name string, opts metav1.GetOptions) (*v1.Policy, error)
+	List(ctx context.Context, username string, opts metav1.ListOptions) (*v1.PolicyList, error)
+
+	// CountByUsers returns the number of policies owned by each of the given users.
+	CountByUsers(ctx context.Context, usernames []string) (map[string]int64, error)
+}
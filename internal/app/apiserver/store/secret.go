@@ -0,0 +1,21 @@
+package store
+
+import (
+	"context"
+
+	metav1 "gobackend/pkg/meta/v1"
+
+	v1 "gobackend/internal/pkg/entity/apiserver/v1"
+)
+
+// SecretStore defines the secret storage interface.
+// All the methods are scoped to the secrets of the given username,
+// an empty username of List means secrets of all users.
+type SecretStore interface {
+	Create(ctx context.Context, secret *v1.Secret, opts metav1.CreateOptions) error
+	Update(ctx context.Context, secret *v1.Secret, opts metav1.UpdateOptions) error
+	Delete(ctx context.Context, username, name string, opts metav1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, username string, names []string, opts metav1.DeleteOptions) error
+	Get(ctx context.Context, username, name string, opts metav1.GetOptions) (*v1.Secret, error)
+	List(ctx context.Context, username string, opts metav1.ListOptions) (*v1.SecretList, error)
+}
@@ -1,11 +1,24 @@
 package store
 
+import "context"
+
 var client Factory
 
 // Factory is the interface of store client.
 type Factory interface {
 	Users() UserStore
+	Secrets() SecretStore
+	Policies() PolicyStore
 	OperationLogs() OperationLogStore
+
+	// Tx runs fn in a transaction, all the stores got from the factory passed to fn
+	// share the transaction. The transaction is committed if fn returns nil,
+	// otherwise it is rolled back.
+	Tx(ctx context.Context, fn func(Factory) error) error
+
+	// Ping checks the connection to the database.
+	Ping(ctx context.Context) error
+
 	Close() error
 }
 
@@ -0,0 +1,121 @@
+// Package authorizer evaluates policies to decide whether an authorization request is allowed.
+package authorizer
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gobackend/pkg/selection"
+
+	v1 "gobackend/internal/pkg/entity/apiserver/v1"
+)
+
+// Authorize decides the request with the given policies.
+// A matched deny policy always wins, otherwise the request is allowed only
+// if at least one allow policy matches, requests matching no policy are denied.
+func Authorize(policies []*v1.Policy, req *v1.AuthzRequest) *v1.AuthzResponse {
+	var allowed *v1.Policy
+
+	for _, p := range policies {
+		if !Matches(p, req) {
+			continue
+		}
+
+		if p.Effect == v1.DenyAccess {
+			return &v1.AuthzResponse{
+				Allowed: false,
+				Policy:  p.InstanceID,
+				Reason:  fmt.Sprintf("denied by policy `%s`", p.Name),
+			}
+		}
+
+		if allowed == nil {
+			allowed = p
+		}
+	}
+
+	if allowed == nil {
+		return &v1.AuthzResponse{
+			Allowed: false,
+			Reason:  "no policy allows the request",
+		}
+	}
+
+	return &v1.AuthzResponse{
+		Allowed: true,
+		Policy:  allowed.InstanceID,
+		Reason:  fmt.Sprintf("allowed by policy `%s`", allowed.Name),
+	}
+}
+
+// Matches returns true if the subject, action, resource and context of the request
+// all match the policy, the effect of the policy is not considered.
+func Matches(p *v1.Policy, req *v1.AuthzRequest) bool {
+	if !matchAny(p.Subjects, req.Subject) ||
+		!matchAny(p.Actions, req.Action) ||
+		!matchAny(p.Resources, req.Resource) {
+		return false
+	}
+
+	for _, c := range p.Conditions {
+		if !matchCondition(c, req.Context) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func matchAny(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		if matchPattern(pattern, value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchPattern reports whether value matches the pattern, `*` in pattern matches any sequence of characters.
+func matchPattern(pattern, value string) bool {
+	if !strings.Contains(pattern, "*") {
+		return pattern == value
+	}
+
+	parts := strings.Split(pattern, "*")
+	for i := range parts {
+		parts[i] = regexp.QuoteMeta(parts[i])
+	}
+
+	matched, _ := regexp.MatchString("^"+strings.Join(parts, ".*")+"$", value)
+
+	return matched
+}
+
+func matchCondition(c v1.Condition, ctx map[string]string) bool {
+	value, exists := ctx[c.Key]
+
+	switch c.Operator {
+	case selection.Exists:
+		return exists
+	case selection.DoesNotExist:
+		return !exists
+	case selection.Equals, selection.DoubleEquals, selection.In:
+		return exists && contains(c.Values, value)
+	case selection.NotEquals, selection.NotIn:
+		return !exists || !contains(c.Values, value)
+	default:
+		return false
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
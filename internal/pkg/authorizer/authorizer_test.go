@@ -0,0 +1,129 @@
+package authorizer
+
+import (
+	"testing"
+
+	"gobackend/pkg/selection"
+
+	v1 "gobackend/internal/pkg/entity/apiserver/v1"
+)
+
+func TestAuthorize(t *testing.T) {
+	readUsers := &v1.Policy{
+		Effect:    v1.AllowAccess,
+		Subjects:  []string{"users:*"},
+		Actions:   []string{"get", "list"},
+		Resources: []string{"resources:users/*"},
+	}
+	readUsers.Name = "read-users"
+
+	denyAdmin := &v1.Policy{
+		Effect:    v1.DenyAccess,
+		Subjects:  []string{"*"},
+		Actions:   []string{"*"},
+		Resources: []string{"resources:users/admin"},
+	}
+	denyAdmin.Name = "deny-admin"
+
+	officeOnly := &v1.Policy{
+		Effect:    v1.AllowAccess,
+		Subjects:  []string{"users:bob"},
+		Actions:   []string{"delete"},
+		Resources: []string{"resources:users/*"},
+		Conditions: []v1.Condition{
+			{Key: "network", Operator: selection.In, Values: []string{"office", "vpn"}},
+			{Key: "suspended", Operator: selection.DoesNotExist},
+		},
+	}
+	officeOnly.Name = "office-only"
+
+	policies := []*v1.Policy{readUsers, denyAdmin, officeOnly}
+
+	tests := []struct {
+		name    string
+		req     *v1.AuthzRequest
+		allowed bool
+	}{
+		{
+			name:    "wildcard subject and resource",
+			req:     &v1.AuthzRequest{Subject: "users:alice", Action: "get", Resource: "resources:users/alice"},
+			allowed: true,
+		},
+		{
+			name:    "action not allowed",
+			req:     &v1.AuthzRequest{Subject: "users:alice", Action: "delete", Resource: "resources:users/alice"},
+			allowed: false,
+		},
+		{
+			name:    "deny overrides allow",
+			req:     &v1.AuthzRequest{Subject: "users:alice", Action: "get", Resource: "resources:users/admin"},
+			allowed: false,
+		},
+		{
+			name: "conditions match",
+			req: &v1.AuthzRequest{
+				Subject:  "users:bob",
+				Action:   "delete",
+				Resource: "resources:users/alice",
+				Context:  map[string]string{"network": "vpn"},
+			},
+			allowed: true,
+		},
+		{
+			name: "condition value not in set",
+			req: &v1.AuthzRequest{
+				Subject:  "users:bob",
+				Action:   "delete",
+				Resource: "resources:users/alice",
+				Context:  map[string]string{"network": "home"},
+			},
+			allowed: false,
+		},
+		{
+			name: "condition key must not exist",
+			req: &v1.AuthzRequest{
+				Subject:  "users:bob",
+				Action:   "delete",
+				Resource: "resources:users/alice",
+				Context:  map[string]string{"network": "office", "suspended": "true"},
+			},
+			allowed: false,
+		},
+		{
+			name:    "regexp meta characters are literal",
+			req:     &v1.AuthzRequest{Subject: "users:alice", Action: "get", Resource: "resources:users.alice"},
+			allowed: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Authorize(policies, tt.req); got.Allowed != tt.allowed {
+				t.Errorf("Authorize() = %+v, want allowed %v", got, tt.allowed)
+			}
+		})
+	}
+}
+
+func TestMatchPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		value   string
+		want    bool
+	}{
+		{"users", "users", true},
+		{"users", "users2", false},
+		{"*", "", true},
+		{"users:*", "users:alice", true},
+		{"*:alice", "users:alice", true},
+		{"users:*:read", "users:alice:read", true},
+		{"users:*:read", "users:alice:write", false},
+		{"a.c", "abc", false},
+	}
+
+	for _, tt := range tests {
+		if got := matchPattern(tt.pattern, tt.value); got != tt.want {
+			t.Errorf("matchPattern(%q, %q) = %v, want %v", tt.pattern, tt.value, got, tt.want)
+		}
+	}
+}
@@ -16,10 +16,16 @@ const (
 
 	//  ErrSecretNotFound - 404: Secret not found.
 	ErrSecretNotFound
+
+	// ErrSecretAlreadyExist - 400: Secret already exist.
+	ErrSecretAlreadyExist
 )
 
 // apiserver: policy errors.
 const (
 	// ErrPolicyNotFound - 404: Policy not found.
 	ErrPolicyNotFound int = iota + 110201
+
+	// ErrPolicyAlreadyExist - 400: Policy already exist.
+	ErrPolicyAlreadyExist
 )
@@ -26,6 +26,18 @@ const (
 
 	// ErrPageNotFound - 404: Page not found.
 	ErrPageNotFound
+
+	// ErrResourceConflict - 409: The resource has been modified, get the latest version and try again.
+	ErrResourceConflict
+
+	// ErrLabelSelectorValidation - 400: Label selector validation failed.
+	ErrLabelSelectorValidation
+
+	// ErrContinueValidation - 400: Continue token validation failed.
+	ErrContinueValidation
+
+	// ErrTooManyRequests - 429: Too many requests.
+	ErrTooManyRequests
 )
 
 // common: database errors.
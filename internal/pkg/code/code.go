@@ -53,9 +53,9 @@ func (coder ErrCode) HTTPStatus() int {
 
 // nolint: unparam,deadcode
 func register(code int, httpStatus int, message string, refs ...string) {
-	found, _ := gubrak.Includes([]int{200, 400, 401, 403, 404, 500}, httpStatus)
+	found, _ := gubrak.Includes([]int{200, 400, 401, 403, 404, 409, 429, 500}, httpStatus)
 	if !found {
-		panic("http code not in `200, 400, 401, 403, 404, 500`")
+		panic("http code not in `200, 400, 401, 403, 404, 409, 429, 500`")
 	}
 
 	var reference string
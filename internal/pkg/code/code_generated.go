@@ -8,7 +8,9 @@ func init() {
 	register(ErrUserAlreadyExist, 400, "User already exist")
 	register(ErrReachMaxCount, 400, "Secret reach the max count")
 	register(ErrSecretNotFound, 404, "Secret not found")
+	register(ErrSecretAlreadyExist, 400, "Secret already exist")
 	register(ErrPolicyNotFound, 404, "Policy not found")
+	register(ErrPolicyAlreadyExist, 400, "Policy already exist")
 	register(ErrSuccess, 200, "OK")
 	register(ErrUnknown, 500, "Internal server error")
 	register(ErrBind, 400, "Error occurred while binding the request body to the struct")
@@ -17,6 +19,10 @@ func init() {
 	register(ErrUpdateNone, 400, "Update nothing")
 	register(ErrTokenInvalid, 401, "Token invalid")
 	register(ErrPageNotFound, 404, "Page not found")
+	register(ErrResourceConflict, 409, "The resource has been modified, get the latest version and try again")
+	register(ErrLabelSelectorValidation, 400, "Label selector validation failed")
+	register(ErrContinueValidation, 400, "Continue token validation failed")
+	register(ErrTooManyRequests, 429, "Too many requests")
 	register(ErrDatabase, 500, "Database error")
 	register(ErrEncrypt, 401, "Error occurred while encrypting the user password")
 	register(ErrSignatureInvalid, 401, "Signature is invalid")
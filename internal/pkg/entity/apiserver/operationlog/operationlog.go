@@ -34,3 +34,61 @@ type List struct {
 func (u *OperationLog) TableName() string {
 	return "operation_log"
 }
+
+// Stats is the statistics of the operation logs grouped by the dimensions.
+type Stats struct {
+	// GroupBy are the dimensions the operation logs are grouped by.
+	GroupBy []string `json:"group_by"`
+
+	// TotalCount is the number of the operation logs in all the groups.
+	TotalCount int64 `json:"total_count"`
+
+	// Items are sorted by the number of the operation logs in descending order.
+	Items []*StatsItem `json:"items"`
+}
+
+// StatsItem is the statistics of a group of operation logs. An operation log with
+// an HTTP status of 400 or greater is counted as an error, the latencies are in seconds.
+type StatsItem struct {
+	Group      map[string]string `json:"group"`
+	Count      int64             `json:"count"`
+	ErrorCount int64             `json:"error_count"`
+	ErrorRate  float64           `json:"error_rate"`
+	AvgLatency float64           `json:"avg_latency"`
+	P95Latency float64           `json:"p95_latency"`
+	MaxLatency float64           `json:"max_latency"`
+}
+
+// Aggregate is the aggregate of a group of operation logs, the statistics are computed from it.
+type Aggregate struct {
+	// Group are the values of the dimensions of the group.
+	Group []string
+
+	Count      int64
+	ErrorCount int64
+	SumLatency float64
+	MaxLatency float64
+
+	// Latencies is the histogram of the latencies, an item is the number of the operation logs
+	// in a bucket, the upper bounds of the buckets are defined by the store.
+	Latencies []int64
+}
+
+// Merge adds the operation logs of other to the aggregate.
+func (a *Aggregate) Merge(other *Aggregate) {
+	if a.Count == 0 || other.MaxLatency > a.MaxLatency {
+		a.MaxLatency = other.MaxLatency
+	}
+
+	a.Count += other.Count
+	a.ErrorCount += other.ErrorCount
+	a.SumLatency += other.SumLatency
+
+	for len(a.Latencies) < len(other.Latencies) {
+		a.Latencies = append(a.Latencies, 0)
+	}
+
+	for i, n := range other.Latencies {
+		a.Latencies[i] += n
+	}
+}
@@ -0,0 +1,179 @@
+package v1
+
+import (
+	"encoding/json"
+
+	"gorm.io/gorm"
+
+	metav1 "gobackend/pkg/meta/v1"
+	"gobackend/pkg/selection"
+	"gobackend/pkg/util/idtool"
+)
+
+// Policy effects.
+const (
+	AllowAccess = "allow"
+	DenyAccess  = "deny"
+)
+
+// Policy represents a policy restful resource, it is also used as gorm model.
+// A policy grants or denies subjects to perform actions on resources when all the conditions match.
+// Subjects, actions and resources support `*` wildcard, e.g. `users:*`.
+type Policy struct {
+	// May add TypeMeta in the future.
+	// metav1.TypeMeta `json:",inline"`
+
+	// Standard object's metadata.
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Username is the owner of the policy, populated by the system.
+	Username string `json:"username" gorm:"index;column:username;type:varchar(64);not null" validate:"omitempty"`
+
+	Description string `json:"description" gorm:"column:description" validate:"description"`
+
+	// Required: true
+	Effect string `json:"effect" gorm:"column:effect;type:varchar(8);not null" validate:"required,oneof=allow deny"`
+
+	// Required: true
+	Subjects []string `json:"subjects" gorm:"-" validate:"required,min=1,dive,required"`
+
+	// Required: true
+	Actions []string `json:"actions" gorm:"-" validate:"required,min=1,dive,required"`
+
+	// Required: true
+	Resources []string `json:"resources" gorm:"-" validate:"required,min=1,dive,required"`
+
+	Conditions []Condition `json:"conditions,omitempty" gorm:"-" validate:"omitempty,dive"`
+
+	// PolicyShadow is the shadow of Subjects, Actions, Resources and Conditions. DO NOT modify directly.
+	PolicyShadow string `json:"-" gorm:"column:policy_shadow;type:text" validate:"omitempty"`
+}
+
+// Condition restricts a policy to the requests whose context value of Key
+// satisfies the Operator with Values.
+type Condition struct {
+	Key string `json:"key" validate:"required"`
+
+	// Operator is one of: =, !=, in, notin, exists, !.
+	Operator selection.Operator `json:"operator" validate:"required,oneof== != in notin exists !"`
+
+	Values []string `json:"values,omitempty" validate:"omitempty"`
+}
+
+// PolicyList is the whole list of all policies which have been stored in stroage.
+type PolicyList struct {
+	// May add TypeMeta in the future.
+	// metav1.TypeMeta `json:",inline"`
+
+	// Standard list metadata.
+	// +optional
+	metav1.ListMeta `json:",inline"`
+
+	Items []*Policy `json:"items"`
+}
+
+// AuthzRequest asks whether the subject can perform the action on the resource.
+type AuthzRequest struct {
+	// Required: true
+	Subject string `json:"subject" validate:"required"`
+
+	// Required: true
+	Action string `json:"action" validate:"required"`
+
+	// Required: true
+	Resource string `json:"resource" validate:"required"`
+
+	// Context is matched against the conditions of policies.
+	Context map[string]string `json:"context,omitempty"`
+}
+
+// AuthzResponse is the decision of an AuthzRequest.
+type AuthzResponse struct {
+	Allowed bool `json:"allowed"`
+
+	// Policy is the instance id of the policy which decides the result,
+	// it is empty if no policy matches.
+	Policy string `json:"policy,omitempty"`
+
+	Reason string `json:"reason,omitempty"`
+}
+
+type policyStatement struct {
+	Subjects   []string    `json:"subjects"`
+	Actions    []string    `json:"actions"`
+	Resources  []string    `json:"resources"`
+	Conditions []Condition `json:"conditions,omitempty"`
+}
+
+// TableName maps to mysql table name.
+func (p *Policy) TableName() string {
+	return "policy"
+}
+
+// BeforeCreate run before create database record.
+func (p *Policy) BeforeCreate(tx *gorm.DB) (err error) {
+	p.ResourceVersion = 1
+
+	return p.marshalShadow()
+}
+
+// AfterCreate run after create database record.
+func (p *Policy) AfterCreate(tx *gorm.DB) (err error) {
+	p.InstanceID = idtool.GetInstanceID(p.ID, "policy-")
+
+	return tx.Save(p).Error
+}
+
+// BeforeUpdate run before update database record.
+func (p *Policy) BeforeUpdate(tx *gorm.DB) (err error) {
+	return p.marshalShadow()
+}
+
+// AfterFind run after find to unmarshal the shadow strings.
+func (p *Policy) AfterFind(tx *gorm.DB) (err error) {
+	// The shadows are empty if the columns are not selected, see ListOptions.Fields.
+	if p.ExtendShadow != "" {
+		if err := json.Unmarshal([]byte(p.ExtendShadow), &p.Extend); err != nil {
+			return err
+		}
+	}
+
+	if err := p.UnmarshalLabelsShadow(); err != nil {
+		return err
+	}
+
+	if p.PolicyShadow == "" {
+		return nil
+	}
+
+	var statement policyStatement
+	if err := json.Unmarshal([]byte(p.PolicyShadow), &statement); err != nil {
+		return err
+	}
+
+	p.Subjects = statement.Subjects
+	p.Actions = statement.Actions
+	p.Resources = statement.Resources
+	p.Conditions = statement.Conditions
+
+	return nil
+}
+
+func (p *Policy) marshalShadow() error {
+	p.ExtendShadow = p.Extend.String()
+	p.MarshalLabelsShadow()
+
+	data, err := json.Marshal(policyStatement{
+		Subjects:   p.Subjects,
+		Actions:    p.Actions,
+		Resources:  p.Resources,
+		Conditions: p.Conditions,
+	})
+	if err != nil {
+		return err
+	}
+
+	p.PolicyShadow = string(data)
+
+	return nil
+}
@@ -0,0 +1,87 @@
+package v1
+
+import (
+	"encoding/json"
+
+	"gorm.io/gorm"
+
+	metav1 "gobackend/pkg/meta/v1"
+	"gobackend/pkg/util/idtool"
+)
+
+// Secret represents a secret restful resource, a secret is an API key pair owned by a user.
+// It is also used as gorm model.
+type Secret struct {
+	// May add TypeMeta in the future.
+	// metav1.TypeMeta `json:",inline"`
+
+	// Standard object's metadata.
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Username is the owner of the secret, populated by the system.
+	Username string `json:"username" gorm:"index;column:username;type:varchar(64);not null" validate:"omitempty"`
+
+	// SecretID is the `kid` of the tokens signed by the secret, populated by the system.
+	SecretID string `json:"secret_id" gorm:"unique;column:secret_id;type:varchar(64);not null" validate:"omitempty"`
+
+	// SecretKey is used to sign the tokens, populated by the system.
+	SecretKey string `json:"secret_key" gorm:"column:secret_key;type:varchar(255);not null" validate:"omitempty"`
+
+	// Expires is the unix timestamp when the secret expires, 0 means never expire.
+	Expires int64 `json:"expires" gorm:"column:expires" validate:"omitempty"`
+
+	Description string `json:"description" gorm:"column:description" validate:"description"`
+}
+
+// SecretList is the whole list of all secrets which have been stored in stroage.
+type SecretList struct {
+	// May add TypeMeta in the future.
+	// metav1.TypeMeta `json:",inline"`
+
+	// Standard list metadata.
+	// +optional
+	metav1.ListMeta `json:",inline"`
+
+	Items []*Secret `json:"items"`
+}
+
+// TableName maps to mysql table name.
+func (s *Secret) TableName() string {
+	return "secret"
+}
+
+// BeforeCreate run before create database record.
+func (s *Secret) BeforeCreate(tx *gorm.DB) (err error) {
+	s.ResourceVersion = 1
+	s.ExtendShadow = s.Extend.String()
+	s.MarshalLabelsShadow()
+
+	return
+}
+
+// AfterCreate run after create database record.
+func (s *Secret) AfterCreate(tx *gorm.DB) (err error) {
+	s.InstanceID = idtool.GetInstanceID(s.ID, "secret-")
+
+	return tx.Save(s).Error
+}
+
+// BeforeUpdate run before update database record.
+func (s *Secret) BeforeUpdate(tx *gorm.DB) (err error) {
+	s.ExtendShadow = s.Extend.String()
+	s.MarshalLabelsShadow()
+
+	return
+}
+
+// AfterFind run after find to unmarshal a extend shadown string into metav1.Extend struct.
+func (s *Secret) AfterFind(tx *gorm.DB) (err error) {
+	// The shadows are empty if the columns are not selected, see ListOptions.Fields.
+	if s.ExtendShadow != "" {
+		if err := json.Unmarshal([]byte(s.ExtendShadow), &s.Extend); err != nil {
+			return err
+		}
+	}
+
+	return s.UnmarshalLabelsShadow()
+}
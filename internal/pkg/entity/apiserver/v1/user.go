@@ -51,6 +51,11 @@ func (u *User) TableName() string {
 	return "user"
 }
 
+// IsAdministrator returns true if the user has the administrator role.
+func (u *User) IsAdministrator() bool {
+	return u.IsAdmin == 1
+}
+
 // Compare with the plain text password. Returns true if it's the same as the encrypted one (in the `User` struct).
 func (u *User) Compare(pwd string) (err error) {
 	err = authtool.Compare(u.Password, pwd)
@@ -60,7 +65,12 @@ func (u *User) Compare(pwd string) (err error) {
 
 // BeforeCreate run before create database record.
 func (u *User) BeforeCreate(tx *gorm.DB) (err error) {
-	return
+	u.ResourceVersion = 1
+	u.Password, err = authtool.Encrypt(u.Password)
+	u.ExtendShadow = u.Extend.String()
+	u.MarshalLabelsShadow()
+
+	return err
 }
 
 // AfterCreate run after create database record.
@@ -72,18 +82,22 @@ func (u *User) AfterCreate(tx *gorm.DB) (err error) {
 }
 
 // BeforeUpdate run before update database record.
+// The password has been encrypted when the record is created, it must not be encrypted again.
 func (u *User) BeforeUpdate(tx *gorm.DB) (err error) {
-	u.Password, err = authtool.Encrypt(u.Password)
 	u.ExtendShadow = u.Extend.String()
+	u.MarshalLabelsShadow()
 
-	return err
+	return
 }
 
 // AfterFind run after find to unmarshal a extend shadown string into metav1.Extend struct.
 func (u *User) AfterFind(tx *gorm.DB) (err error) {
-	if err := json.Unmarshal([]byte(u.ExtendShadow), &u.Extend); err != nil {
-		return err
+	// The shadows are empty if the columns are not selected, see ListOptions.Fields.
+	if u.ExtendShadow != "" {
+		if err := json.Unmarshal([]byte(u.ExtendShadow), &u.Extend); err != nil {
+			return err
+		}
 	}
 
-	return nil
+	return u.UnmarshalLabelsShadow()
 }
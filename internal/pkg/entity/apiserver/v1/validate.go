@@ -14,6 +14,8 @@ func (u *User) Validate() field.ErrorList {
 		allErrs = append(allErrs, field.Invalid(field.NewPath("password"), err.Error(), ""))
 	}
 
+	allErrs = append(allErrs, validateLabels(u.Labels)...)
+
 	return allErrs
 }
 
@@ -22,6 +24,42 @@ func (u *User) Validate() field.ErrorList {
 func (u *User) ValidateUpdate() field.ErrorList {
 	val := validation.NewValidator(u)
 	allErrs := val.Validate()
+	allErrs = append(allErrs, validateLabels(u.Labels)...)
+
+	return allErrs
+}
+
+// Validate validates that a secret object is valid.
+func (s *Secret) Validate() field.ErrorList {
+	val := validation.NewValidator(s)
+	allErrs := val.Validate()
+
+	if s.Expires < 0 {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("expires"), s.Expires, "must be greater than or equal to 0"))
+	}
+
+	allErrs = append(allErrs, validateLabels(s.Labels)...)
 
 	return allErrs
 }
+
+// Validate validates that a policy object is valid.
+func (p *Policy) Validate() field.ErrorList {
+	val := validation.NewValidator(p)
+	allErrs := val.Validate()
+	allErrs = append(allErrs, validateLabels(p.Labels)...)
+
+	return allErrs
+}
+
+// Validate validates that an authorization request is valid.
+func (r *AuthzRequest) Validate() field.ErrorList {
+	val := validation.NewValidator(r)
+
+	return val.Validate()
+}
+
+// validateLabels validates the labels in the object metadata.
+func validateLabels(labels map[string]string) field.ErrorList {
+	return validation.ValidateLabels(labels, field.NewPath("metadata", "labels"))
+}
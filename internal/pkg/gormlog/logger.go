@@ -46,6 +46,10 @@ var LogLevelMap = map[string]int{
 	"info":   4,
 }
 
+// RequestIDKey is the context key of the request id, it is the same as the one set by the
+// request id middleware. Trace lines are tagged with it when the context has one.
+const RequestIDKey = "X-Request-ID"
+
 // Writer log writer interface.
 type Writer interface {
 	Printf(string, ...interface{})
@@ -139,28 +143,34 @@ func (l logger) Trace(ctx context.Context, begin time.Time, fc func() (string, i
 	}
 
 	elapsed := time.Since(begin)
+	caller := fileWithLineNum()
+
+	if rid, _ := ctx.Value(RequestIDKey).(string); rid != "" {
+		caller += " x-request-id=" + rid
+	}
+
 	switch {
 	case err != nil && l.LogLevel >= Error:
 		sql, rows := fc()
 		if rows == -1 {
-			l.Printf(l.traceErrStr, fileWithLineNum(), err, float64(elapsed.Nanoseconds())/1e6, "-", sql)
+			l.Printf(l.traceErrStr, caller, err, float64(elapsed.Nanoseconds())/1e6, "-", sql)
 		} else {
-			l.Printf(l.traceErrStr, fileWithLineNum(), err, float64(elapsed.Nanoseconds())/1e6, rows, sql)
+			l.Printf(l.traceErrStr, caller, err, float64(elapsed.Nanoseconds())/1e6, rows, sql)
 		}
 	case elapsed > l.SlowThreshold && l.SlowThreshold != 0 && l.LogLevel >= Warn:
 		sql, rows := fc()
 		slowLog := fmt.Sprintf("SLOW SQL >= %v", l.SlowThreshold)
 		if rows == -1 {
-			l.Printf(l.traceWarnStr, fileWithLineNum(), slowLog, float64(elapsed.Nanoseconds())/1e6, "-", sql)
+			l.Printf(l.traceWarnStr, caller, slowLog, float64(elapsed.Nanoseconds())/1e6, "-", sql)
 		} else {
-			l.Printf(l.traceWarnStr, fileWithLineNum(), slowLog, float64(elapsed.Nanoseconds())/1e6, rows, sql)
+			l.Printf(l.traceWarnStr, caller, slowLog, float64(elapsed.Nanoseconds())/1e6, rows, sql)
 		}
 	case l.LogLevel >= Info:
 		sql, rows := fc()
 		if rows == -1 {
-			l.Printf(l.traceStr, fileWithLineNum(), float64(elapsed.Nanoseconds())/1e6, "-", sql)
+			l.Printf(l.traceStr, caller, float64(elapsed.Nanoseconds())/1e6, "-", sql)
 		} else {
-			l.Printf(l.traceStr, fileWithLineNum(), float64(elapsed.Nanoseconds())/1e6, rows, sql)
+			l.Printf(l.traceStr, caller, float64(elapsed.Nanoseconds())/1e6, rows, sql)
 		}
 	}
 }
@@ -1,8 +1,10 @@
 package auth
 
 import (
+	"context"
 	"strings"
 
+	jwt "github.com/dgrijalva/jwt-go/v4"
 	"github.com/gin-gonic/gin"
 
 	"gobackend/pkg/core"
@@ -19,9 +21,10 @@ const authHeaderCount = 2
 type AutoStrategy struct {
 	basic BasicStrategy
 	jwt   JWTStrategy
+	cache *CacheStrategy
 }
 
-var _ middleware.AuthStrategy = &AutoStrategy{}
+var _ Strategy = &AutoStrategy{}
 
 // NewAutoStrategy create auto strategy with basic strategy and jwt strategy.
 func NewAutoStrategy(basic BasicStrategy, jwt JWTStrategy) AutoStrategy {
@@ -31,6 +34,14 @@ func NewAutoStrategy(basic BasicStrategy, jwt JWTStrategy) AutoStrategy {
 	}
 }
 
+// WithCache returns a copy of the auto strategy which verifies the bearer tokens
+// carrying a `kid` header, e.g. tokens signed by secrets, with cache strategy.
+func (a AutoStrategy) WithCache(cache CacheStrategy) AutoStrategy {
+	a.cache = &cache
+
+	return a
+}
+
 // AuthFunc defines auto strategy as the gin authentication middleware.
 func (a AutoStrategy) AuthFunc() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -52,6 +63,12 @@ func (a AutoStrategy) AuthFunc() gin.HandlerFunc {
 		case "Basic":
 			operator.SetStrategy(a.basic)
 		case "Bearer":
+			if a.cache != nil && hasKID(authHeader[1]) {
+				operator.SetStrategy(a.cache)
+
+				break
+			}
+
 			operator.SetStrategy(a.jwt)
 			// a.JWT.MiddlewareFunc()(c)
 		default:
@@ -66,3 +83,38 @@ func (a AutoStrategy) AuthFunc() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// Verify verifies the Authorization header with the strategy selected by its scheme and
+// returns the username.
+func (a AutoStrategy) Verify(ctx context.Context, header string) (string, error) {
+	authHeader := strings.SplitN(header, " ", 2)
+
+	if len(authHeader) != authHeaderCount {
+		return "", errors.WithCode(code.ErrInvalidAuthHeader, "Authorization header format is wrong.")
+	}
+
+	switch authHeader[0] {
+	case "Basic":
+		return a.basic.Verify(ctx, header)
+	case "Bearer":
+		if a.cache != nil && hasKID(authHeader[1]) {
+			return a.cache.Verify(ctx, header)
+		}
+
+		return a.jwt.Verify(ctx, header)
+	default:
+		return "", errors.WithCode(code.ErrSignatureInvalid, "unrecognized Authorization header.")
+	}
+}
+
+// hasKID reports whether the token header contains the `kid` field, the token is not verified here.
+func hasKID(rawJWT string) bool {
+	token, _, err := jwt.NewParser().ParseUnverified(rawJWT, jwt.MapClaims{})
+	if err != nil {
+		return false
+	}
+
+	_, ok := token.Header["kid"]
+
+	return ok
+}
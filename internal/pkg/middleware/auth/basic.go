@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"context"
 	"encoding/base64"
 	"strings"
 
@@ -15,13 +16,13 @@ import (
 
 // BasicStrategy defines Basic authentication strategy.
 type BasicStrategy struct {
-	compare func(username string, password string) bool
+	compare func(ctx context.Context, username string, password string) bool
 }
 
-var _ middleware.AuthStrategy = &BasicStrategy{}
+var _ Strategy = &BasicStrategy{}
 
-// NewBasicStrategy create basic strategy with compare function.
-func NewBasicStrategy(compare func(username string, password string) bool) BasicStrategy {
+// NewBasicStrategy create basic strategy with compare function, ctx passed to it is the context of the request.
+func NewBasicStrategy(compare func(ctx context.Context, username string, password string) bool) BasicStrategy {
 	return BasicStrategy{
 		compare: compare,
 	}
@@ -30,35 +31,34 @@ func NewBasicStrategy(compare func(username string, password string) bool) Basic
 // AuthFunc defines basic strategy as the gin authentication middleware.
 func (b BasicStrategy) AuthFunc() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		auth := strings.SplitN(c.Request.Header.Get("Authorization"), " ", 2)
-
-		if len(auth) != 2 || auth[0] != "Basic" {
-			core.WriteResponse(
-				c,
-				errors.WithCode(code.ErrSignatureInvalid, "Authorization header format is wrong."),
-				nil,
-			)
+		username, err := b.Verify(middleware.RequestContext(c), c.Request.Header.Get("Authorization"))
+		if err != nil {
+			core.WriteResponse(c, err, nil)
 			c.Abort()
 
 			return
 		}
 
-		payload, _ := base64.StdEncoding.DecodeString(auth[1])
-		pair := strings.SplitN(string(payload), ":", 2)
+		c.Set(middleware.UsernameKey, username)
 
-		if len(pair) != 2 || !b.compare(pair[0], pair[1]) {
-			core.WriteResponse(
-				c,
-				errors.WithCode(code.ErrSignatureInvalid, "Authorization header format is wrong."),
-				nil,
-			)
-			c.Abort()
+		c.Next()
+	}
+}
 
-			return
-		}
+// Verify verifies the basic credentials in the Authorization header and returns the username.
+func (b BasicStrategy) Verify(ctx context.Context, header string) (string, error) {
+	auth := strings.SplitN(header, " ", 2)
 
-		c.Set(middleware.UsernameKey, pair[0])
+	if len(auth) != 2 || auth[0] != "Basic" {
+		return "", errors.WithCode(code.ErrSignatureInvalid, "Authorization header format is wrong.")
+	}
 
-		c.Next()
+	payload, _ := base64.StdEncoding.DecodeString(auth[1])
+	pair := strings.SplitN(string(payload), ":", 2)
+
+	if len(pair) != 2 || !b.compare(ctx, pair[0], pair[1]) {
+		return "", errors.WithCode(code.ErrSignatureInvalid, "Authorization header format is wrong.")
 	}
+
+	return pair[0], nil
 }
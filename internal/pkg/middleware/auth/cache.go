@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -31,73 +32,90 @@ type Secret struct {
 // CacheStrategy defines jwt bearer authentication strategy which called `cache strategy`.
 // Secrets are obtained through grpc api interface and cached in memory.
 type CacheStrategy struct {
-	get func(kid string) (Secret, error)
+	get func(ctx context.Context, kid string) (Secret, error)
 }
 
-var _ middleware.AuthStrategy = &CacheStrategy{}
+var _ Strategy = &CacheStrategy{}
 
-// NewCacheStrategy create cache strategy with function which can list and cache secrets.
-func NewCacheStrategy(get func(kid string) (Secret, error)) CacheStrategy {
+// NewCacheStrategy create cache strategy with function which can list and cache secrets,
+// ctx passed to it is the context of the request.
+func NewCacheStrategy(get func(ctx context.Context, kid string) (Secret, error)) CacheStrategy {
 	return CacheStrategy{get}
 }
 
 // AuthFunc defines cache strategy as the gin authentication middleware.
 func (cache CacheStrategy) AuthFunc() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		header := c.Request.Header.Get("Authorization")
-		if len(header) == 0 {
-			core.WriteResponse(c, errors.WithCode(code.ErrMissingHeader, "Authorization header cannot be empty."), nil)
+		secret, err := cache.verify(middleware.RequestContext(c), c.Request.Header.Get("Authorization"))
+		if err != nil {
+			core.WriteResponse(c, err, nil)
 			c.Abort()
 
 			return
 		}
 
-		var rawJWT string
-		// Parse the header to get the token part.
-		fmt.Sscanf(header, "Bearer %s", &rawJWT)
-
-		// Use own validation logic, see below
-		var secret Secret
-
-		claims := &jwt.MapClaims{}
-		// Verify the token
-		parsedT, err := jwt.ParseWithClaims(rawJWT, claims, func(token *jwt.Token) (interface{}, error) {
-			// Validate the alg is HMAC signature
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-			}
-
-			kid, ok := token.Header["kid"].(string)
-			if !ok {
-				return nil, ErrMissingKID
-			}
-
-			var err error
-			secret, err = cache.get(kid)
-			if err != nil {
-				return nil, ErrMissingSecret
-			}
-
-			return []byte(secret.Key), nil
-		}, jwt.WithAudience(AuthzAudience))
-		if err != nil || !parsedT.Valid {
-			core.WriteResponse(c, errors.WithCode(code.ErrSignatureInvalid, err.Error()), nil)
-			c.Abort()
+		c.Set(middleware.UsernameKey, secret.Username)
+		c.Set(middleware.SecretIDKey, secret.ID)
+		c.Next()
+	}
+}
 
-			return
+// Verify verifies the bearer token signed by a secret in the Authorization header and returns
+// the username of the secret.
+func (cache CacheStrategy) Verify(ctx context.Context, header string) (string, error) {
+	secret, err := cache.verify(ctx, header)
+	if err != nil {
+		return "", err
+	}
+
+	return secret.Username, nil
+}
+
+// verify verifies the bearer token in the Authorization header and returns the secret it is signed by.
+func (cache CacheStrategy) verify(ctx context.Context, header string) (Secret, error) {
+	if len(header) == 0 {
+		return Secret{}, errors.WithCode(code.ErrMissingHeader, "Authorization header cannot be empty.")
+	}
+
+	var rawJWT string
+	// Parse the header to get the token part.
+	fmt.Sscanf(header, "Bearer %s", &rawJWT)
+
+	// Use own validation logic, see below
+	var secret Secret
+
+	claims := &jwt.MapClaims{}
+	// Verify the token
+	parsedT, err := jwt.ParseWithClaims(rawJWT, claims, func(token *jwt.Token) (interface{}, error) {
+		// Validate the alg is HMAC signature
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
 
-		if KeyExpired(secret.Expires) {
-			tm := time.Unix(secret.Expires, 0).Format("2006-01-02 15:04:05")
-			core.WriteResponse(c, errors.WithCode(code.ErrExpired, "expired at: %s", tm), nil)
-			c.Abort()
+		kid, ok := token.Header["kid"].(string)
+		if !ok {
+			return nil, ErrMissingKID
+		}
 
-			return
+		var err error
+		secret, err = cache.get(ctx, kid)
+		if err != nil {
+			return nil, ErrMissingSecret
 		}
 
-		c.Set(middleware.UsernameKey, secret.Username)
-		c.Next()
+		return []byte(secret.Key), nil
+	}, jwt.WithAudience(AuthzAudience))
+	if err != nil || !parsedT.Valid {
+		return Secret{}, errors.WithCode(code.ErrSignatureInvalid, err.Error())
 	}
+
+	if KeyExpired(secret.Expires) {
+		tm := time.Unix(secret.Expires, 0).Format("2006-01-02 15:04:05")
+
+		return Secret{}, errors.WithCode(code.ErrExpired, "expired at: %s", tm)
+	}
+
+	return secret, nil
 }
 
 // KeyExpired checks if a key has expired, if the value of user.SessionState.Expires is 0, it will be ignored.
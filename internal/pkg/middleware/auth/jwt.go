@@ -1,10 +1,16 @@
 package auth
 
 import (
+	"context"
+	"strings"
+
 	ginjwt "github.com/appleboy/gin-jwt/v2"
+	jwtv3 "github.com/dgrijalva/jwt-go"
 	"github.com/gin-gonic/gin"
 
-	"gobackend/internal/pkg/middleware"
+	"gobackend/pkg/errors"
+
+	"gobackend/internal/pkg/code"
 )
 
 // AuthzAudience defines the value of jwt audience field.
@@ -15,7 +21,7 @@ type JWTStrategy struct {
 	ginjwt.GinJWTMiddleware
 }
 
-var _ middleware.AuthStrategy = &JWTStrategy{}
+var _ Strategy = &JWTStrategy{}
 
 // NewJWTStrategy create jwt bearer strategy with GinJWTMiddleware.
 func NewJWTStrategy(gjwt ginjwt.GinJWTMiddleware) JWTStrategy {
@@ -26,3 +32,39 @@ func NewJWTStrategy(gjwt ginjwt.GinJWTMiddleware) JWTStrategy {
 func (j JWTStrategy) AuthFunc() gin.HandlerFunc {
 	return j.MiddlewareFunc()
 }
+
+// Verify verifies the bearer token in the Authorization header as MiddlewareFunc does,
+// and returns the identity in the token.
+func (j JWTStrategy) Verify(ctx context.Context, header string) (string, error) {
+	if header == "" {
+		return "", errors.WithCode(code.ErrMissingHeader, "Authorization header cannot be empty.")
+	}
+
+	parts := strings.SplitN(header, " ", 2)
+	if len(parts) != 2 || parts[0] != j.TokenHeadName {
+		return "", errors.WithCode(code.ErrInvalidAuthHeader, "Authorization header format is wrong.")
+	}
+
+	token, err := j.ParseTokenString(parts[1])
+	if err != nil {
+		var ve *jwtv3.ValidationError
+		if errors.As(err, &ve) && ve.Errors&jwtv3.ValidationErrorExpired != 0 {
+			return "", errors.WithCode(code.ErrExpired, err.Error())
+		}
+
+		return "", errors.WithCode(code.ErrTokenInvalid, err.Error())
+	}
+
+	claims := ginjwt.ExtractClaimsFromToken(token)
+	if _, ok := claims["exp"]; !ok {
+		return "", errors.WithCode(code.ErrTokenInvalid, ginjwt.ErrMissingExpField.Error())
+	}
+
+	// The identity is in the claim set by PayloadFunc as IdentityHandler reads it.
+	username, _ := claims[ginjwt.IdentityKey].(string)
+	if username == "" {
+		return "", errors.WithCode(code.ErrTokenInvalid, "missing identity in token")
+	}
+
+	return username, nil
+}
@@ -0,0 +1,17 @@
+package auth
+
+import (
+	"context"
+
+	"gobackend/internal/pkg/middleware"
+)
+
+// Strategy is an authentication strategy which also verifies the credentials out of gin,
+// e.g. the `authorization` metadata of the gRPC calls.
+type Strategy interface {
+	middleware.AuthStrategy
+
+	// Verify verifies the value of the Authorization header and returns the username,
+	// ctx is the context of the request the header comes from.
+	Verify(ctx context.Context, header string) (string, error)
+}
@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"gobackend/pkg/core"
+	"gobackend/pkg/errors"
+	metav1 "gobackend/pkg/meta/v1"
+
+	"gobackend/internal/app/apiserver/store"
+	"gobackend/internal/pkg/code"
+	v1 "gobackend/internal/pkg/entity/apiserver/v1"
+)
+
+// UserKey defines the key in gin context which represents the authenticated user object.
+const UserKey = "user"
+
+// AuthzPolicy decides whether a non-administrator user is allowed to access the route.
+type AuthzPolicy func(c *gin.Context, user *v1.User) bool
+
+// AllowSelf allows the user whose name equals to the value of the route parameter.
+func AllowSelf(param string) AuthzPolicy {
+	return func(c *gin.Context, user *v1.User) bool {
+		return c.Param(param) == user.Name
+	}
+}
+
+// AllowAll allows every authenticated user.
+func AllowAll() AuthzPolicy {
+	return func(c *gin.Context, user *v1.User) bool {
+		return true
+	}
+}
+
+// Authz is a middleware that authorizes the authenticated user to access the route.
+// Administrators can access any route, other users are allowed only if one of the policies passes.
+// Without policies, the route is only accessible for administrators.
+// NOTE: Must be placed after the authentication middleware.
+func Authz(storeIns store.Factory, policies ...AuthzPolicy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		username := c.GetString(UsernameKey)
+		if username == "" {
+			core.WriteResponse(c, errors.WithCode(code.ErrPermissionDenied, "anonymous user is not allowed"), nil)
+			c.Abort()
+
+			return
+		}
+
+		user, err := storeIns.Users().Get(RequestContext(c), username, metav1.GetOptions{})
+		if err != nil {
+			core.WriteResponse(c, err, nil)
+			c.Abort()
+
+			return
+		}
+
+		c.Set(UserKey, user)
+
+		if user.IsAdministrator() {
+			c.Next()
+
+			return
+		}
+
+		for _, policy := range policies {
+			if policy(c, user) {
+				c.Next()
+
+				return
+			}
+		}
+
+		core.WriteResponse(
+			c,
+			errors.WithCode(code.ErrPermissionDenied, "user `%s` is not allowed to %s %s", username, c.Request.Method, c.FullPath()),
+			nil,
+		)
+		c.Abort()
+	}
+}
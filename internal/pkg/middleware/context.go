@@ -1,6 +1,8 @@
 package middleware
 
 import (
+	"context"
+
 	"github.com/gin-gonic/gin"
 
 	"gobackend/pkg/log"
@@ -9,6 +11,10 @@ import (
 // UsernameKey defines the key in gin context which represents the owner of the secret.
 const UsernameKey = "username"
 
+// SecretIDKey defines the key in gin context which represents the secret id (the API key)
+// the request is signed with, it is only set for the requests authenticated by secrets.
+const SecretIDKey = "secretID"
+
 // Context is a middleware that injects common prefix fields to gin.Context.
 func Context() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -22,3 +28,28 @@ func Context() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// requestContext carries the deadline, cancellation and values of the request context,
+// and also the values set in gin.Context.
+type requestContext struct {
+	context.Context
+	c *gin.Context
+}
+
+// RequestContext returns a context.Context which is canceled when the client goes away
+// or the request times out. gin.Context itself never gets canceled, so it should not
+// be passed to the functions which wait on the context, e.g. database queries.
+func RequestContext(c *gin.Context) context.Context {
+	return &requestContext{Context: c.Request.Context(), c: c}
+}
+
+// Value returns the value set in gin.Context first, then the value of the request context.
+func (r *requestContext) Value(key interface{}) interface{} {
+	if k, ok := key.(string); ok {
+		if v, exists := r.c.Get(k); exists {
+			return v
+		}
+	}
+
+	return r.Context.Value(key)
+}
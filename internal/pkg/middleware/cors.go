@@ -1,6 +1,9 @@
 package middleware
 
 import (
+	"fmt"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/gin-contrib/cors"
@@ -9,15 +12,57 @@ import (
 
 const maxAge = 12
 
-// Cors add cors headers.
-func Cors() gin.HandlerFunc {
-	return cors.New(cors.Config{
+// corsHandler holds the gin.HandlerFunc applying the current cors configuration.
+var corsHandler atomic.Value
+
+//nolint:gochecknoinits
+func init() {
+	corsHandler.Store(cors.New(DefaultCorsConfig()))
+}
+
+// DefaultCorsConfig returns the default cors configuration, all the origins are allowed.
+func DefaultCorsConfig() cors.Config {
+	return cors.Config{
 		AllowOrigins:     []string{"*"},
 		AllowMethods:     []string{"PUT", "PATCH", "GET", "POST", "OPTIONS", "DELETE", "HEAD"},
-		AllowHeaders:     []string{"Origin", "Authorization", "Content-Type", "Accept"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowHeaders:     []string{"Origin", "Authorization", "Content-Type", "Accept", "If-Match", "If-None-Match"},
+		ExposeHeaders:    []string{"Content-Length", "ETag"},
 		AllowCredentials: true,
 		AllowWildcard:    true,
 		MaxAge:           maxAge * time.Hour,
-	})
+	}
+}
+
+// ValidateCorsConfig checks the cors configuration, cors.New panics on some invalid ones.
+func ValidateCorsConfig(config cors.Config) error {
+	if err := config.Validate(); err != nil {
+		return err
+	}
+
+	for _, origin := range config.AllowOrigins {
+		if strings.Count(origin, "*") > 1 {
+			return fmt.Errorf("bad origin %s: only one * is allowed", origin)
+		}
+	}
+
+	return nil
+}
+
+// SetCorsConfig replaces the configuration of the cors middleware, it is safe to be called
+// while serving.
+func SetCorsConfig(config cors.Config) error {
+	if err := ValidateCorsConfig(config); err != nil {
+		return err
+	}
+
+	corsHandler.Store(cors.New(config))
+
+	return nil
+}
+
+// Cors add cors headers, the headers follow the configuration set by SetCorsConfig.
+func Cors() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		corsHandler.Load().(gin.HandlerFunc)(c)
+	}
 }
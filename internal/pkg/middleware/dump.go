@@ -1,16 +1,70 @@
 package middleware
 
 import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
 	"github.com/gin-gonic/gin"
-	gindump "github.com/tpkeeper/gin-dump"
 
+	"gobackend/pkg/json"
 	"gobackend/pkg/log"
 )
 
 // Dump header/body of request and response, very helpful for debugging your applications.
+// The sensitive headers and values are redacted, and the bodies are truncated by the redactor.
 // NOTE: Do not use in production for performance consideration.
 func Dump() gin.HandlerFunc {
-	return gindump.DumpWithOptions(true, true, true, true, false, func(dumpStr string) {
-		log.Info(dumpStr)
-	})
+	return func(c *gin.Context) {
+		r := getRedactor(c)
+
+		var b strings.Builder
+
+		header, _ := json.MarshalIndent(r.Header(c.Request.Header), "", "  ")
+		b.WriteString("Request-Header:\n")
+		b.Write(header)
+
+		if c.Request.Body != nil && c.Request.ContentLength != 0 {
+			body, err := ioutil.ReadAll(c.Request.Body)
+			c.Request.Body = ioutil.NopCloser(bytes.NewBuffer(body))
+
+			if err != nil {
+				b.WriteString("\nread request body error: " + err.Error())
+			} else {
+				b.WriteString("\nRequest-Body:\n")
+				b.WriteString(r.Body(c.ContentType(), body))
+			}
+		}
+
+		w := &bodyLogWriter{body: bytes.NewBufferString(""), ResponseWriter: c.Writer}
+		c.Writer = w
+
+		c.Next()
+
+		header, _ = json.MarshalIndent(r.Header(c.Writer.Header()), "", "  ")
+		b.WriteString("\nResponse-Header:\n")
+		b.Write(header)
+
+		if bodyAllowedForStatus(c.Writer.Status()) && w.body.Len() > 0 {
+			b.WriteString("\nResponse-Body:\n")
+			b.WriteString(r.Body(c.Writer.Header().Get("Content-Type"), w.body.Bytes()))
+		}
+
+		log.Info(b.String())
+	}
+}
+
+// bodyAllowedForStatus is a copy of http.bodyAllowedForStatus non-exported function.
+func bodyAllowedForStatus(status int) bool {
+	switch {
+	case status >= 100 && status <= 199:
+		return false
+	case status == http.StatusNoContent:
+		return false
+	case status == http.StatusNotModified:
+		return false
+	}
+
+	return true
 }
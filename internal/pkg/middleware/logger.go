@@ -125,7 +125,7 @@ func LoggerWithConfig(conf gin.LoggerConfig) gin.HandlerFunc {
 			param.BodySize = c.Writer.Size()
 
 			if raw != "" {
-				path = path + "?" + raw
+				path = path + "?" + getRedactor(c).Query(raw)
 			}
 
 			param.Path = path
@@ -12,8 +12,8 @@ import (
 	"gobackend/pkg/log"
 	metav1 "gobackend/pkg/meta/v1"
 
-	"gobackend/internal/app/apiserver/store"
 	"gobackend/internal/pkg/entity/apiserver/operationlog"
+	"gobackend/internal/pkg/redact"
 )
 
 var regPattern = regexp.MustCompile(`^/operation-logs*`)
@@ -35,8 +35,15 @@ func (w bodyLogWriter) WriteString(s string) (int, error) {
 	return w.ResponseWriter.WriteString(s)
 }
 
+// OperationLogQueue queues the operation logs to be written asynchronously.
+type OperationLogQueue interface {
+	// Enqueue puts the operation log into the queue without blocking,
+	// it returns false if the operation log is dropped.
+	Enqueue(operationLog *operationlog.OperationLog) bool
+}
+
 // OperationLog is a middleware function that logs operation.
-func OperationLog(storeIns store.Factory) gin.HandlerFunc {
+func OperationLog(queue OperationLogQueue) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Read operations do not need to be logged.
 		if c.Request.Method == http.MethodGet ||
@@ -49,6 +56,11 @@ func OperationLog(storeIns store.Factory) gin.HandlerFunc {
 			return
 		}
 
+		// Dry runs change nothing.
+		if metav1.IsDryRun(c.QueryArray("dry_run")) {
+			return
+		}
+
 		bodyLogWriter := &bodyLogWriter{
 			body:           bytes.NewBufferString(""),
 			ResponseWriter: c.Writer,
@@ -57,21 +69,23 @@ func OperationLog(storeIns store.Factory) gin.HandlerFunc {
 
 		startTime := time.Now()
 
-		requestBody := ""
+		var bodyBytes []byte
 		if c.Request.Body != nil {
-			bodyBytes, _ := ioutil.ReadAll(c.Request.Body)
+			bodyBytes, _ = ioutil.ReadAll(c.Request.Body)
 			c.Request.Body = ioutil.NopCloser(bytes.NewBuffer(bodyBytes))
-			requestBody = string(bodyBytes)
 		}
 
 		c.Next()
 
-		responseBody := bodyLogWriter.body.String()
+		// The passwords and the tokens must not be stored in the operation logs.
+		redactor := getRedactor(c)
+		requestBody := redactor.Body(c.ContentType(), bodyBytes)
+		responseBody := redactor.Body(c.Writer.Header().Get("Content-Type"), bodyLogWriter.body.Bytes())
 
 		latencyTime := time.Since(startTime).Seconds()
 
 		requestID := getRequestID(c)
-		requestURI := getRequestURI(c)
+		requestURI := getRequestURI(c, redactor)
 		username := c.GetString(UsernameKey)
 
 		clientIP := c.ClientIP()
@@ -93,19 +107,9 @@ func OperationLog(storeIns store.Factory) gin.HandlerFunc {
 			ResData:    responseBody,
 		}
 
-		go func() {
-			if err := storeIns.OperationLogs().Create(
-				c,
-				operationLog,
-				metav1.CreateOptions{},
-			); err != nil {
-				log.Errorf(
-					"request id %s: create an operation log error: %s",
-					requestID,
-					err,
-				)
-			}
-		}()
+		if !queue.Enqueue(operationLog) {
+			log.Warnf("request id %s: operation log dropped, the queue is full or closed", requestID)
+		}
 	}
 }
 
@@ -118,10 +122,10 @@ func getRequestID(c *gin.Context) string {
 	return requestID.(string)
 }
 
-func getRequestURI(c *gin.Context) string {
+func getRequestURI(c *gin.Context, redactor *redact.Redactor) string {
 	requestURI := c.Request.URL.Path
 	if c.Request.URL.RawQuery != "" {
-		requestURI = c.Request.URL.Path + "?" + c.Request.URL.RawQuery
+		requestURI = c.Request.URL.Path + "?" + redactor.Query(c.Request.URL.RawQuery)
 	}
 
 	return requestURI
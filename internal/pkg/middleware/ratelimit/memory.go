@@ -0,0 +1,79 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often the memory limiter drops the idle clients.
+const sweepInterval = time.Minute
+
+type memoryLimiter struct {
+	mu        sync.Mutex
+	tats      map[string]time.Time
+	nextSweep time.Time
+	now       func() time.Time
+}
+
+var _ Limiter = &memoryLimiter{}
+
+// NewMemoryLimiter creates a Limiter which keeps the counts in process, the limits are enforced
+// per replica.
+func NewMemoryLimiter() Limiter {
+	return newMemoryLimiter(time.Now)
+}
+
+func newMemoryLimiter(now func() time.Time) *memoryLimiter {
+	return &memoryLimiter{
+		tats: make(map[string]time.Time),
+		now:  now,
+	}
+}
+
+// Allow implements Limiter.
+func (m *memoryLimiter) Allow(_ context.Context, key string, limit Limit) (Result, error) {
+	if limit.Unlimited() {
+		return Result{Allowed: true}, nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	m.sweep(now)
+
+	tat, result := gcra(m.tats[key], now, limit)
+	m.tats[key] = tat
+
+	return result, nil
+}
+
+// Peek implements Limiter.
+func (m *memoryLimiter) Peek(_ context.Context, key string, limit Limit) (Result, error) {
+	if limit.Unlimited() {
+		return Result{Allowed: true}, nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	_, result := gcra(m.tats[key], m.now(), limit)
+
+	return result, nil
+}
+
+// sweep drops the clients whose counts have been reset, so that the map does not keep growing.
+func (m *memoryLimiter) sweep(now time.Time) {
+	if now.Before(m.nextSweep) {
+		return
+	}
+
+	for key, tat := range m.tats {
+		if !tat.After(now) {
+			delete(m.tats, key)
+		}
+	}
+
+	m.nextSweep = now.Add(sweepInterval)
+}
@@ -0,0 +1,235 @@
+package ratelimit
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"gobackend/pkg/core"
+	"gobackend/pkg/errors"
+	"gobackend/pkg/log"
+
+	"gobackend/internal/pkg/code"
+	"gobackend/internal/pkg/middleware"
+)
+
+// The ways the clients are identified.
+const (
+	// KeyIP identifies the clients by their IP addresses.
+	KeyIP = "ip"
+
+	// KeyUsername identifies the clients by their usernames, the anonymous clients are identified
+	// by their IP addresses.
+	KeyUsername = "username"
+
+	// KeyAPIKey identifies the clients by the secrets the requests are signed with, the other
+	// clients are identified as KeyUsername does.
+	KeyAPIKey = "api-key"
+)
+
+// The response headers describing the limit of the client.
+const (
+	HeaderLimit      = "X-RateLimit-Limit"
+	HeaderRemaining  = "X-RateLimit-Remaining"
+	HeaderReset      = "X-RateLimit-Reset"
+	HeaderRetryAfter = "Retry-After"
+)
+
+// Route overrides the limit of the requests matching Method and Path. Path is the route pattern,
+// e.g. /v1/users/:name, and an empty Method matches all the methods. The requests to the route
+// are counted apart from the other requests of the client.
+type Route struct {
+	Method string
+	Path   string
+	Key    string
+	Limit  Limit
+}
+
+// Policy defines how the requests are limited.
+type Policy struct {
+	// Key is how the clients are identified, one of KeyIP, KeyUsername and KeyAPIKey.
+	Key string
+
+	// Limit applies to the requests not matching any route.
+	Limit Limit
+
+	Routes []Route
+
+	// AuthFailures limits the failed authentications of the clients by their IP addresses,
+	// see AuthFailureHandler.
+	AuthFailures Limit
+}
+
+// Middleware limits the requests by a policy, the policy can be replaced while serving.
+type Middleware struct {
+	limiter Limiter
+	policy  atomic.Value
+}
+
+// compiledPolicy is a policy with the routes indexed by their names.
+type compiledPolicy struct {
+	Policy
+	routes map[string]Route
+}
+
+// New creates a middleware limiting the requests by the policy with the limiter.
+func New(limiter Limiter, policy Policy) *Middleware {
+	m := &Middleware{limiter: limiter}
+	m.SetPolicy(policy)
+
+	return m
+}
+
+// SetPolicy replaces the policy, the counts of the clients are kept.
+func (m *Middleware) SetPolicy(policy Policy) {
+	routes := make(map[string]Route, len(policy.Routes))
+	for _, route := range policy.Routes {
+		if route.Key == "" {
+			route.Key = policy.Key
+		}
+
+		routes[routeName(route.Method, route.Path)] = route
+	}
+
+	m.policy.Store(&compiledPolicy{Policy: policy, routes: routes})
+}
+
+// Handler returns the gin middleware. It should be installed after the authentication to identify
+// the clients by usernames or API keys. The requests are allowed if the limiter fails, rate limiting
+// should not take the API down.
+func (m *Middleware) Handler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		policy, _ := m.policy.Load().(*compiledPolicy)
+		scope, keyBy, limit := "default", policy.Key, policy.Limit
+
+		route, ok := policy.routes[routeName(c.Request.Method, c.FullPath())]
+		if !ok {
+			route, ok = policy.routes[routeName("", c.FullPath())]
+		}
+
+		if ok {
+			scope, keyBy, limit = routeName(route.Method, route.Path), route.Key, route.Limit
+		}
+
+		if limit.Unlimited() {
+			c.Next()
+
+			return
+		}
+
+		key := scope + ":" + clientKey(c, keyBy)
+
+		result, err := m.limiter.Allow(middleware.RequestContext(c), key, limit)
+		if err != nil {
+			log.C(c).Warnf("rate limiting %s failed, the request is allowed: %s", key, err.Error())
+			c.Next()
+
+			return
+		}
+
+		c.Header(HeaderLimit, strconv.Itoa(limit.burst()))
+		c.Header(HeaderRemaining, strconv.Itoa(result.Remaining))
+		c.Header(HeaderReset, strconv.Itoa(seconds(result.ResetAfter)))
+
+		if !result.Allowed {
+			reject(c, result)
+
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// AuthFailureHandler returns the gin middleware limiting the failed authentications, it should be
+// installed before the authentication, which Handler is installed after. The clients are identified
+// by their IP addresses, and the responses with status 401 are counted as failures. The clients
+// exceeding the limit are rejected before their credentials are checked, so that the passwords
+// can not be guessed by brute force.
+func (m *Middleware) AuthFailureHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		policy, _ := m.policy.Load().(*compiledPolicy)
+		limit := policy.AuthFailures
+
+		if limit.Unlimited() {
+			c.Next()
+
+			return
+		}
+
+		ctx := middleware.RequestContext(c)
+		key := "auth-failure:" + KeyIP + ":" + c.ClientIP()
+
+		result, err := m.limiter.Peek(ctx, key, limit)
+		if err != nil {
+			log.C(c).Warnf("rate limiting %s failed, the request is allowed: %s", key, err.Error())
+			c.Next()
+
+			return
+		}
+
+		if !result.Allowed {
+			reject(c, result)
+
+			return
+		}
+
+		c.Next()
+
+		if c.Writer.Status() != http.StatusUnauthorized {
+			return
+		}
+
+		if _, err := m.limiter.Allow(ctx, key, limit); err != nil {
+			log.C(c).Warnf("count the failed authentication of %s failed: %s", key, err.Error())
+		}
+	}
+}
+
+// reject responds ErrTooManyRequests to the request exceeding the limit.
+func reject(c *gin.Context, result Result) {
+	c.Header(HeaderRetryAfter, strconv.Itoa(seconds(result.RetryAfter)))
+	core.WriteResponse(
+		c,
+		errors.WithCode(code.ErrTooManyRequests, "rate limit exceeded, retry after %s", result.RetryAfter),
+		nil,
+	)
+	c.Abort()
+}
+
+// routeName returns the name of the route, it is also the scope of the counts of the route.
+func routeName(method, path string) string {
+	if method == "" {
+		return path
+	}
+
+	return strings.ToUpper(method) + " " + path
+}
+
+// clientKey identifies the client of the request.
+func clientKey(c *gin.Context, keyBy string) string {
+	switch keyBy {
+	case KeyAPIKey:
+		if secretID := c.GetString(middleware.SecretIDKey); secretID != "" {
+			return KeyAPIKey + ":" + secretID
+		}
+
+		fallthrough
+	case KeyUsername:
+		if username := c.GetString(middleware.UsernameKey); username != "" {
+			return KeyUsername + ":" + username
+		}
+	}
+
+	return KeyIP + ":" + c.ClientIP()
+}
+
+// seconds rounds the duration up to seconds, as the headers are in seconds.
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,192 @@
+package ratelimit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"gobackend/internal/pkg/code"
+	"gobackend/internal/pkg/middleware"
+)
+
+type failingLimiter struct{}
+
+func (failingLimiter) Allow(context.Context, string, Limit) (Result, error) {
+	return Result{}, errors.New("redis is down")
+}
+
+func (failingLimiter) Peek(context.Context, string, Limit) (Result, error) {
+	return Result{}, errors.New("redis is down")
+}
+
+func newEngine(limiter Limiter, policy Policy) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	g := gin.New()
+	authenticate := func(c *gin.Context) {
+		if username := c.GetHeader("X-Username"); username != "" {
+			c.Set(middleware.UsernameKey, username)
+		}
+
+		if secretID := c.GetHeader("X-Secret-ID"); secretID != "" {
+			c.Set(middleware.SecretIDKey, secretID)
+		}
+	}
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+
+	g.Use(authenticate, New(limiter, policy).Handler())
+	g.GET("/users/:name", ok)
+	g.POST("/login", ok)
+	g.GET("/healthz", ok)
+
+	return g
+}
+
+func do(g *gin.Engine, method, path string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	w := httptest.NewRecorder()
+	g.ServeHTTP(w, req)
+
+	return w
+}
+
+func TestHandler(t *testing.T) {
+	g := newEngine(NewMemoryLimiter(), Policy{
+		Key:   KeyAPIKey,
+		Limit: Limit{Rate: 1, Period: time.Hour},
+		Routes: []Route{
+			{Method: http.MethodPost, Path: "/login", Key: KeyIP, Limit: Limit{Rate: 2, Period: time.Hour}},
+			{Path: "/healthz"},
+		},
+	})
+
+	alice := map[string]string{"X-Username": "alice"}
+
+	w := do(g, http.MethodGet, "/users/alice", alice)
+	if w.Code != http.StatusOK || w.Header().Get(HeaderLimit) != "1" || w.Header().Get(HeaderRemaining) != "0" {
+		t.Fatalf("first request: status = %d, headers = %v", w.Code, w.Header())
+	}
+
+	// The routes share the default limit.
+	w = do(g, http.MethodGet, "/users/bob", alice)
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("second request: status = %d, want 429", w.Code)
+	}
+
+	if w.Header().Get(HeaderRetryAfter) != "3600" || w.Header().Get(HeaderReset) != "3600" {
+		t.Fatalf("second request: headers = %v, want Retry-After and X-RateLimit-Reset 3600", w.Header())
+	}
+
+	var body struct {
+		Code int `json:"code"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil || body.Code != code.ErrTooManyRequests {
+		t.Fatalf("second request: body = %s, want code %d", w.Body.String(), code.ErrTooManyRequests)
+	}
+
+	// Another user, and the same user signing with a secret, are counted apart.
+	if w = do(g, http.MethodGet, "/users/bob", map[string]string{"X-Username": "bob"}); w.Code != http.StatusOK {
+		t.Fatalf("request of bob: status = %d, want 200", w.Code)
+	}
+
+	withSecret := map[string]string{"X-Username": "alice", "X-Secret-ID": "kid"}
+	if w = do(g, http.MethodGet, "/users/alice", withSecret); w.Code != http.StatusOK {
+		t.Fatalf("request with a secret: status = %d, want 200", w.Code)
+	}
+
+	// The route is counted apart by the IP address.
+	for i, want := range []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests} {
+		if w = do(g, http.MethodPost, "/login", alice); w.Code != want {
+			t.Fatalf("login %d: status = %d, want %d", i, w.Code, want)
+		}
+	}
+
+	// The route is not limited.
+	for i := 0; i < 10; i++ {
+		if w = do(g, http.MethodGet, "/healthz", nil); w.Code != http.StatusOK || w.Header().Get(HeaderLimit) != "" {
+			t.Fatalf("healthz %d: status = %d, headers = %v", i, w.Code, w.Header())
+		}
+	}
+}
+
+func TestHandlerLimiterFails(t *testing.T) {
+	g := newEngine(failingLimiter{}, Policy{Key: KeyIP, Limit: Limit{Rate: 1, Period: time.Hour}})
+
+	for i := 0; i < 3; i++ {
+		if w := do(g, http.MethodGet, "/users/alice", nil); w.Code != http.StatusOK {
+			t.Fatalf("request %d: status = %d, want 200", i, w.Code)
+		}
+	}
+}
+
+func TestSetPolicy(t *testing.T) {
+	m := New(NewMemoryLimiter(), Policy{Key: KeyIP, Limit: Limit{Rate: 1, Period: time.Hour}})
+
+	g := gin.New()
+	g.Use(m.Handler())
+	g.GET("/users/:name", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	do(g, http.MethodGet, "/users/alice", nil)
+
+	if w := do(g, http.MethodGet, "/users/alice", nil); w.Code != http.StatusTooManyRequests {
+		t.Fatalf("status = %d before the policy is replaced, want 429", w.Code)
+	}
+
+	m.SetPolicy(Policy{
+		Key:    KeyIP,
+		Limit:  Limit{Rate: 1, Period: time.Hour},
+		Routes: []Route{{Path: "/users/:name"}},
+	})
+
+	if w := do(g, http.MethodGet, "/users/alice", nil); w.Code != http.StatusOK {
+		t.Fatalf("status = %d after the route is exempted, want 200", w.Code)
+	}
+}
+
+func TestAuthFailureHandler(t *testing.T) {
+	m := New(NewMemoryLimiter(), Policy{Key: KeyIP, AuthFailures: Limit{Rate: 2, Period: time.Hour}})
+
+	g := gin.New()
+	g.Use(m.AuthFailureHandler(), func(c *gin.Context) {
+		if c.GetHeader("Authorization") != "Basic good" {
+			c.AbortWithStatus(http.StatusUnauthorized)
+		}
+	})
+	g.GET("/users/:name", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	good := map[string]string{"Authorization": "Basic good"}
+	bad := map[string]string{"Authorization": "Basic bad"}
+
+	// The successful authentications are not counted.
+	for i := 0; i < 5; i++ {
+		if w := do(g, http.MethodGet, "/users/alice", good); w.Code != http.StatusOK {
+			t.Fatalf("request %d: status = %d, want 200", i, w.Code)
+		}
+	}
+
+	for i := 0; i < 2; i++ {
+		if w := do(g, http.MethodGet, "/users/alice", bad); w.Code != http.StatusUnauthorized {
+			t.Fatalf("failure %d: status = %d, want 401", i, w.Code)
+		}
+	}
+
+	// The client is rejected before the credentials are checked, even the right ones.
+	for _, headers := range []map[string]string{bad, good} {
+		w := do(g, http.MethodGet, "/users/alice", headers)
+		if w.Code != http.StatusTooManyRequests || w.Header().Get(HeaderRetryAfter) != "1800" {
+			t.Fatalf("status = %d, headers = %v, want 429 and retry after 1800", w.Code, w.Header())
+		}
+	}
+}
@@ -0,0 +1,92 @@
+// Package ratelimit limits the request rate of the clients, the clients are identified by their
+// IP addresses, usernames or API keys, and the limits can be overridden per route.
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Limit allows Rate requests per Period, and at most Burst requests at once.
+// A Limit with a zero Rate does not limit the requests.
+type Limit struct {
+	Rate   int
+	Period time.Duration
+	Burst  int
+}
+
+// Unlimited reports whether the limit does not limit the requests.
+func (l Limit) Unlimited() bool {
+	return l.Rate <= 0 || l.Period <= 0
+}
+
+// burst returns the max number of requests allowed at once, it defaults to the rate.
+func (l Limit) burst() int {
+	if l.Burst > 0 {
+		return l.Burst
+	}
+
+	return l.Rate
+}
+
+// interval returns the time it takes to earn one request.
+func (l Limit) interval() time.Duration {
+	return l.Period / time.Duration(l.Rate)
+}
+
+// Result is the result of a request checked against a limit.
+type Result struct {
+	// Allowed reports whether the request is allowed.
+	Allowed bool
+
+	// Remaining is the number of the requests still allowed at once.
+	Remaining int
+
+	// RetryAfter is the time to wait before the next request is allowed, it is zero if the
+	// request is allowed.
+	RetryAfter time.Duration
+
+	// ResetAfter is the time it takes to allow Burst requests at once again.
+	ResetAfter time.Duration
+}
+
+// Limiter checks the requests of the clients against their limits. The requests are counted
+// with the generic cell rate algorithm (GCRA), so the allowed requests are evenly spread.
+type Limiter interface {
+	// Allow counts a request of the client identified by key, and reports whether it is allowed.
+	Allow(ctx context.Context, key string, limit Limit) (Result, error)
+
+	// Peek reports whether a request of the client identified by key would be allowed,
+	// the request is not counted.
+	Peek(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// gcra counts a request at now against the limit, tat is the theoretical arrival time of the
+// client's next request. It returns the new theoretical arrival time, which is not changed
+// if the request is not allowed.
+func gcra(tat, now time.Time, limit Limit) (time.Time, Result) {
+	interval := limit.interval()
+	burstOffset := interval * time.Duration(limit.burst())
+
+	if tat.Before(now) {
+		tat = now
+	}
+
+	newTAT := tat.Add(interval)
+	diff := now.Sub(newTAT.Add(-burstOffset))
+
+	if diff < 0 {
+		return tat, Result{
+			Allowed:    false,
+			Remaining:  0,
+			RetryAfter: -diff,
+			ResetAfter: tat.Sub(now),
+		}
+	}
+
+	return newTAT, Result{
+		Allowed:    true,
+		Remaining:  int(diff / interval),
+		ResetAfter: newTAT.Sub(now),
+	}
+}
@@ -0,0 +1,118 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+)
+
+func TestMemoryLimiter(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2021, 10, 1, 0, 0, 0, 0, time.UTC)
+	limiter := newMemoryLimiter(func() time.Time { return now })
+	limit := Limit{Rate: 1, Period: time.Second, Burst: 3}
+
+	// Peeking does not count the requests.
+	for i := 0; i < 5; i++ {
+		if result, _ := limiter.Peek(ctx, "alice", limit); !result.Allowed || result.Remaining != 2 {
+			t.Fatalf("peek %d: Peek() = %+v, want allowed with 2 remaining", i, result)
+		}
+	}
+
+	for i, want := range []int{2, 1, 0} {
+		result, _ := limiter.Allow(ctx, "alice", limit)
+		if !result.Allowed || result.Remaining != want {
+			t.Fatalf("request %d: Allow() = %+v, want allowed with %d remaining", i, result, want)
+		}
+	}
+
+	result, _ := limiter.Allow(ctx, "alice", limit)
+	if result.Allowed || result.RetryAfter != time.Second || result.ResetAfter != 3*time.Second {
+		t.Fatalf("Allow() = %+v, want limited, retry after 1s and reset after 3s", result)
+	}
+
+	// The other clients are counted apart.
+	if result, _ := limiter.Allow(ctx, "bob", limit); !result.Allowed {
+		t.Fatalf("Allow() of another client = %+v, want allowed", result)
+	}
+
+	now = now.Add(time.Second)
+
+	if result, _ := limiter.Allow(ctx, "alice", limit); !result.Allowed || result.Remaining != 0 {
+		t.Fatalf("Allow() after 1s = %+v, want allowed with 0 remaining", result)
+	}
+
+	// The idle clients are swept.
+	now = now.Add(time.Hour)
+	_, _ = limiter.Allow(ctx, "carol", limit)
+
+	if len(limiter.tats) != 1 {
+		t.Fatalf("len(tats) = %d after the sweep, want 1", len(limiter.tats))
+	}
+}
+
+func TestUnlimited(t *testing.T) {
+	limiter := NewMemoryLimiter()
+
+	for i := 0; i < 100; i++ {
+		if result, _ := limiter.Allow(context.Background(), "alice", Limit{}); !result.Allowed {
+			t.Fatalf("Allow() = %+v, want allowed", result)
+		}
+	}
+}
+
+func TestRedisLimiter(t *testing.T) {
+	ctx := context.Background()
+
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("miniredis.Run() error = %v", err)
+	}
+
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+
+	t.Cleanup(func() {
+		client.Close()
+		mr.Close()
+	})
+
+	limiter := NewRedisLimiter(client, "test:")
+	limit := Limit{Rate: 2, Period: time.Hour}
+
+	if result, err := limiter.Peek(ctx, "alice", limit); err != nil || !result.Allowed || mr.Exists("test:alice") {
+		t.Fatalf("Peek() = %+v, %v, want allowed without counting", result, err)
+	}
+
+	for i, want := range []int{1, 0} {
+		result, err := limiter.Allow(ctx, "alice", limit)
+		if err != nil || !result.Allowed || result.Remaining != want {
+			t.Fatalf("request %d: Allow() = %+v, %v, want allowed with %d remaining", i, result, err, want)
+		}
+	}
+
+	if result, err := limiter.Peek(ctx, "alice", limit); err != nil || result.Allowed {
+		t.Fatalf("Peek() = %+v, %v, want limited", result, err)
+	}
+
+	result, err := limiter.Allow(ctx, "alice", limit)
+	if err != nil || result.Allowed {
+		t.Fatalf("Allow() = %+v, %v, want limited", result, err)
+	}
+
+	if result.RetryAfter <= 29*time.Minute || result.RetryAfter > 30*time.Minute {
+		t.Fatalf("RetryAfter = %v, want about 30m", result.RetryAfter)
+	}
+
+	if ttl := mr.TTL("test:alice"); ttl <= 59*time.Minute || ttl > time.Hour {
+		t.Fatalf("TTL() = %v, want about 1h", ttl)
+	}
+
+	mr.Close()
+
+	if _, err := limiter.Allow(ctx, "alice", limit); err == nil {
+		t.Fatal("Allow() error = nil with redis down, want error")
+	}
+}
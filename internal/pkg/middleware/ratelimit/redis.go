@@ -0,0 +1,105 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// gcraScript is gcra run in redis, the time is taken from the redis server so that the replicas
+// share the clock. KEYS[1] holds the theoretical arrival time in microseconds, ARGV[1] is
+// the emission interval and ARGV[2] is the burst offset, both in microseconds. The request is
+// only counted if ARGV[3] is 1.
+var gcraScript = redis.NewScript(`
+redis.replicate_commands()
+
+local interval = tonumber(ARGV[1])
+local burst_offset = tonumber(ARGV[2])
+local count = ARGV[3] == "1"
+
+local time = redis.call("TIME")
+local now = tonumber(time[1]) * 1000000 + tonumber(time[2])
+
+local tat = tonumber(redis.call("GET", KEYS[1]))
+if not tat or tat < now then
+	tat = now
+end
+
+local new_tat = tat + interval
+local diff = now - (new_tat - burst_offset)
+
+if diff < 0 then
+	return {0, 0, -diff, tat - now}
+end
+
+if count then
+	redis.call("SET", KEYS[1], string.format("%.0f", new_tat), "PX", math.ceil((new_tat - now) / 1000))
+end
+
+return {1, math.floor(diff / interval), 0, new_tat - now}
+`)
+
+type redisLimiter struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+var _ Limiter = &redisLimiter{}
+
+// NewRedisLimiter creates a Limiter which keeps the counts in redis, the limits are shared by
+// the replicas. The keys of the counts are prefixed by prefix.
+func NewRedisLimiter(client redis.UniversalClient, prefix string) Limiter {
+	return &redisLimiter{
+		client: client,
+		prefix: prefix,
+	}
+}
+
+// Allow implements Limiter.
+func (r *redisLimiter) Allow(ctx context.Context, key string, limit Limit) (Result, error) {
+	return r.run(ctx, key, limit, true)
+}
+
+// Peek implements Limiter.
+func (r *redisLimiter) Peek(ctx context.Context, key string, limit Limit) (Result, error) {
+	return r.run(ctx, key, limit, false)
+}
+
+func (r *redisLimiter) run(ctx context.Context, key string, limit Limit, count bool) (Result, error) {
+	if limit.Unlimited() {
+		return Result{Allowed: true}, nil
+	}
+
+	countArg := 0
+	if count {
+		countArg = 1
+	}
+
+	interval := limit.interval()
+	burstOffset := interval * time.Duration(limit.burst())
+
+	values, err := gcraScript.Run(
+		ctx,
+		r.client,
+		[]string{r.prefix + key},
+		interval.Microseconds(),
+		burstOffset.Microseconds(),
+		countArg,
+	).Int64Slice()
+	if err != nil {
+		return Result{}, err
+	}
+
+	if len(values) != 4 {
+		return Result{}, fmt.Errorf("unexpected rate limit script result: %v", values)
+	}
+
+	return Result{
+		Allowed:    values[0] == 1,
+		Remaining:  int(values[1]),
+		RetryAfter: time.Duration(values[2]) * time.Microsecond,
+		ResetAfter: time.Duration(values[3]) * time.Microsecond,
+	}, nil
+}
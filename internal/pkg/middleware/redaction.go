@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"gobackend/internal/pkg/redact"
+)
+
+// RedactorKey defines the key in gin context which represents the redactor of the request data.
+const RedactorKey = "redactor"
+
+// Redaction is a middleware that injects the redactor used by the logging middlewares to gin.Context.
+func Redaction(r *redact.Redactor) gin.HandlerFunc {
+	if r == nil {
+		r = redact.Default()
+	}
+
+	return func(c *gin.Context) {
+		c.Set(RedactorKey, r)
+
+		c.Next()
+	}
+}
+
+// getRedactor returns the redactor in gin.Context, or the default one if there is none.
+func getRedactor(c *gin.Context) *redact.Redactor {
+	if r, ok := c.Value(RedactorKey).(*redact.Redactor); ok {
+		return r
+	}
+
+	return redact.Default()
+}
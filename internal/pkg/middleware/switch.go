@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+
+	"gobackend/pkg/core"
+	"gobackend/pkg/errors"
+
+	"gobackend/internal/pkg/code"
+)
+
+// Switch turns the middlewares of a feature on and off while serving.
+type Switch struct {
+	on int32
+}
+
+// NewSwitch creates a switch in the given state.
+func NewSwitch(on bool) *Switch {
+	s := &Switch{}
+	s.Set(on)
+
+	return s
+}
+
+// Set turns the switch on or off.
+func (s *Switch) Set(on bool) {
+	var v int32
+	if on {
+		v = 1
+	}
+
+	atomic.StoreInt32(&s.on, v)
+}
+
+// On reports whether the switch is on.
+func (s *Switch) On() bool {
+	return atomic.LoadInt32(&s.on) == 1
+}
+
+// Wrap returns a middleware which runs handler when the switch is on, and passes the requests
+// on when it is off.
+func (s *Switch) Wrap(handler gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if s.On() {
+			handler(c)
+
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// Guard returns a middleware which responds as if the routes do not exist when the switch is off.
+func (s *Switch) Guard() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !s.On() {
+			core.WriteResponse(c, errors.WithCode(code.ErrPageNotFound, "URL path not found"), nil)
+			c.Abort()
+
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Timeout is a middleware that sets a deadline on the request context,
+// the deadline reaches the database through RequestContext.
+// The requests for which longRunning returns true are exempt, longRunning can be nil.
+func Timeout(timeout time.Duration, longRunning func(r *http.Request) bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if longRunning != nil && longRunning(c.Request) {
+			c.Next()
+
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+	}
+}
@@ -0,0 +1,89 @@
+package options
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// Available authentication strategies.
+const (
+	AuthStrategyBasic = "basic"
+	AuthStrategyJWT   = "jwt"
+	AuthStrategyAuto  = "auto"
+	AuthStrategyCache = "cache"
+)
+
+// AuthOptions contains configuration items related to API authentication.
+type AuthOptions struct {
+	Strategy   string        `json:"strategy"    mapstructure:"strategy"`
+	Realm      string        `json:"realm"       mapstructure:"realm"`
+	Key        string        `json:"-"           mapstructure:"key"`
+	Timeout    time.Duration `json:"timeout"     mapstructure:"timeout"`
+	MaxRefresh time.Duration `json:"max-refresh" mapstructure:"max-refresh"`
+}
+
+// NewAuthOptions creates an AuthOptions object with default parameters.
+func NewAuthOptions() *AuthOptions {
+	return &AuthOptions{
+		Strategy:   AuthStrategyAuto,
+		Realm:      "gobackend jwt",
+		Key:        "",
+		Timeout:    1 * time.Hour,
+		MaxRefresh: 1 * time.Hour,
+	}
+}
+
+// Validate is used to parse and validate the parameters entered by the user at
+// the command line when the program starts.
+func (o *AuthOptions) Validate() (errs []error) {
+	switch o.Strategy {
+	case AuthStrategyBasic, AuthStrategyJWT, AuthStrategyAuto, AuthStrategyCache:
+	default:
+		errs = append(errs, fmt.Errorf(
+			"unknown auth.strategy: %s, available strategy: [%s %s %s %s]",
+			o.Strategy,
+			AuthStrategyBasic,
+			AuthStrategyJWT,
+			AuthStrategyAuto,
+			AuthStrategyCache,
+		))
+	}
+
+	switch {
+	case o.Key == "":
+		errs = append(errs, fmt.Errorf("auth.key is required, set it by --auth.key or the environment variable"))
+	case len(o.Key) < 6 || len(o.Key) > 32:
+		errs = append(errs, fmt.Errorf("auth.key must be between 6 and 32 characters"))
+	}
+
+	if o.Timeout <= 0 {
+		errs = append(errs, fmt.Errorf("auth.timeout must be greater than 0, got %s", o.Timeout))
+	}
+
+	return
+}
+
+// AddFlags adds flags related to authentication for a specific api server to the
+// specified FlagSet.
+func (o *AuthOptions) AddFlags(fs *pflag.FlagSet) {
+	if fs == nil {
+		return
+	}
+
+	fs.StringVar(&o.Strategy, "auth.strategy", o.Strategy, ""+
+		"Authentication strategy used to protect the API, available strategy: basic, jwt, auto, cache. "+
+		"The cache strategy only accepts tokens signed by user secrets, "+
+		"and auto strategy accepts all of them.")
+
+	fs.StringVar(&o.Realm, "auth.realm", o.Realm, "Realm name to display to the user.")
+
+	fs.StringVar(&o.Key, "auth.key", o.Key, "Private key used to sign jwt token, required. "+
+		"Prefer the environment variable to the configuration file to keep it secret.")
+
+	fs.DurationVar(&o.Timeout, "auth.timeout", o.Timeout, "JWT token timeout.")
+
+	fs.DurationVar(&o.MaxRefresh, "auth.max-refresh", o.MaxRefresh, ""+
+		"This field allows clients to refresh their token until MaxRefresh has passed.")
+}
@@ -0,0 +1,92 @@
+package options
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gin-contrib/cors"
+	"github.com/spf13/pflag"
+
+	"gobackend/internal/pkg/middleware"
+	"gobackend/internal/pkg/server"
+)
+
+// CorsOptions defines options for the cors middleware, which is enabled by server.middlewares.
+type CorsOptions struct {
+	AllowOrigins     []string      `json:"allow-origins"     mapstructure:"allow-origins"`
+	AllowMethods     []string      `json:"allow-methods"     mapstructure:"allow-methods"`
+	AllowHeaders     []string      `json:"allow-headers"     mapstructure:"allow-headers"`
+	ExposeHeaders    []string      `json:"expose-headers"    mapstructure:"expose-headers"`
+	AllowCredentials bool          `json:"allow-credentials" mapstructure:"allow-credentials"`
+	MaxAge           time.Duration `json:"max-age"           mapstructure:"max-age"`
+}
+
+// NewCorsOptions creates a CorsOptions object with default parameters.
+func NewCorsOptions() *CorsOptions {
+	defaults := middleware.DefaultCorsConfig()
+
+	return &CorsOptions{
+		AllowOrigins:     defaults.AllowOrigins,
+		AllowMethods:     defaults.AllowMethods,
+		AllowHeaders:     defaults.AllowHeaders,
+		ExposeHeaders:    defaults.ExposeHeaders,
+		AllowCredentials: defaults.AllowCredentials,
+		MaxAge:           defaults.MaxAge,
+	}
+}
+
+// Config returns the cors configuration of the options, the origins may contain a wildcard.
+func (o *CorsOptions) Config() cors.Config {
+	return cors.Config{
+		AllowOrigins:     o.AllowOrigins,
+		AllowMethods:     o.AllowMethods,
+		AllowHeaders:     o.AllowHeaders,
+		ExposeHeaders:    o.ExposeHeaders,
+		AllowCredentials: o.AllowCredentials,
+		AllowWildcard:    true,
+		MaxAge:           o.MaxAge,
+	}
+}
+
+// ApplyTo applies the run options to the method receiver and returns self.
+func (o *CorsOptions) ApplyTo(c *server.Config) error {
+	c.Cors = o.Config()
+
+	return nil
+}
+
+// Validate verifies flags passed to CorsOptions.
+func (o *CorsOptions) Validate() []error {
+	var errs []error
+
+	if err := middleware.ValidateCorsConfig(o.Config()); err != nil {
+		errs = append(errs, fmt.Errorf("invalid cors options: %w", err))
+	}
+
+	if o.MaxAge < 0 {
+		errs = append(errs, fmt.Errorf("cors.max-age can not be negative"))
+	}
+
+	return errs
+}
+
+// AddFlags adds flags related to cors for a specific APIServer to the specified FlagSet.
+func (o *CorsOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringSliceVar(&o.AllowOrigins, "cors.allow-origins", o.AllowOrigins, ""+
+		"The origins allowed to access the API, e.g. https://*.example.com. * allows all the origins.")
+
+	fs.StringSliceVar(&o.AllowMethods, "cors.allow-methods", o.AllowMethods, ""+
+		"The methods allowed in the cross origin requests.")
+
+	fs.StringSliceVar(&o.AllowHeaders, "cors.allow-headers", o.AllowHeaders, ""+
+		"The headers allowed in the cross origin requests.")
+
+	fs.StringSliceVar(&o.ExposeHeaders, "cors.expose-headers", o.ExposeHeaders, ""+
+		"The headers of the responses exposed to the cross origin requests.")
+
+	fs.BoolVar(&o.AllowCredentials, "cors.allow-credentials", o.AllowCredentials, ""+
+		"Allow the cross origin requests to carry the credentials, e.g. cookies.")
+
+	fs.DurationVar(&o.MaxAge, "cors.max-age", o.MaxAge, ""+
+		"How long the results of the preflight requests can be cached.")
+}
@@ -11,6 +11,8 @@ type FeatureOptions struct {
 	EnableProfiling        bool `json:"profiling"      mapstructure:"profiling"`
 	EnableMetrics          bool `json:"enable-metrics" mapstructure:"enable-metrics"`
 	EnableOperationLogging bool `json:"operation-logging" mapstructure:"operation-logging"`
+	EnableStoreCache       bool `json:"store-cache"       mapstructure:"store-cache"`
+	EnableRateLimit        bool `json:"rate-limit"        mapstructure:"rate-limit"`
 }
 
 // NewFeatureOptions creates a FeatureOptions object with default parameters.
@@ -58,4 +60,10 @@ func (o *FeatureOptions) AddFlags(fs *pflag.FlagSet) {
 		o.EnableOperationLogging,
 		"Enable operation logging of the apiserver",
 	)
+
+	fs.BoolVar(&o.EnableStoreCache, "feature.store-cache", o.EnableStoreCache,
+		"Cache the users read from the database in redis, see the --redis.* flags.")
+
+	fs.BoolVar(&o.EnableRateLimit, "feature.rate-limit", o.EnableRateLimit,
+		"Limit the request rate of the clients, see the --rate-limit.* flags.")
 }
@@ -4,18 +4,18 @@ import (
 	"fmt"
 
 	"github.com/spf13/pflag"
+
+	"gobackend/internal/pkg/server"
 )
 
-// GRPCOptions are for creating an unauthenticated, unauthorized, insecure port.
-// No one should be using these anymore.
+// GRPCOptions are for creating the gRPC port, the calls are authenticated as the http requests.
 type GRPCOptions struct {
 	BindAddress string `json:"bind-address" mapstructure:"bind-address"`
 	BindPort    int    `json:"bind-port"    mapstructure:"bind-port"`
 	MaxMsgSize  int    `json:"max-msg-size" mapstructure:"max-msg-size"`
 }
 
-// NewGRPCOptions is for creating an unauthenticated, unauthorized, insecure port.
-// No one should be using these anymore.
+// NewGRPCOptions is for creating the gRPC port with the default parameters.
 func NewGRPCOptions() *GRPCOptions {
 	return &GRPCOptions{
 		BindAddress: "0.0.0.0",
@@ -33,15 +33,30 @@ func (s *GRPCOptions) Validate() []error {
 		errors = append(
 			errors,
 			fmt.Errorf(
-				"--insecure-port %v must be between 0 and 65535, inclusive. 0 for turning off insecure (HTTP) port",
+				"--grpc.bind-port %v must be between 0 and 65535, inclusive. 0 for turning off grpc port",
 				s.BindPort,
 			),
 		)
 	}
 
+	if s.MaxMsgSize <= 0 {
+		errors = append(errors, fmt.Errorf("--grpc.max-msg-size %v must be greater than 0", s.MaxMsgSize))
+	}
+
 	return errors
 }
 
+// ApplyTo applies the run options to the method receiver and returns self.
+func (s *GRPCOptions) ApplyTo(c *server.Config) error {
+	c.GRPCServing = &server.GRPCServingInfo{
+		BindAddress: s.BindAddress,
+		BindPort:    s.BindPort,
+		MaxMsgSize:  s.MaxMsgSize,
+	}
+
+	return nil
+}
+
 // AddFlags adds flags related to features for a specific api server to the
 // specified FlagSet.
 func (s *GRPCOptions) AddFlags(fs *pflag.FlagSet) {
@@ -49,10 +64,8 @@ func (s *GRPCOptions) AddFlags(fs *pflag.FlagSet) {
 		"The IP address on which to serve the --grpc.bind-port(set to 0.0.0.0 for all IPv4 interfaces and :: for all IPv6 interfaces).")
 
 	fs.IntVar(&s.BindPort, "grpc.bind-port", s.BindPort, ""+
-		"The port on which to serve unsecured, unauthenticated grpc access. It is assumed "+
-		"that firewall rules are set up such that this port is not reachable from outside of "+
-		"the deployed machine and that port 443 on the public address is proxied to this "+
-		"port. This is performed by nginx in the default setup. Set to zero to disable.")
+		"The port on which to serve grpc access. The calls are authenticated with the `authorization` "+
+		"metadata, which is the same as the Authorization header of the http requests. Set to zero to disable.")
 
 	fs.IntVar(&s.MaxMsgSize, "grpc.max-msg-size", s.MaxMsgSize, "gRPC max message size.")
 }
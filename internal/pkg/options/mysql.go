@@ -6,15 +6,27 @@ import (
 
 	"github.com/spf13/pflag"
 
+	"gobackend/pkg/db"
+
 	"gobackend/internal/pkg/gormlog"
 )
 
+// Available storage drivers.
+const (
+	DriverMySQL      = db.MySQL
+	DriverPostgreSQL = db.PostgreSQL
+	DriverSQLite     = db.SQLite
+	DriverMemory     = "memory"
+)
+
 // MySQLOptions defines options for mysql database.
 type MySQLOptions struct {
+	Driver                string        `json:"driver"                             mapstructure:"driver"`
 	Host                  string        `json:"host,omitempty"                     mapstructure:"host"`
 	Username              string        `json:"username,omitempty"                 mapstructure:"username"`
 	Password              string        `json:"-"                                  mapstructure:"password"`
 	Database              string        `json:"database"                           mapstructure:"database"`
+	SSLMode               string        `json:"ssl-mode"                           mapstructure:"ssl-mode"`
 	MaxIdleConnections    int           `json:"max-idle-connections,omitempty"     mapstructure:"max-idle-connections"`
 	MaxOpenConnections    int           `json:"max-open-connections,omitempty"     mapstructure:"max-open-connections"`
 	MaxConnectionLifetime time.Duration `json:"max-connection-lifetime,omitempty"  mapstructure:"max-connection-lifetime"`
@@ -25,10 +37,12 @@ type MySQLOptions struct {
 // NewMySQLOptions create a `zero` value instance.
 func NewMySQLOptions() *MySQLOptions {
 	return &MySQLOptions{
+		Driver:                DriverMySQL,
 		Host:                  "127.0.0.1:3306",
 		Username:              "",
 		Password:              "",
 		Database:              "",
+		SSLMode:               "disable",
 		MaxIdleConnections:    100,
 		MaxOpenConnections:    100,
 		MaxConnectionLifetime: time.Duration(120) * time.Second,
@@ -39,6 +53,36 @@ func NewMySQLOptions() *MySQLOptions {
 
 // Validate verifies flags passed to MySQLOptions.
 func (o *MySQLOptions) Validate() (errs []error) {
+	switch o.Driver {
+	case DriverMySQL, DriverPostgreSQL, DriverMemory:
+	case DriverSQLite:
+		if o.Database == "" {
+			errs = append(errs, fmt.Errorf("mysql.database is the database file of sqlite and can not be empty"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf(
+			"unknown mysql.driver: %s, available driver: [%s %s %s %s]",
+			o.Driver,
+			DriverMySQL,
+			DriverPostgreSQL,
+			DriverSQLite,
+			DriverMemory,
+		))
+	}
+
+	validSSLMode := false
+	for _, mode := range db.PostgreSQLSSLModes {
+		validSSLMode = validSSLMode || o.SSLMode == mode
+	}
+
+	if !validSSLMode {
+		errs = append(errs, fmt.Errorf(
+			"unknown mysql.ssl-mode: %s, available ssl mode: %v",
+			o.SSLMode,
+			db.PostgreSQLSSLModes,
+		))
+	}
+
 	if _, ok := gormlog.LogLevelMap[o.LogLevel]; !ok {
 		errs = append(errs, fmt.Errorf(
 			"unknown mysql.log-level: %s, available log level: [silent error warn info]",
@@ -51,6 +95,15 @@ func (o *MySQLOptions) Validate() (errs []error) {
 
 // AddFlags adds flags related to mysql storage for a specific APIServer to the specified FlagSet.
 func (o *MySQLOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(
+		&o.Driver,
+		"mysql.driver",
+		o.Driver,
+		"Storage driver: mysql, postgres, sqlite, memory. The sqlite driver uses mysql.database as the database file. "+
+			"The memory driver keeps all the data in process and ignores the other mysql options, "+
+			"it is only intended for tests and local development.",
+	)
+
 	fs.StringVar(
 		&o.Host,
 		"mysql.host",
@@ -79,6 +132,14 @@ func (o *MySQLOptions) AddFlags(fs *pflag.FlagSet) {
 		"Database name for the server to use.",
 	)
 
+	fs.StringVar(
+		&o.SSLMode,
+		"mysql.ssl-mode",
+		o.SSLMode,
+		"The ssl mode of the connections to postgres: disable, allow, prefer, require, verify-ca, verify-full. "+
+			"It is ignored by the other drivers.",
+	)
+
 	fs.IntVar(
 		&o.MaxIdleConnections,
 		"mysql.max-idle-connections",
@@ -111,6 +172,7 @@ func (o *MySQLOptions) AddFlags(fs *pflag.FlagSet) {
 		&o.AutoMigrate,
 		"mysql.auto-migrate",
 		o.AutoMigrate,
-		"Auto migrate database or not.",
+		"Apply the pending schema migrations on start or not, "+
+			"prefer the migrate command in a production environment.",
 	)
 }
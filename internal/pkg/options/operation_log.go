@@ -0,0 +1,191 @@
+package options
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// OperationLogOptions defines options for operation logs.
+type OperationLogOptions struct {
+	MaxAge           time.Duration `json:"max-age"            mapstructure:"max-age"`
+	MaxRows          int64         `json:"max-rows"           mapstructure:"max-rows"`
+	CleanupInterval  time.Duration `json:"cleanup-interval"   mapstructure:"cleanup-interval"`
+	CleanupBatchSize int           `json:"cleanup-batch-size" mapstructure:"cleanup-batch-size"`
+	ArchiveDir       string        `json:"archive-dir"        mapstructure:"archive-dir"`
+	QueueSize        int           `json:"queue-size"         mapstructure:"queue-size"`
+	BatchSize        int           `json:"batch-size"         mapstructure:"batch-size"`
+	FlushInterval    time.Duration `json:"flush-interval"     mapstructure:"flush-interval"`
+	DrainTimeout     time.Duration `json:"drain-timeout"      mapstructure:"drain-timeout"`
+	Sinks            []string      `json:"sinks"              mapstructure:"sinks"`
+	SinkFile         string        `json:"sink-file"          mapstructure:"sink-file"`
+}
+
+// Operation log sinks.
+const (
+	SinkDatabase = "database"
+	SinkFile     = "file"
+	SinkStdout   = "stdout"
+)
+
+// NewOperationLogOptions create a `zero` value instance, the operation logs are kept forever.
+func NewOperationLogOptions() *OperationLogOptions {
+	return &OperationLogOptions{
+		MaxAge:           0,
+		MaxRows:          0,
+		CleanupInterval:  time.Hour,
+		CleanupBatchSize: 1000,
+		ArchiveDir:       "",
+		QueueSize:        10000,
+		BatchSize:        100,
+		FlushInterval:    time.Second,
+		DrainTimeout:     10 * time.Second,
+		Sinks:            []string{SinkDatabase},
+		SinkFile:         "",
+	}
+}
+
+// RetentionEnabled returns true if the expired operation logs should be deleted.
+func (o *OperationLogOptions) RetentionEnabled() bool {
+	return o.MaxAge > 0 || o.MaxRows > 0
+}
+
+// Validate verifies flags passed to OperationLogOptions.
+func (o *OperationLogOptions) Validate() []error {
+	var errs []error
+
+	if o.MaxAge < 0 {
+		errs = append(errs, fmt.Errorf("operation-log.max-age can not be negative"))
+	}
+
+	if o.MaxRows < 0 {
+		errs = append(errs, fmt.Errorf("operation-log.max-rows can not be negative"))
+	}
+
+	if o.RetentionEnabled() {
+		if o.CleanupInterval <= 0 {
+			errs = append(errs, fmt.Errorf("operation-log.cleanup-interval must be positive"))
+		}
+
+		if o.CleanupBatchSize <= 0 {
+			errs = append(errs, fmt.Errorf("operation-log.cleanup-batch-size must be positive"))
+		}
+	}
+
+	if o.QueueSize <= 0 {
+		errs = append(errs, fmt.Errorf("operation-log.queue-size must be positive"))
+	}
+
+	if o.BatchSize <= 0 {
+		errs = append(errs, fmt.Errorf("operation-log.batch-size must be positive"))
+	}
+
+	if o.FlushInterval <= 0 {
+		errs = append(errs, fmt.Errorf("operation-log.flush-interval must be positive"))
+	}
+
+	if o.DrainTimeout < 0 {
+		errs = append(errs, fmt.Errorf("operation-log.drain-timeout can not be negative"))
+	}
+
+	if len(o.Sinks) == 0 {
+		errs = append(errs, fmt.Errorf("operation-log.sinks can not be empty"))
+	}
+
+	for _, sink := range o.Sinks {
+		switch sink {
+		case SinkDatabase, SinkStdout:
+		case SinkFile:
+			if o.SinkFile == "" {
+				errs = append(errs, fmt.Errorf("operation-log.sink-file is required by the file sink"))
+			}
+		default:
+			errs = append(errs, fmt.Errorf("unsupported operation log sink: %s", sink))
+		}
+	}
+
+	return errs
+}
+
+// AddFlags adds flags related to operation logs for a specific APIServer to the specified FlagSet.
+func (o *OperationLogOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.DurationVar(
+		&o.MaxAge,
+		"operation-log.max-age",
+		o.MaxAge,
+		"Delete the operation logs older than it, e.g. 720h. 0 means no limit.",
+	)
+
+	fs.Int64Var(
+		&o.MaxRows,
+		"operation-log.max-rows",
+		o.MaxRows,
+		"Keep only the newest operation logs up to the number. 0 means no limit.",
+	)
+
+	fs.DurationVar(
+		&o.CleanupInterval,
+		"operation-log.cleanup-interval",
+		o.CleanupInterval,
+		"How often the expired operation logs are deleted.",
+	)
+
+	fs.IntVar(
+		&o.CleanupBatchSize,
+		"operation-log.cleanup-batch-size",
+		o.CleanupBatchSize,
+		"The number of the expired operation logs deleted in a statement.",
+	)
+
+	fs.StringVar(
+		&o.ArchiveDir,
+		"operation-log.archive-dir",
+		o.ArchiveDir,
+		"If not empty, the expired operation logs are written to gzip compressed JSON lines files "+
+			"in the directory before they are deleted.",
+	)
+
+	fs.IntVar(
+		&o.QueueSize,
+		"operation-log.queue-size",
+		o.QueueSize,
+		"The maximum number of the operation logs waiting to be written, "+
+			"the new ones are dropped when the queue is full.",
+	)
+
+	fs.IntVar(
+		&o.BatchSize,
+		"operation-log.batch-size",
+		o.BatchSize,
+		"The maximum number of the operation logs written to the sinks at a time.",
+	)
+
+	fs.DurationVar(
+		&o.FlushInterval,
+		"operation-log.flush-interval",
+		o.FlushInterval,
+		"The maximum time an operation log waits in the queue before it is written.",
+	)
+
+	fs.DurationVar(
+		&o.DrainTimeout,
+		"operation-log.drain-timeout",
+		o.DrainTimeout,
+		"How long to wait for the queued operation logs to be written on shutdown. 0 means no limit.",
+	)
+
+	fs.StringSliceVar(
+		&o.Sinks,
+		"operation-log.sinks",
+		o.Sinks,
+		"The sinks the operation logs are written to, supported values: database, file, stdout.",
+	)
+
+	fs.StringVar(
+		&o.SinkFile,
+		"operation-log.sink-file",
+		o.SinkFile,
+		"The JSON lines file the operation logs are appended to by the file sink.",
+	)
+}
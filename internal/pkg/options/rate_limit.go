@@ -0,0 +1,154 @@
+package options
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"gobackend/internal/pkg/middleware/ratelimit"
+)
+
+// Rate limit backends.
+const (
+	RateLimitBackendMemory = "memory"
+	RateLimitBackendRedis  = "redis"
+)
+
+// RateLimitOptions defines options for rate limiting, the clients are allowed Rate requests
+// per Period, and at most Burst requests at once. The failed authentications are limited apart,
+// a client is allowed AuthFailureRate failures per AuthFailurePeriod by its IP address.
+type RateLimitOptions struct {
+	Backend string        `json:"backend" mapstructure:"backend"`
+	Key     string        `json:"key"     mapstructure:"key"`
+	Rate    int           `json:"rate"    mapstructure:"rate"`
+	Period  time.Duration `json:"period"  mapstructure:"period"`
+	Burst   int           `json:"burst"   mapstructure:"burst"`
+	// Routes override the limit of the routes, they can only be set in the config file.
+	Routes            []RateLimitRoute `json:"routes"              mapstructure:"routes"`
+	AuthFailureRate   int              `json:"auth-failure-rate"   mapstructure:"auth-failure-rate"`
+	AuthFailurePeriod time.Duration    `json:"auth-failure-period" mapstructure:"auth-failure-period"`
+}
+
+// RateLimitRoute overrides the limit of a route, the zero Key and Period default to the ones of
+// RateLimitOptions, the zero Burst defaults to Rate, and a zero Rate disables rate limiting of the route.
+type RateLimitRoute struct {
+	// Method is the HTTP method of the route, empty means all the methods.
+	Method string `json:"method" mapstructure:"method"`
+	// Path is the path pattern of the route, e.g. /v1/users/:name.
+	Path   string        `json:"path"   mapstructure:"path"`
+	Key    string        `json:"key"    mapstructure:"key"`
+	Rate   int           `json:"rate"   mapstructure:"rate"`
+	Period time.Duration `json:"period" mapstructure:"period"`
+	Burst  int           `json:"burst"  mapstructure:"burst"`
+}
+
+// NewRateLimitOptions create a `zero` value instance.
+func NewRateLimitOptions() *RateLimitOptions {
+	return &RateLimitOptions{
+		Backend: RateLimitBackendMemory,
+		Key:     ratelimit.KeyIP,
+		Rate:    20,
+		Period:  time.Second,
+		Burst:   40,
+		Routes:  []RateLimitRoute{},
+
+		AuthFailureRate:   10,
+		AuthFailurePeriod: time.Minute,
+	}
+}
+
+// Validate verifies flags passed to RateLimitOptions.
+func (o *RateLimitOptions) Validate() []error {
+	var errs []error
+
+	switch o.Backend {
+	case RateLimitBackendMemory, RateLimitBackendRedis:
+	default:
+		errs = append(errs, fmt.Errorf("unsupported rate limit backend: %s", o.Backend))
+	}
+
+	if err := validateRateLimitKey(o.Key); err != nil {
+		errs = append(errs, err)
+	}
+
+	if o.Rate < 0 || o.Burst < 0 {
+		errs = append(errs, fmt.Errorf("rate-limit.rate and rate-limit.burst can not be negative"))
+	}
+
+	if o.Period <= 0 {
+		errs = append(errs, fmt.Errorf("rate-limit.period must be positive"))
+	}
+
+	if o.AuthFailureRate < 0 {
+		errs = append(errs, fmt.Errorf("rate-limit.auth-failure-rate can not be negative"))
+	}
+
+	if o.AuthFailurePeriod <= 0 {
+		errs = append(errs, fmt.Errorf("rate-limit.auth-failure-period must be positive"))
+	}
+
+	routes := make(map[string]bool, len(o.Routes))
+
+	for _, route := range o.Routes {
+		name := strings.TrimSpace(strings.ToUpper(route.Method) + " " + route.Path)
+
+		if !strings.HasPrefix(route.Path, "/") {
+			errs = append(errs, fmt.Errorf("rate-limit.routes: path of %q must start with /", name))
+		}
+
+		if routes[name] {
+			errs = append(errs, fmt.Errorf("rate-limit.routes: %q is duplicated", name))
+		}
+
+		routes[name] = true
+
+		if route.Key != "" {
+			if err := validateRateLimitKey(route.Key); err != nil {
+				errs = append(errs, fmt.Errorf("rate-limit.routes: %q: %w", name, err))
+			}
+		}
+
+		if route.Rate < 0 || route.Burst < 0 || route.Period < 0 {
+			errs = append(errs, fmt.Errorf("rate-limit.routes: rate, burst and period of %q can not be negative", name))
+		}
+	}
+
+	return errs
+}
+
+func validateRateLimitKey(key string) error {
+	switch key {
+	case ratelimit.KeyIP, ratelimit.KeyUsername, ratelimit.KeyAPIKey:
+		return nil
+	default:
+		return fmt.Errorf("unsupported rate limit key: %s", key)
+	}
+}
+
+// AddFlags adds flags related to rate limiting for a specific APIServer to the specified FlagSet.
+func (o *RateLimitOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.Backend, "rate-limit.backend", o.Backend, ""+
+		"Where the requests are counted, supported values: memory, redis. The limits are enforced "+
+		"per replica by memory, and shared by the replicas by redis, see the --redis.* flags.")
+
+	fs.StringVar(&o.Key, "rate-limit.key", o.Key, ""+
+		"How the clients are identified, supported values: ip, username, api-key. "+
+		"The anonymous clients are always identified by their IP addresses.")
+
+	fs.IntVar(&o.Rate, "rate-limit.rate", o.Rate, ""+
+		"The number of the requests a client is allowed per --rate-limit.period. 0 means no limit.")
+
+	fs.DurationVar(&o.Period, "rate-limit.period", o.Period, "The period of --rate-limit.rate.")
+
+	fs.IntVar(&o.Burst, "rate-limit.burst", o.Burst, ""+
+		"The max number of the requests a client is allowed at once. 0 means --rate-limit.rate.")
+
+	fs.IntVar(&o.AuthFailureRate, "rate-limit.auth-failure-rate", o.AuthFailureRate, ""+
+		"The number of the failed authentications an IP address is allowed per --rate-limit.auth-failure-period, "+
+		"the further requests are rejected before authentication. 0 means no limit.")
+
+	fs.DurationVar(&o.AuthFailurePeriod, "rate-limit.auth-failure-period", o.AuthFailurePeriod, ""+
+		"The period of --rate-limit.auth-failure-rate.")
+}
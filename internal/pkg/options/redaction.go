@@ -0,0 +1,73 @@
+package options
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+
+	"gobackend/internal/pkg/redact"
+	"gobackend/internal/pkg/server"
+)
+
+// RedactionOptions defines options for redacting the sensitive data in the operation logs,
+// the access logs and the dumps.
+type RedactionOptions struct {
+	JSONPaths   []string `json:"json-paths"    mapstructure:"json-paths"`
+	Headers     []string `json:"headers"       mapstructure:"headers"`
+	MaxBodySize int      `json:"max-body-size" mapstructure:"max-body-size"`
+}
+
+// NewRedactionOptions creates a RedactionOptions object with default parameters.
+func NewRedactionOptions() *RedactionOptions {
+	return &RedactionOptions{
+		JSONPaths:   redact.DefaultJSONPaths,
+		Headers:     redact.DefaultHeaders,
+		MaxBodySize: redact.DefaultMaxBodySize,
+	}
+}
+
+// ApplyTo applies the run options to the method receiver and returns self.
+func (o *RedactionOptions) ApplyTo(c *server.Config) error {
+	redactor, err := redact.New(o.JSONPaths, o.Headers, o.MaxBodySize)
+	if err != nil {
+		return err
+	}
+
+	c.Redactor = redactor
+
+	return nil
+}
+
+// Validate verifies flags passed to RedactionOptions.
+func (o *RedactionOptions) Validate() []error {
+	if _, err := redact.New(o.JSONPaths, o.Headers, o.MaxBodySize); err != nil {
+		return []error{fmt.Errorf("invalid redaction options: %w", err)}
+	}
+
+	return nil
+}
+
+// AddFlags adds flags related to redaction for a specific APIServer to the specified FlagSet.
+func (o *RedactionOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringSliceVar(
+		&o.JSONPaths,
+		"redaction.json-paths",
+		o.JSONPaths,
+		"The JSON paths of the values redacted in the bodies, query strings and forms, e.g. $..password, "+
+			"$.items[*].secret_key.",
+	)
+
+	fs.StringSliceVar(
+		&o.Headers,
+		"redaction.headers",
+		o.Headers,
+		"The names of the headers redacted in the dumps.",
+	)
+
+	fs.IntVar(
+		&o.MaxBodySize,
+		"redaction.max-body-size",
+		o.MaxBodySize,
+		"The bodies in the operation logs and the dumps are truncated to the number of bytes. 0 means no limit.",
+	)
+}
@@ -1,6 +1,13 @@
 package options
 
-import "github.com/spf13/pflag"
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/spf13/pflag"
+)
 
 // RedisOptions defines options for redis cluster.
 type RedisOptions struct {
@@ -17,6 +24,8 @@ type RedisOptions struct {
 	EnableCluster         bool     `json:"enable-cluster"           mapstructure:"enable-cluster"`
 	UseSSL                bool     `json:"use-ssl"                  mapstructure:"use-ssl"`
 	SSLInsecureSkipVerify bool     `json:"ssl-insecure-skip-verify" mapstructure:"ssl-insecure-skip-verify"`
+	// CacheTTL is the time to live of the entries of the store cache.
+	CacheTTL time.Duration `json:"cache-ttl" mapstructure:"cache-ttl"`
 }
 
 // NewRedisOptions create a `zero` value instance.
@@ -35,6 +44,7 @@ func NewRedisOptions() *RedisOptions {
 		EnableCluster:         false,
 		UseSSL:                false,
 		SSLInsecureSkipVerify: false,
+		CacheTTL:              5 * time.Minute,
 	}
 }
 
@@ -42,9 +52,44 @@ func NewRedisOptions() *RedisOptions {
 func (o *RedisOptions) Validate() []error {
 	errs := []error{}
 
+	if len(o.Addrs) == 0 && (o.Port <= 0 || o.Port > 65535) {
+		errs = append(errs, fmt.Errorf("--redis.port %v must be between 1 and 65535, inclusive", o.Port))
+	}
+
+	if o.EnableCluster && o.Database != 0 {
+		errs = append(errs, fmt.Errorf("--redis.database must be 0 when --redis.enable-cluster is true"))
+	}
+
+	if o.EnableCluster && o.MasterName != "" {
+		errs = append(errs, fmt.Errorf("--redis.master-name can not be used with --redis.enable-cluster"))
+	}
+
+	if o.MaxIdle < 0 || o.MaxActive < 0 {
+		errs = append(errs, fmt.Errorf(
+			"--redis.optimisation-max-idle and --redis.optimisation-max-active can not be negative",
+		))
+	}
+
+	if o.Timeout < 0 {
+		errs = append(errs, fmt.Errorf("--redis.timeout %v can not be negative", o.Timeout))
+	}
+
+	if o.CacheTTL <= 0 {
+		errs = append(errs, fmt.Errorf("--redis.cache-ttl %v must be greater than 0", o.CacheTTL))
+	}
+
 	return errs
 }
 
+// Addresses returns the addresses of the redis servers, Addrs takes precedence over Host and Port.
+func (o *RedisOptions) Addresses() []string {
+	if len(o.Addrs) > 0 {
+		return o.Addrs
+	}
+
+	return []string{net.JoinHostPort(o.Host, strconv.Itoa(o.Port))}
+}
+
 // AddFlags adds flags related to redis storage for a specific APIServer to the specified FlagSet.
 func (o *RedisOptions) AddFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&o.Host, "redis.host", o.Host, "Hostname of your Redis server.")
@@ -79,4 +124,7 @@ func (o *RedisOptions) AddFlags(fs *pflag.FlagSet) {
 
 	fs.BoolVar(&o.SSLInsecureSkipVerify, "redis.ssl-insecure-skip-verify", o.SSLInsecureSkipVerify, ""+
 		"Allows usage of self-signed certificates when connecting to an encrypted Redis database.")
+
+	fs.DurationVar(&o.CacheTTL, "redis.cache-ttl", o.CacheTTL, ""+
+		"The time to live of the entries of the store cache, which is enabled by --feature.store-cache.")
 }
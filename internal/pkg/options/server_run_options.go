@@ -2,6 +2,7 @@ package options
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/spf13/pflag"
 
@@ -11,9 +12,12 @@ import (
 
 // ServerRunOptions contains the options while running a generic api server.
 type ServerRunOptions struct {
-	Mode        string   `json:"mode"        mapstructure:"mode"`
-	Healthz     bool     `json:"healthz"     mapstructure:"healthz"`
-	Middlewares []string `json:"middlewares" mapstructure:"middlewares"`
+	Mode           string        `json:"mode"            mapstructure:"mode"`
+	Healthz        bool          `json:"healthz"         mapstructure:"healthz"`
+	MaxPingCount   int           `json:"max-ping-count"  mapstructure:"max-ping-count"`
+	ShutdownDelay  time.Duration `json:"shutdown-delay"  mapstructure:"shutdown-delay"`
+	Middlewares    []string      `json:"middlewares"     mapstructure:"middlewares"`
+	RequestTimeout time.Duration `json:"request-timeout" mapstructure:"request-timeout"`
 }
 
 // NewServerRunOptions creates a new ServerRunOptions object with default parameters.
@@ -21,9 +25,12 @@ func NewServerRunOptions() *ServerRunOptions {
 	defaults := server.NewConfig()
 
 	return &ServerRunOptions{
-		Mode:        defaults.Mode,
-		Healthz:     defaults.Healthz,
-		Middlewares: defaults.Middlewares,
+		Mode:           defaults.Mode,
+		Healthz:        defaults.Healthz,
+		MaxPingCount:   defaults.MaxPingCount,
+		ShutdownDelay:  defaults.ShutdownDelay,
+		Middlewares:    defaults.Middlewares,
+		RequestTimeout: defaults.RequestTimeout,
 	}
 }
 
@@ -31,7 +38,10 @@ func NewServerRunOptions() *ServerRunOptions {
 func (s *ServerRunOptions) ApplyTo(c *server.Config) error {
 	c.Mode = s.Mode
 	c.Healthz = s.Healthz
+	c.MaxPingCount = s.MaxPingCount
+	c.ShutdownDelay = s.ShutdownDelay
 	c.Middlewares = s.Middlewares
+	c.RequestTimeout = s.RequestTimeout
 
 	return nil
 }
@@ -45,6 +55,18 @@ func (s *ServerRunOptions) Validate() (errs []error) {
 		))
 	}
 
+	if s.MaxPingCount < 0 {
+		errs = append(errs, fmt.Errorf("server.max-ping-count must be greater than or equal to 0, got %d", s.MaxPingCount))
+	}
+
+	if s.ShutdownDelay < 0 {
+		errs = append(errs, fmt.Errorf("server.shutdown-delay must be greater than or equal to 0, got %s", s.ShutdownDelay))
+	}
+
+	if s.RequestTimeout < 0 {
+		errs = append(errs, fmt.Errorf("server.request-timeout must be greater than or equal to 0, got %s", s.RequestTimeout))
+	}
+
 	var availableMiddlewares, invalidMiddlewares []string
 
 	for _, m := range s.Middlewares {
@@ -76,8 +98,19 @@ func (s *ServerRunOptions) AddFlags(fs *pflag.FlagSet) {
 		"Start the server in a specified server mode. Supported server mode: debug, test, release.")
 
 	fs.BoolVar(&s.Healthz, "server.healthz", s.Healthz, ""+
-		"Add self readiness check and install /healthz router.")
+		"Add self readiness check and install /healthz, /livez and /readyz routers.")
+
+	fs.IntVar(&s.MaxPingCount, "server.max-ping-count", s.MaxPingCount, ""+
+		"The number of the readiness checks a second apart after the server starts, the server exits "+
+		"if none of them passes. Set to 0 to disable the checks.")
+
+	fs.DurationVar(&s.ShutdownDelay, "server.shutdown-delay", s.ShutdownDelay, ""+
+		"How long the server keeps serving after /readyz fails on shutdown, "+
+		"so that the load balancers stop sending the requests to it.")
 
 	fs.StringSliceVar(&s.Middlewares, "server.middlewares", s.Middlewares, ""+
 		"List of allowed middlewares for server, comma separated. If this list is empty default middlewares will be used.")
+
+	fs.DurationVar(&s.RequestTimeout, "server.request-timeout", s.RequestTimeout, ""+
+		"The deadline of a request, it also cancels the database queries of the request. Set to 0 to disable it.")
 }
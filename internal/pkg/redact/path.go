@@ -0,0 +1,131 @@
+package redact
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// wildcard matches any key of an object or any element of an array.
+const wildcard = "*"
+
+// step is a step of a JSON path.
+type step struct {
+	// key is the name of an object member, the index of an array element or wildcard.
+	key string
+
+	// descend makes the step match the values at any depth, i.e. `..` of JSON path.
+	descend bool
+}
+
+// path is a parsed JSON path.
+type path []step
+
+// parsePath parses a JSON path in a subset of the JSONPath syntax: `$` is the root,
+// `.name` or `['name']` is a member of an object, `[0]` is an element of an array,
+// `*` or `[*]` matches any member or element, and `..name` matches the members at any depth,
+// e.g. `$.metadata.name`, `$.items[*].password`, `$..token`.
+func parsePath(s string) (path, error) {
+	if !strings.HasPrefix(s, "$") {
+		return nil, fmt.Errorf("json path %q must start with $", s)
+	}
+
+	var p path
+
+	rest := s[1:]
+	for rest != "" {
+		var st step
+
+		switch {
+		case strings.HasPrefix(rest, ".."):
+			st.descend = true
+			rest = rest[2:]
+		case strings.HasPrefix(rest, "."):
+			rest = rest[1:]
+		case strings.HasPrefix(rest, "["):
+		default:
+			return nil, fmt.Errorf("json path %q: unexpected %q", s, rest)
+		}
+
+		if strings.HasPrefix(rest, "[") {
+			end := strings.Index(rest, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("json path %q: unterminated [", s)
+			}
+
+			st.key = strings.Trim(rest[1:end], `'"`)
+			if st.key != wildcard && !isQuoted(rest[1:end]) {
+				if _, err := strconv.Atoi(st.key); err != nil {
+					return nil, fmt.Errorf("json path %q: invalid index %q", s, rest[1:end])
+				}
+			}
+
+			rest = rest[end+1:]
+		} else {
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+
+			st.key, rest = rest[:end], rest[end:]
+		}
+
+		if st.key == "" {
+			return nil, fmt.Errorf("json path %q: empty name", s)
+		}
+
+		p = append(p, st)
+	}
+
+	if len(p) == 0 {
+		return nil, fmt.Errorf("json path %q matches the whole document", s)
+	}
+
+	return p, nil
+}
+
+func isQuoted(s string) bool {
+	return len(s) >= 2 && (s[0] == '\'' || s[0] == '"') && s[len(s)-1] == s[0]
+}
+
+// apply replaces the values matching the path in v, which is decoded from JSON,
+// and returns the new value and whether anything is replaced.
+func (p path) apply(v interface{}) (interface{}, bool) {
+	if len(p) == 0 {
+		return Redacted, true
+	}
+
+	st, changed := p[0], false
+
+	visit := func(key string, child interface{}) interface{} {
+		if st.key == wildcard || st.key == key {
+			var ok bool
+			if child, ok = p[1:].apply(child); ok {
+				changed = true
+			}
+		}
+
+		// The rest of the path may match again deeper.
+		if st.descend {
+			var ok bool
+			if child, ok = p.apply(child); ok {
+				changed = true
+			}
+		}
+
+		return child
+	}
+
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for key, child := range t {
+			t[key] = visit(key, child)
+		}
+	case []interface{}:
+		for i, child := range t {
+			t[i] = visit(strconv.Itoa(i), child)
+		}
+	}
+
+	return v, changed
+}
@@ -0,0 +1,250 @@
+// Package redact removes the sensitive data, e.g. passwords and tokens, from the request and
+// response data before they are logged or stored.
+package redact
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+
+	"gobackend/pkg/json"
+)
+
+// Redacted replaces the sensitive values.
+const Redacted = "[REDACTED]"
+
+// DefaultJSONPaths are the JSON paths redacted by default.
+var DefaultJSONPaths = []string{"$..password", "$..token", "$..secret_key"}
+
+// DefaultHeaders are the header names redacted by default.
+var DefaultHeaders = []string{"Authorization", "Cookie", "Set-Cookie"}
+
+// DefaultMaxBodySize is the default maximum size of a body in bytes, the rest is truncated.
+const DefaultMaxBodySize = 4096
+
+var defaultRedactor, _ = New(DefaultJSONPaths, DefaultHeaders, DefaultMaxBodySize)
+
+// Default returns the redactor with the default rules.
+func Default() *Redactor {
+	return defaultRedactor
+}
+
+// Redactor replaces the sensitive values in JSON documents, form values, query strings and headers,
+// and truncates the large bodies. It is safe for concurrent use.
+type Redactor struct {
+	paths       []path
+	headers     map[string]struct{}
+	maxBodySize int
+}
+
+// New creates a redactor which redacts the values matching the JSON paths and the headers,
+// and truncates the bodies larger than maxBodySize bytes, 0 means no limit.
+// The query strings and the form values are redacted as JSON objects of the names to the values.
+func New(jsonPaths []string, headers []string, maxBodySize int) (*Redactor, error) {
+	if maxBodySize < 0 {
+		return nil, fmt.Errorf("max body size can not be negative")
+	}
+
+	r := &Redactor{
+		paths:       make([]path, 0, len(jsonPaths)),
+		headers:     make(map[string]struct{}, len(headers)),
+		maxBodySize: maxBodySize,
+	}
+
+	for _, s := range jsonPaths {
+		p, err := parsePath(s)
+		if err != nil {
+			return nil, err
+		}
+
+		r.paths = append(r.paths, p)
+	}
+
+	for _, h := range headers {
+		r.headers[http.CanonicalHeaderKey(h)] = struct{}{}
+	}
+
+	return r, nil
+}
+
+// Header returns a copy of the header with the sensitive values redacted.
+func (r *Redactor) Header(h http.Header) http.Header {
+	ret := make(http.Header, len(h))
+
+	for name, values := range h {
+		if _, ok := r.headers[http.CanonicalHeaderKey(name)]; ok {
+			values = make([]string, len(values))
+			for i := range values {
+				values[i] = Redacted
+			}
+		}
+
+		ret[name] = values
+	}
+
+	return ret
+}
+
+// Query returns the query string with the sensitive values redacted. The query is reformatted
+// if something is redacted or it is malformed, see parseQuery.
+func (r *Redactor) Query(rawQuery string) string {
+	if rawQuery == "" {
+		return ""
+	}
+
+	values, malformed := parseQuery(rawQuery)
+	if !r.values(values) && !malformed {
+		return rawQuery
+	}
+
+	return values.Encode()
+}
+
+// parseQuery parses the query string like url.ParseQuery, which drops the malformed pairs,
+// but whether they are sensitive is unknown, so they can not be kept as they are either.
+// The values which can not be unescaped are replaced with Redacted, and the pairs whose
+// names can not be unescaped are dropped. It returns true if any pair is malformed.
+func parseQuery(query string) (url.Values, bool) {
+	values := make(url.Values)
+	malformed := false
+
+	for _, pair := range strings.Split(query, "&") {
+		if pair == "" {
+			continue
+		}
+
+		rawName, rawValue := pair, ""
+		if i := strings.Index(pair, "="); i >= 0 {
+			rawName, rawValue = pair[:i], pair[i+1:]
+		}
+
+		name, err := url.QueryUnescape(rawName)
+		if err != nil || strings.Contains(rawName, ";") {
+			malformed = true
+
+			continue
+		}
+
+		value, err := url.QueryUnescape(rawValue)
+		if err != nil || strings.Contains(rawValue, ";") {
+			malformed = true
+			value = Redacted
+		}
+
+		values[name] = append(values[name], value)
+	}
+
+	return values, malformed
+}
+
+// Body returns the body with the sensitive values redacted if it is a JSON document or a form,
+// and truncated to the maximum body size. A body without content type is redacted if it is JSON.
+// The body is reformatted only if something is redacted.
+func (r *Redactor) Body(contentType string, body []byte) string {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+
+	switch {
+	case mediaType == "" || mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		if redacted, ok := r.json(body); ok {
+			body = redacted
+		}
+	case mediaType == "application/x-www-form-urlencoded":
+		body = []byte(r.Query(string(body)))
+	}
+
+	return r.Truncate(string(body))
+}
+
+// Truncate truncates s to the maximum body size, and notes the number of the truncated bytes.
+func (r *Redactor) Truncate(s string) string {
+	if r.maxBodySize == 0 || len(s) <= r.maxBodySize {
+		return s
+	}
+
+	end := r.maxBodySize
+	for end > 0 && !utf8.RuneStart(s[end]) {
+		end--
+	}
+
+	return fmt.Sprintf("%s...(%d bytes truncated)", s[:end], len(s)-end)
+}
+
+// json redacts a JSON document, it returns false if nothing is redacted or it is not JSON.
+func (r *Redactor) json(data []byte) ([]byte, bool) {
+	if len(r.paths) == 0 {
+		return nil, false
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var v interface{}
+	if err := decoder.Decode(&v); err != nil || decoder.More() {
+		return nil, false
+	}
+
+	v, changed := r.apply(v)
+	if !changed {
+		return nil, false
+	}
+
+	var buf bytes.Buffer
+
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+
+	if err := encoder.Encode(v); err != nil {
+		return nil, false
+	}
+
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), true
+}
+
+// values redacts the values as an object of the names to the values, it returns false
+// if nothing is redacted.
+func (r *Redactor) values(values url.Values) bool {
+	obj := make(map[string]interface{}, len(values))
+
+	for name, vs := range values {
+		arr := make([]interface{}, 0, len(vs))
+		for _, v := range vs {
+			arr = append(arr, v)
+		}
+
+		obj[name] = arr
+	}
+
+	if _, changed := r.apply(obj); !changed {
+		return false
+	}
+
+	for name, v := range obj {
+		switch t := v.(type) {
+		case string:
+			values[name] = []string{t}
+		case []interface{}:
+			for i, elem := range t {
+				values[name][i] = fmt.Sprint(elem)
+			}
+		}
+	}
+
+	return true
+}
+
+func (r *Redactor) apply(v interface{}) (interface{}, bool) {
+	changed := false
+
+	for _, p := range r.paths {
+		var ok bool
+		if v, ok = p.apply(v); ok {
+			changed = true
+		}
+	}
+
+	return v, changed
+}
@@ -0,0 +1,181 @@
+package redact
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParsePath(t *testing.T) {
+	tests := []struct {
+		path    string
+		want    path
+		wantErr bool
+	}{
+		{path: "$.password", want: path{{key: "password"}}},
+		{path: "$..token", want: path{{key: "token", descend: true}}},
+		{path: "$.items[*].secret_key", want: path{{key: "items"}, {key: "*"}, {key: "secret_key"}}},
+		{path: "$['metadata'][0]", want: path{{key: "metadata"}, {key: "0"}}},
+		{path: "password", wantErr: true},
+		{path: "$", wantErr: true},
+		{path: "$.a[b]", wantErr: true},
+		{path: "$.a[0", wantErr: true},
+		{path: "$.a..", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, err := parsePath(tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parsePath() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parsePath() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedactorBody(t *testing.T) {
+	r, err := New([]string{"$..password", "$.items[*].secret_key", "$.token"}, nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		want        string
+	}{
+		{
+			name:        "nested",
+			contentType: "application/json; charset=utf-8",
+			body:        `{"metadata":{"name":"a"},"auth":{"password":"p"},"password":"q"}`,
+			want:        `{"auth":{"password":"[REDACTED]"},"metadata":{"name":"a"},"password":"[REDACTED]"}`,
+		},
+		{
+			name:        "array",
+			contentType: "application/json",
+			body:        `{"items":[{"secret_key":"k1"},{"secret_key":"k2","n":1.50}],"token":{"a":1}}`,
+			want:        `{"items":[{"secret_key":"[REDACTED]"},{"n":1.50,"secret_key":"[REDACTED]"}],"token":"[REDACTED]"}`,
+		},
+		{
+			name:        "not matched",
+			contentType: "application/json",
+			body:        `{"name": "a", "secret_key": "k"}`,
+			want:        `{"name": "a", "secret_key": "k"}`,
+		},
+		{
+			name: "no content type",
+			body: `{"password":"p"}`,
+			want: `{"password":"[REDACTED]"}`,
+		},
+		{
+			name:        "form",
+			contentType: "application/x-www-form-urlencoded",
+			body:        "username=a&password=p&password=q",
+			want:        "password=%5BREDACTED%5D&username=a",
+		},
+		{
+			name:        "malformed form",
+			contentType: "application/x-www-form-urlencoded",
+			body:        "password=hunter2%zz&x=1",
+			want:        "password=%5BREDACTED%5D&x=1",
+		},
+		{
+			name:        "invalid json",
+			contentType: "application/json",
+			body:        `{"password":`,
+			want:        `{"password":`,
+		},
+		{
+			name:        "text",
+			contentType: "text/plain",
+			body:        `{"password":"p"}`,
+			want:        `{"password":"p"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := r.Body(tt.contentType, []byte(tt.body)); got != tt.want {
+				t.Errorf("Body() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedactorQuery(t *testing.T) {
+	r := Default()
+
+	if got, want := r.Query("token=abc&limit=1"), "limit=1&token=%5BREDACTED%5D"; got != want {
+		t.Errorf("Query() = %s, want %s", got, want)
+	}
+
+	// The query is kept as is if nothing is redacted.
+	if got, want := r.Query("limit=1&field_selector=name%3Da"), "limit=1&field_selector=name%3Da"; got != want {
+		t.Errorf("Query() = %s, want %s", got, want)
+	}
+
+	// The malformed values are redacted and the malformed names are dropped, the rest are redacted as usual.
+	malformed := []struct {
+		query string
+		want  string
+	}{
+		{"password=hunter2%zz&x=1", "password=%5BREDACTED%5D&x=1"},
+		{"x=%zz&token=abc", "token=%5BREDACTED%5D&x=%5BREDACTED%5D"},
+		{"pass%zzword=hunter2&x=1", "x=1"},
+		{"x=1;password=hunter2", "x=%5BREDACTED%5D"},
+		{"x;password=hunter2&limit=1", "limit=1"},
+	}
+
+	for _, tt := range malformed {
+		if got := r.Query(tt.query); got != tt.want {
+			t.Errorf("Query(%q) = %s, want %s", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestRedactorHeader(t *testing.T) {
+	h := http.Header{
+		"Authorization": {"Basic YTpi"},
+		"Content-Type":  {"application/json"},
+	}
+
+	got := Default().Header(h)
+
+	if got.Get("Authorization") != Redacted || got.Get("Content-Type") != "application/json" {
+		t.Errorf("Header() = %v", got)
+	}
+
+	if h.Get("Authorization") != "Basic YTpi" {
+		t.Error("Header() modified the original header")
+	}
+}
+
+func TestRedactorTruncate(t *testing.T) {
+	r, err := New(DefaultJSONPaths, nil, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := r.Body("application/json", []byte(`{"password":"p"}`)), `{"passwo...(17 bytes truncated)`; got != want {
+		t.Errorf("Body() = %s, want %s", got, want)
+	}
+
+	// A multi-byte character is not split.
+	if got := r.Truncate(strings.Repeat("é", 5)); got != "éééé...(2 bytes truncated)" {
+		t.Errorf("Truncate() = %s", got)
+	}
+
+	if got := r.Truncate("short"); got != "short" {
+		t.Errorf("Truncate() = %s, want short", got)
+	}
+
+	if _, err := New(nil, nil, -1); err == nil {
+		t.Error("New() with negative max body size error = nil")
+	}
+}
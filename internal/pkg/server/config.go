@@ -5,12 +5,17 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/spf13/viper"
 
 	"gobackend/pkg/log"
 	"gobackend/pkg/util"
+
+	"gobackend/internal/pkg/middleware"
+	"gobackend/internal/pkg/redact"
 )
 
 const (
@@ -26,12 +31,18 @@ const (
 type Config struct {
 	InsecureServing        *InsecureServingInfo
 	SecureServing          *SecureServingInfo
+	GRPCServing            *GRPCServingInfo
 	Mode                   string
 	Middlewares            []string
 	Healthz                bool
+	MaxPingCount           int
+	ShutdownDelay          time.Duration
+	RequestTimeout         time.Duration
 	EnableProfiling        bool
 	EnableMetrics          bool
 	EnableOperationLogging bool
+	Redactor               *redact.Redactor
+	Cors                   cors.Config
 }
 
 // InsecureServingInfo holds configuration of the insecure http server.
@@ -63,11 +74,16 @@ func (s *SecureServingInfo) Address() string {
 func NewConfig() *Config {
 	return &Config{
 		Healthz:                true,
+		MaxPingCount:           3,
+		ShutdownDelay:          0,
 		Mode:                   gin.ReleaseMode,
 		Middlewares:            []string{},
+		RequestTimeout:         10 * time.Second,
 		EnableProfiling:        false,
 		EnableMetrics:          true,
 		EnableOperationLogging: false,
+		Redactor:               redact.Default(),
+		Cors:                   middleware.DefaultCorsConfig(),
 	}
 }
 
@@ -86,17 +102,26 @@ func (c *Config) Complete() CompletedConfig {
 func (c CompletedConfig) NewServer() (*GenericAPIServer, error) {
 	gin.SetMode(c.Mode)
 
+	if err := middleware.SetCorsConfig(c.Cors); err != nil {
+		return nil, err
+	}
+
 	engine := gin.New()
 
 	s := &GenericAPIServer{
 		InsecureServingInfo:    c.InsecureServing,
 		SecureServingInfo:      c.SecureServing,
+		GRPCServingInfo:        c.GRPCServing,
 		mode:                   c.Mode,
 		healthz:                c.Healthz,
+		maxPingCount:           c.MaxPingCount,
+		shutdownDelay:          c.ShutdownDelay,
 		enableMetrics:          c.EnableMetrics,
 		enableProfiling:        c.EnableProfiling,
 		enableOperationLogging: c.EnableOperationLogging,
 		middlewares:            c.Middlewares,
+		requestTimeout:         c.RequestTimeout,
+		redactor:               c.Redactor,
 		Engine:                 engine,
 	}
 
@@ -4,8 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"strings"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -14,12 +16,14 @@ import (
 	"github.com/gin-gonic/gin"
 	ginprometheus "github.com/zsais/go-gin-prometheus"
 	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc"
 
 	"gobackend/pkg/core"
 	"gobackend/pkg/log"
 	"gobackend/pkg/version"
 
 	"gobackend/internal/pkg/middleware"
+	"gobackend/internal/pkg/redact"
 )
 
 // GenericAPIServer contains state for api server.
@@ -28,12 +32,18 @@ type GenericAPIServer struct {
 	middlewares []string
 	mode        string
 
+	// requestTimeout bounds the request context, 0 means no timeout.
+	requestTimeout time.Duration
+
 	// InsecureServingInfo holds configuration of the insecure HTTP server.
 	InsecureServingInfo *InsecureServingInfo
 
 	// SecureServingInfo holds configuration of the TLS server.
 	SecureServingInfo *SecureServingInfo
 
+	// GRPCServingInfo holds configuration of the gRPC server, nil means the gRPC server is disabled.
+	GRPCServingInfo *GRPCServingInfo
+
 	// ShutdownTimeout is the timeout used for server shutdown. This specifies the timeout before server
 	// gracefully shutdown returns.
 	ShutdownTimeout time.Duration
@@ -41,13 +51,35 @@ type GenericAPIServer struct {
 	// enable operation logs feature.
 	enableOperationLogging bool
 
+	// redactor redacts the sensitive data in the logs and the dumps of the requests.
+	redactor *redact.Redactor
+
+	// longRunningPaths are exempt from the request timeout and the write timeout.
+	longRunningPaths map[string]struct{}
+
 	*gin.Engine
 	healthz         bool
 	enableMetrics   bool
 	enableProfiling bool
 
+	// livezChecks and readyzChecks are run by /livez and /readyz.
+	livezChecks, readyzChecks healthChecks
+
+	// maxPingCount is the number of the readiness checks after the server starts, 0 disables them.
+	maxPingCount int
+
+	// shuttingDown is set to 1 when Close is called, it fails the readiness.
+	shuttingDown int32
+
+	// shutdownDelay is how long the server keeps serving after the readiness fails on shutdown.
+	shutdownDelay time.Duration
+
 	// wrapper for gin.Engine
 	insecureServer, secureServer *http.Server
+
+	// grpcServer serves the gRPC services beside the http servers.
+	grpcServer *grpc.Server
+	grpcAuth   GRPCAuthFunc
 }
 
 func initGenericAPIServer(s *GenericAPIServer) {
@@ -57,6 +89,8 @@ func initGenericAPIServer(s *GenericAPIServer) {
 	s.Setup()
 	s.InstallMiddlewares()
 	s.InstallAPIs()
+
+	s.grpcServer = s.newGRPCServer()
 }
 
 // Setup the api server.
@@ -68,11 +102,9 @@ func (s *GenericAPIServer) Setup() {
 
 // InstallAPIs install generic apis.
 func (s *GenericAPIServer) InstallAPIs() {
-	// install healthz handler
+	// install healthz handlers
 	if s.healthz {
-		s.GET("/healthz", func(c *gin.Context) {
-			core.WriteResponse(c, nil, map[string]string{"status": "ok"})
-		})
+		s.installHealthChecks()
 	}
 
 	// install metric handler
@@ -93,15 +125,22 @@ func (s *GenericAPIServer) InstallAPIs() {
 
 // InstallMiddlewares install generic middlewares.
 func (s *GenericAPIServer) InstallMiddlewares() {
-	log.Infof("install default middlewares: requestid, context, logger, recovery")
+	log.Infof("install default middlewares: requestid, redaction, context, logger, recovery")
 
 	s.Use(middleware.RequestID())
+	s.Use(middleware.Redaction(s.redactor))
 	s.Use(middleware.Context())
 	// NOTE: Must place before middleware.Recovery(),
 	// otherwise it will not write the access log entry when panic occurred.
 	s.Use(middleware.Logger())
 	s.Use(middleware.Recovery())
 
+	if s.requestTimeout > 0 {
+		log.Infof("install request timeout middleware: %s", s.requestTimeout)
+
+		s.Use(middleware.Timeout(s.requestTimeout, s.isLongRunning))
+	}
+
 	// Install custom middlewares.
 	for _, m := range s.middlewares {
 		mw, ok := middleware.Middlewares[m]
@@ -117,7 +156,50 @@ func (s *GenericAPIServer) InstallMiddlewares() {
 	}
 }
 
-// Run spawns the http server. It only returns when the port cannot be listened on initially.
+// AddLongRunningPaths exempts the requests of the paths from the request timeout and the write
+// timeout, e.g. the streaming downloads. The handlers should stop when the client goes away.
+// It must be called before Run.
+func (s *GenericAPIServer) AddLongRunningPaths(paths ...string) {
+	if s.longRunningPaths == nil {
+		s.longRunningPaths = make(map[string]struct{}, len(paths))
+	}
+
+	for _, path := range paths {
+		s.longRunningPaths[path] = struct{}{}
+	}
+}
+
+func (s *GenericAPIServer) isLongRunning(r *http.Request) bool {
+	_, ok := s.longRunningPaths[r.URL.Path]
+
+	return ok
+}
+
+// connKey is the context key of the connection of the request, see connContext.
+type connKey struct{}
+
+// connContext keeps the connection in the context of its requests, so that the write deadline
+// of the connection can be lifted by the handlers.
+func connContext(ctx context.Context, conn net.Conn) context.Context {
+	return context.WithValue(ctx, connKey{}, conn)
+}
+
+// ServeHTTP lifts the write deadline of the long running requests before serving the requests.
+// The deadline is set by the server after reading the request headers, and reset for every
+// request on the connection.
+func (s *GenericAPIServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.isLongRunning(r) {
+		if conn, ok := r.Context().Value(connKey{}).(net.Conn); ok {
+			if err := conn.SetWriteDeadline(time.Time{}); err != nil {
+				log.Warnf("lift the write deadline of %s failed: %s", r.URL.Path, err)
+			}
+		}
+	}
+
+	s.Engine.ServeHTTP(w, r)
+}
+
+// Run spawns the http servers and the grpc server. It only returns when the port cannot be listened on initially.
 func (s *GenericAPIServer) Run() error {
 	logOptions := log.GetOptions()
 
@@ -125,10 +207,16 @@ func (s *GenericAPIServer) Run() error {
 	insecureAddress := s.InsecureServingInfo.Address
 	secureAddress := s.SecureServingInfo.Address()
 
+	var grpcAddress string
+	if s.GRPCServingInfo != nil {
+		grpcAddress = s.GRPCServingInfo.Address()
+	}
+
 	if logOptions.Format != "json" && !logOptions.DisableColor {
 		pid = color.New(color.BgRed).Sprintf(pid)
 		insecureAddress = color.New(color.BgCyan).Sprintf(insecureAddress)
 		secureAddress = color.New(color.BgCyan).Sprintf(secureAddress)
+		grpcAddress = color.New(color.BgCyan).Sprintf(grpcAddress)
 	}
 
 	log.Infof("application pid is %s", pid)
@@ -140,6 +228,7 @@ func (s *GenericAPIServer) Run() error {
 		ReadTimeout:    10 * time.Second,
 		WriteTimeout:   10 * time.Second,
 		MaxHeaderBytes: 1 << 20,
+		ConnContext:    connContext,
 	}
 
 	s.secureServer = &http.Server{
@@ -148,6 +237,7 @@ func (s *GenericAPIServer) Run() error {
 		ReadTimeout:    10 * time.Second,
 		WriteTimeout:   10 * time.Second,
 		MaxHeaderBytes: 1 << 20,
+		ConnContext:    connContext,
 	}
 
 	var eg errgroup.Group
@@ -183,11 +273,30 @@ func (s *GenericAPIServer) Run() error {
 		return nil
 	})
 
-	// Ping the server to make sure the router is working.
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-	if s.healthz {
-		if err := s.ping(ctx); err != nil {
+	eg.Go(func() error {
+		if s.GRPCServingInfo == nil || s.GRPCServingInfo.BindPort == 0 {
+			return nil
+		}
+
+		lis, err := net.Listen("tcp", s.GRPCServingInfo.Address())
+		if err != nil {
+			log.Fatalf("listen on grpc address %s failed: %s", grpcAddress, err.Error())
+		}
+
+		log.Infof("listening on grpc address: %s", grpcAddress)
+
+		if err := s.grpcServer.Serve(lis); err != nil {
+			log.Fatal(err.Error())
+		}
+
+		log.Infof("grpc server on %s stopped", grpcAddress)
+
+		return nil
+	})
+
+	// Ping the server to make sure the router is working and the server is ready.
+	if s.healthz && s.maxPingCount > 0 {
+		if err := s.ping(); err != nil {
 			return err
 		}
 	}
@@ -199,8 +308,17 @@ func (s *GenericAPIServer) Run() error {
 	return nil
 }
 
-// Close graceful shutdown the api server.
+// Close graceful shutdown the api server. The readiness fails at once, and the server keeps
+// serving for the shutdown delay before it stops, so that the load balancers stop sending
+// the requests to it.
 func (s *GenericAPIServer) Close() {
+	atomic.StoreInt32(&s.shuttingDown, 1)
+
+	if s.healthz && s.shutdownDelay > 0 {
+		log.Infof("the readiness fails, shut down in %s", s.shutdownDelay)
+		time.Sleep(s.shutdownDelay)
+	}
+
 	// The context is used to inform the server it has 10 seconds to finish
 	// the request it is currently handling
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -213,40 +331,69 @@ func (s *GenericAPIServer) Close() {
 	if err := s.insecureServer.Shutdown(ctx); err != nil {
 		log.Warnf("shutdown insecure server failed: %s", err.Error())
 	}
+
+	// GracefulStop waits for the pending calls, stop them at the deadline.
+	stopped := make(chan struct{})
+	go func() {
+		s.grpcServer.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		log.Warnf("shutdown grpc server failed: %s", ctx.Err().Error())
+		s.grpcServer.Stop()
+	}
 }
 
-// ping pings the http server to make sure the router is working.
-func (s *GenericAPIServer) ping(ctx context.Context) error {
-	url := fmt.Sprintf("http://%s/healthz", s.InsecureServingInfo.Address)
+// ping checks the readiness of the http server until it passes, at most maxPingCount times
+// a second apart.
+func (s *GenericAPIServer) ping() error {
+	url := fmt.Sprintf("http://%s/readyz", s.InsecureServingInfo.Address)
 	if strings.Contains(s.InsecureServingInfo.Address, "0.0.0.0") {
-		url = fmt.Sprintf("http://127.0.0.1:%s/healthz", strings.Split(s.InsecureServingInfo.Address, ":")[1])
+		url = fmt.Sprintf("http://127.0.0.1:%s/readyz", strings.Split(s.InsecureServingInfo.Address, ":")[1])
 	}
 
-	for {
-		// Change NewRequest to NewRequestWithContext and pass context it
-		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-		if err != nil {
-			return err
+	var err error
+
+	for i := 0; i < s.maxPingCount; i++ {
+		if i > 0 {
+			// Sleep for a second to continue the next ping.
+			log.Infof("waiting for the router, retry in 1 second: %s", err.Error())
+			time.Sleep(1 * time.Second)
 		}
-		// Ping the server by sending a GET request to `/healthz`.
-		// nolint: gosec
-		resp, err := http.DefaultClient.Do(req)
-		if err == nil && resp.StatusCode == http.StatusOK {
-			log.Info("the router has been deployed successfully")
 
-			resp.Body.Close()
+		if err = pingOnce(url); err == nil {
+			log.Info("the router has been deployed successfully")
 
 			return nil
 		}
+	}
 
-		// Sleep for a second to continue the next ping.
-		log.Info("waiting for the router, retry in 1 second")
-		time.Sleep(1 * time.Second)
+	return fmt.Errorf("the server is not ready after %d pings: %w", s.maxPingCount, err)
+}
 
-		select {
-		case <-ctx.Done():
-			log.Fatal("can not ping http server within the specified time interval")
-		default:
-		}
+// pingOnce sends a GET request to the url, it fails if the response is not 200.
+func pingOnce(url string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	// nolint: gosec
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
 	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s responded %s", url, resp.Status)
+	}
+
+	return nil
 }
@@ -0,0 +1,50 @@
+package server
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestLongRunningWriteDeadline(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	s := &GenericAPIServer{Engine: gin.New()}
+	s.AddLongRunningPaths("/export")
+
+	slow := func(c *gin.Context) {
+		time.Sleep(100 * time.Millisecond)
+		c.String(http.StatusOK, "done")
+	}
+	s.GET("/export", slow)
+	s.GET("/list", slow)
+
+	ts := httptest.NewUnstartedServer(s)
+	ts.Config.WriteTimeout = 20 * time.Millisecond
+	ts.Config.ConnContext = connContext
+	ts.Start()
+
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/export")
+	if err != nil {
+		t.Fatalf("GET /export error = %v", err)
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	if err != nil || string(body) != "done" {
+		t.Errorf("GET /export = %q, %v, want done", body, err)
+	}
+
+	// The write timeout still applies to the other requests, the response is never received.
+	if resp, err := http.Get(ts.URL + "/list"); err == nil {
+		resp.Body.Close()
+		t.Error("GET /list error = nil, want the write timeout")
+	}
+}
@@ -0,0 +1,248 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"runtime/debug"
+	"strconv"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"gobackend/pkg/errors"
+	"gobackend/pkg/log"
+
+	"gobackend/internal/pkg/code"
+	"gobackend/internal/pkg/middleware"
+)
+
+// ErrorDomain is the domain of the error details of the gRPC statuses.
+const ErrorDomain = "gobackend"
+
+// GRPCServingInfo holds configuration of the gRPC server.
+type GRPCServingInfo struct {
+	BindAddress string
+	BindPort    int
+	MaxMsgSize  int
+}
+
+// Address join host IP address and host port number into a address string, like: 0.0.0.0:8081.
+func (s *GRPCServingInfo) Address() string {
+	return net.JoinHostPort(s.BindAddress, strconv.Itoa(s.BindPort))
+}
+
+// GRPCAuthFunc authenticates a gRPC request by its incoming metadata, and returns the username
+// of the requester.
+type GRPCAuthFunc func(ctx context.Context) (string, error)
+
+// newGRPCServer creates the gRPC server, the interceptors log the calls and convert the errors to
+// gRPC statuses, recover the panics, bound the calls with the request timeout and authenticate
+// the requesters, in that order.
+func (s *GenericAPIServer) newGRPCServer() *grpc.Server {
+	opts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(
+			s.grpcLogging,
+			grpcRecovery,
+			s.grpcTimeout,
+			s.grpcAuthenticate,
+		),
+	}
+
+	if s.GRPCServingInfo != nil && s.GRPCServingInfo.MaxMsgSize > 0 {
+		opts = append(opts,
+			grpc.MaxRecvMsgSize(s.GRPCServingInfo.MaxMsgSize),
+			grpc.MaxSendMsgSize(s.GRPCServingInfo.MaxMsgSize),
+		)
+	}
+
+	return grpc.NewServer(opts...)
+}
+
+// RegisterService registers a gRPC service and its implementation, it makes GenericAPIServer
+// a grpc.ServiceRegistrar. It must be called before Run.
+func (s *GenericAPIServer) RegisterService(desc *grpc.ServiceDesc, impl interface{}) {
+	s.grpcServer.RegisterService(desc, impl)
+}
+
+// SetGRPCAuthFunc sets the function authenticating the gRPC requests, the requests are
+// anonymous if it is not set. It must be called before Run.
+func (s *GenericAPIServer) SetGRPCAuthFunc(fn GRPCAuthFunc) {
+	s.grpcAuth = fn
+}
+
+// grpcLogging injects the request id and the context logger, logs the call and converts
+// the returned error to a gRPC status.
+func (s *GenericAPIServer) grpcLogging(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	start := time.Now()
+
+	var rid string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(middleware.XRequestIDKey); len(values) > 0 {
+			rid = values[0]
+		}
+	}
+
+	if rid == "" {
+		rid = uuid.Must(uuid.NewV4()).String()
+	}
+
+	_ = grpc.SetHeader(ctx, metadata.Pairs(middleware.XRequestIDKey, rid))
+
+	l := log.WithFields(log.String("x-request-id", rid))
+
+	// The values are keyed as in gin.Context, so that the handlers shared with gin find them.
+	ctx = context.WithValue(ctx, middleware.XRequestIDKey, rid) //nolint:staticcheck
+	ctx = context.WithValue(ctx, log.ContextLoggerName, l)      //nolint:staticcheck
+
+	resp, err := handler(ctx, req)
+	if err != nil {
+		log.C(ctx).Errorf("%#+v", err)
+
+		err = GRPCError(err)
+	}
+
+	log.C(ctx).Infof("%-13s | %12v | %s", status.Code(err), time.Since(start), info.FullMethod)
+
+	return resp, err
+}
+
+// grpcRecovery turns the panics of the handlers into internal errors.
+func grpcRecovery(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (resp interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.C(ctx).Errorf("panic recovered in %s: %v\n%s", info.FullMethod, r, debug.Stack())
+
+			err = errors.WithCode(code.ErrUnknown, fmt.Sprint(r))
+		}
+	}()
+
+	return handler(ctx, req)
+}
+
+// grpcTimeout bounds the calls without a deadline with the request timeout.
+func (s *GenericAPIServer) grpcTimeout(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	if _, ok := ctx.Deadline(); !ok && s.requestTimeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, s.requestTimeout)
+		defer cancel()
+	}
+
+	return handler(ctx, req)
+}
+
+// grpcAuthenticate authenticates the requester with grpcAuth and injects the username.
+func (s *GenericAPIServer) grpcAuthenticate(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	if s.grpcAuth == nil {
+		return handler(ctx, req)
+	}
+
+	username, err := s.grpcAuth(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	l := log.C(ctx).WithFields(log.String("username", username))
+
+	ctx = context.WithValue(ctx, middleware.UsernameKey, username) //nolint:staticcheck
+	ctx = context.WithValue(ctx, log.ContextLoggerName, l)         //nolint:staticcheck
+
+	return handler(ctx, req)
+}
+
+// GRPCError converts an error to a gRPC status error. The gRPC status of an errors.Coder error
+// is derived from its HTTP status, the message is its user facing message, and the error code
+// is in the ErrorInfo detail. The gRPC status errors and the context errors are kept as they are.
+func GRPCError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	}
+
+	coder := errors.ParseCoder(err)
+
+	st := status.New(grpcCode(coder.HTTPStatus()), coder.String())
+
+	info := &errdetails.ErrorInfo{
+		Reason: strconv.Itoa(coder.Code()),
+		Domain: ErrorDomain,
+	}
+	if coder.Reference() != "" {
+		info.Metadata = map[string]string{"reference": coder.Reference()}
+	}
+
+	if detailed, err := st.WithDetails(info); err == nil {
+		st = detailed
+	}
+
+	return st.Err()
+}
+
+// grpcCode maps an HTTP status to a gRPC code.
+func grpcCode(httpStatus int) codes.Code {
+	switch httpStatus {
+	case http.StatusOK:
+		return codes.OK
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.Aborted
+	case http.StatusPreconditionFailed:
+		return codes.FailedPrecondition
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case http.StatusNotImplemented:
+		return codes.Unimplemented
+	case http.StatusServiceUnavailable:
+		return codes.Unavailable
+	case http.StatusGatewayTimeout:
+		return codes.DeadlineExceeded
+	case http.StatusInternalServerError:
+		return codes.Internal
+	default:
+		return codes.Unknown
+	}
+}
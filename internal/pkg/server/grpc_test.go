@@ -0,0 +1,61 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"gobackend/pkg/errors"
+
+	"gobackend/internal/pkg/code"
+)
+
+func TestGRPCError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantCode codes.Code
+		// wantReason is the error code in the ErrorInfo detail, 0 means no detail.
+		wantReason int
+	}{
+		{"validation", errors.WithCode(code.ErrValidation, "bad"), codes.InvalidArgument, code.ErrValidation},
+		{"unauthenticated", errors.WithCode(code.ErrTokenInvalid, "bad"), codes.Unauthenticated, code.ErrTokenInvalid},
+		{"permission", errors.WithCode(code.ErrPermissionDenied, "no"), codes.PermissionDenied, code.ErrPermissionDenied},
+		{"not found", errors.WithCode(code.ErrUserNotFound, "no"), codes.NotFound, code.ErrUserNotFound},
+		{"conflict", errors.WithCode(code.ErrResourceConflict, "stale"), codes.Aborted, code.ErrResourceConflict},
+		{"database", errors.WithCode(code.ErrDatabase, "down"), codes.Internal, code.ErrDatabase},
+		{"no code", fmt.Errorf("boom"), codes.Internal, 1},
+		{"deadline", fmt.Errorf("query: %w", context.DeadlineExceeded), codes.DeadlineExceeded, 0},
+		{"canceled", context.Canceled, codes.Canceled, 0},
+		{"status", status.Error(codes.Unavailable, "later"), codes.Unavailable, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := status.Convert(GRPCError(tt.err))
+			if st.Code() != tt.wantCode {
+				t.Errorf("code = %s, want %s", st.Code(), tt.wantCode)
+			}
+
+			var reason int
+			for _, detail := range st.Details() {
+				if info, ok := detail.(*errdetails.ErrorInfo); ok && info.Domain == ErrorDomain {
+					reason, _ = strconv.Atoi(info.Reason)
+				}
+			}
+
+			if reason != tt.wantReason {
+				t.Errorf("reason = %d, want %d", reason, tt.wantReason)
+			}
+		})
+	}
+
+	if GRPCError(nil) != nil {
+		t.Error("GRPCError(nil) != nil")
+	}
+}
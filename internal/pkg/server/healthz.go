@@ -0,0 +1,222 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+
+	"gobackend/pkg/core"
+	"gobackend/pkg/log"
+)
+
+// HealthChecker is a named check of the server health, e.g. the connection to a dependency.
+type HealthChecker interface {
+	Name() string
+	Check(ctx context.Context) error
+}
+
+type healthCheck struct {
+	name  string
+	check func(ctx context.Context) error
+}
+
+func (c *healthCheck) Name() string {
+	return c.name
+}
+
+func (c *healthCheck) Check(ctx context.Context) error {
+	return c.check(ctx)
+}
+
+// NamedCheck returns a HealthChecker of the check function.
+func NamedCheck(name string, check func(ctx context.Context) error) HealthChecker {
+	return &healthCheck{name: name, check: check}
+}
+
+// PingHealthCheck always passes, it tells the router is working.
+var PingHealthCheck = NamedCheck("ping", func(context.Context) error {
+	return nil
+})
+
+// healthChecks holds the checks run by a health endpoint, the checks may be added while serving.
+type healthChecks struct {
+	lock   sync.RWMutex
+	checks []HealthChecker
+}
+
+func (h *healthChecks) add(checks ...HealthChecker) error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	for _, check := range checks {
+		for _, added := range h.checks {
+			if added.Name() == check.Name() {
+				return fmt.Errorf("health check %s is already added", check.Name())
+			}
+		}
+
+		h.checks = append(h.checks, check)
+	}
+
+	return nil
+}
+
+func (h *healthChecks) list() []HealthChecker {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+
+	return append([]HealthChecker(nil), h.checks...)
+}
+
+// handler serves the checks as the endpoint, e.g. livez. All the checks are run unless the names
+// are excluded by the exclude query parameters, or a single one is run if the check path parameter
+// is set. The reasons of the failed checks are logged but not written in the responses, which are
+// served to everyone. The response lists the results of the checks if any one fails or the verbose
+// query parameter is set, otherwise it is "ok".
+func (h *healthChecks) handler(endpoint string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		checks := h.list()
+
+		if name := c.Param("check"); name != "" {
+			checks = findHealthCheck(checks, name)
+			if checks == nil {
+				c.String(http.StatusNotFound, "%s check %s not found\n", endpoint, name)
+
+				return
+			}
+		}
+
+		excluded := make(map[string]struct{})
+		for _, names := range c.QueryArray("exclude") {
+			for _, name := range strings.Split(names, ",") {
+				excluded[strings.TrimSpace(name)] = struct{}{}
+			}
+		}
+
+		var out bytes.Buffer
+
+		failed := false
+
+		for _, check := range checks {
+			name := check.Name()
+
+			if _, ok := excluded[name]; ok {
+				delete(excluded, name)
+				fmt.Fprintf(&out, "[+]%s excluded: ok\n", name)
+
+				continue
+			}
+
+			if err := check.Check(c.Request.Context()); err != nil {
+				log.C(c).Warnf("%s check %s failed: %s", endpoint, name, err.Error())
+				fmt.Fprintf(&out, "[-]%s failed: reason withheld\n", name)
+
+				failed = true
+
+				continue
+			}
+
+			fmt.Fprintf(&out, "[+]%s ok\n", name)
+		}
+
+		if len(excluded) > 0 {
+			unknown := make([]string, 0, len(excluded))
+			for name := range excluded {
+				unknown = append(unknown, name)
+			}
+
+			sort.Strings(unknown)
+			fmt.Fprintf(&out, "warn: some health checks cannot be excluded: no matches for %s\n", strings.Join(unknown, ", "))
+		}
+
+		if failed {
+			c.String(http.StatusServiceUnavailable, "%s%s check failed\n", out.String(), endpoint)
+
+			return
+		}
+
+		if _, ok := c.GetQuery("verbose"); !ok {
+			c.String(http.StatusOK, "ok")
+
+			return
+		}
+
+		c.String(http.StatusOK, "%s%s check passed\n", out.String(), endpoint)
+	}
+}
+
+// healthz serves the checks as /healthz. The response is the JSON written before /livez and /readyz
+// were added, which the existing probes expect.
+func (h *healthChecks) healthz() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		for _, check := range h.list() {
+			if err := check.Check(c.Request.Context()); err != nil {
+				log.C(c).Warnf("healthz check %s failed: %s", check.Name(), err.Error())
+				c.JSON(http.StatusServiceUnavailable, map[string]string{"status": "failed"})
+
+				return
+			}
+		}
+
+		core.WriteResponse(c, nil, map[string]string{"status": "ok"})
+	}
+}
+
+func findHealthCheck(checks []HealthChecker, name string) []HealthChecker {
+	for _, check := range checks {
+		if check.Name() == name {
+			return []HealthChecker{check}
+		}
+	}
+
+	return nil
+}
+
+// AddLivezChecks adds the checks to both /livez and /readyz, the server is restarted when they fail.
+// They should only fail if the server can not recover by itself, e.g. a deadlock.
+func (s *GenericAPIServer) AddLivezChecks(checks ...HealthChecker) error {
+	if err := s.livezChecks.add(checks...); err != nil {
+		return err
+	}
+
+	return s.readyzChecks.add(checks...)
+}
+
+// AddReadyzChecks adds the checks to /readyz, the server does not receive the traffic while they fail,
+// e.g. the checks of the dependencies.
+func (s *GenericAPIServer) AddReadyzChecks(checks ...HealthChecker) error {
+	return s.readyzChecks.add(checks...)
+}
+
+// installHealthChecks installs /livez and /readyz, and /healthz which is kept for the existing probes
+// and runs the checks of /livez in the JSON format.
+func (s *GenericAPIServer) installHealthChecks() {
+	s.livezChecks.checks = []HealthChecker{PingHealthCheck}
+
+	// The readiness fails as soon as the shutdown begins, see Close.
+	s.readyzChecks.checks = []HealthChecker{PingHealthCheck, NamedCheck("shutdown", s.checkShutdown)}
+
+	livez := s.livezChecks.handler("livez")
+	readyz := s.readyzChecks.handler("readyz")
+
+	s.GET("/healthz", s.livezChecks.healthz())
+	s.GET("/livez", livez)
+	s.GET("/livez/:check", livez)
+	s.GET("/readyz", readyz)
+	s.GET("/readyz/:check", readyz)
+}
+
+func (s *GenericAPIServer) checkShutdown(context.Context) error {
+	if atomic.LoadInt32(&s.shuttingDown) == 1 {
+		return fmt.Errorf("the server is shutting down")
+	}
+
+	return nil
+}
@@ -0,0 +1,122 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newHealthzServer(t *testing.T, readyz ...HealthChecker) *GenericAPIServer {
+	t.Helper()
+
+	gin.SetMode(gin.TestMode)
+
+	s := &GenericAPIServer{Engine: gin.New(), healthz: true}
+	s.installHealthChecks()
+
+	if err := s.AddReadyzChecks(readyz...); err != nil {
+		t.Fatalf("AddReadyzChecks() error = %v", err)
+	}
+
+	return s
+}
+
+func get(s *GenericAPIServer, target string) (int, string) {
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+
+	return w.Code, w.Body.String()
+}
+
+func TestHealthChecks(t *testing.T) {
+	var dbErr error
+
+	s := newHealthzServer(t, NamedCheck("db", func(context.Context) error { return dbErr }))
+
+	tests := []struct {
+		name     string
+		target   string
+		dbErr    error
+		wantCode int
+		wantBody string
+	}{
+		{"livez", "/livez", nil, http.StatusOK, "ok"},
+		{"healthz", "/healthz", nil, http.StatusOK, `{"status":"ok"}`},
+		{"readyz", "/readyz", nil, http.StatusOK, "ok"},
+		{
+			"verbose", "/readyz?verbose", nil, http.StatusOK,
+			"[+]ping ok\n[+]shutdown ok\n[+]db ok\nreadyz check passed\n",
+		},
+		{
+			"failed", "/readyz", fmt.Errorf("connection refused"), http.StatusServiceUnavailable,
+			"[+]ping ok\n[+]shutdown ok\n[-]db failed: reason withheld\nreadyz check failed\n",
+		},
+		{"livez ignores readyz checks", "/livez", fmt.Errorf("connection refused"), http.StatusOK, "ok"},
+		{
+			"exclude", "/readyz?verbose&exclude=db,shutdown&exclude=cache", fmt.Errorf("connection refused"), http.StatusOK,
+			"[+]ping ok\n[+]shutdown excluded: ok\n[+]db excluded: ok\n" +
+				"warn: some health checks cannot be excluded: no matches for cache\nreadyz check passed\n",
+		},
+		{
+			"single check", "/readyz/db", fmt.Errorf("connection refused"), http.StatusServiceUnavailable,
+			"[-]db failed: reason withheld\nreadyz check failed\n",
+		},
+		{"unknown check", "/livez/db", nil, http.StatusNotFound, "livez check db not found\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dbErr = tt.dbErr
+
+			code, body := get(s, tt.target)
+			if code != tt.wantCode || body != tt.wantBody {
+				t.Errorf("GET %s = %d %q, want %d %q", tt.target, code, body, tt.wantCode, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestHealthChecksShutdown(t *testing.T) {
+	s := newHealthzServer(t)
+	s.insecureServer = &http.Server{}
+	s.secureServer = &http.Server{}
+	s.grpcServer = s.newGRPCServer()
+
+	s.Close()
+
+	if code, body := get(s, "/readyz?verbose"); code != http.StatusServiceUnavailable ||
+		!strings.Contains(body, "[-]shutdown failed") {
+		t.Errorf("GET /readyz after Close() = %d %q, want the shutdown check failed", code, body)
+	}
+
+	if code, _ := get(s, "/livez"); code != http.StatusOK {
+		t.Errorf("GET /livez after Close() = %d, want %d", code, http.StatusOK)
+	}
+}
+
+func TestHealthzLivezChecks(t *testing.T) {
+	s := newHealthzServer(t)
+
+	if err := s.AddLivezChecks(NamedCheck("deadlock", func(context.Context) error {
+		return fmt.Errorf("deadlock detected")
+	})); err != nil {
+		t.Fatalf("AddLivezChecks() error = %v", err)
+	}
+
+	if code, body := get(s, "/healthz"); code != http.StatusServiceUnavailable || body != `{"status":"failed"}` {
+		t.Errorf("GET /healthz = %d %q, want the livez check failed", code, body)
+	}
+}
+
+func TestAddHealthChecksDuplicate(t *testing.T) {
+	s := newHealthzServer(t)
+
+	if err := s.AddLivezChecks(NamedCheck("ping", func(context.Context) error { return nil })); err == nil {
+		t.Error("AddLivezChecks() of a duplicate name error = nil")
+	}
+}
@@ -121,6 +121,8 @@ type App struct {
 	commands    []*Command
 	args        cobra.PositionalArgs
 	cmd         *cobra.Command
+	newOptions  func() CliOptions
+	reloadFunc  ReloadFunc
 }
 
 // New creates a new application instance based on the given application name,
@@ -143,18 +145,6 @@ func New(name string, binaryName string, opts ...Option) *App {
 
 // Run is used to launch the application.
 func (a *App) Run() {
-	if a.processLock {
-		lock, lockFile, err := processLock(a.pidDir)
-		if err != nil {
-			fmt.Printf("%v\n", err)
-
-			return
-		}
-
-		defer os.Remove(lockFile)
-		defer lock.Close()
-	}
-
 	if err := a.cmd.Execute(); err != nil {
 		fmt.Printf("%v %v\n", color.RedString("Error:"), err)
 		os.Exit(1)
@@ -185,7 +175,7 @@ func (a *App) buildCommand() {
 
 	if len(a.commands) > 0 {
 		for _, command := range a.commands {
-			cmd.AddCommand(command.cobraCommand())
+			cmd.AddCommand(command.cobraCommand(a))
 		}
 		cmd.SetHelpCommand(helpCommand(a.name))
 	}
@@ -230,6 +220,18 @@ func (a *App) buildCommand() {
 }
 
 func (a *App) runCommand(cmd *cobra.Command, args []string) error {
+	// Only the application itself holds the process lock,
+	// sub commands can run while the application is running.
+	if a.processLock {
+		lock, lockFile, err := processLock(a.pidDir)
+		if err != nil {
+			return err
+		}
+
+		defer os.Remove(lockFile)
+		defer lock.Close()
+	}
+
 	cliflag.PrintFlags(cmd.Flags())
 
 	if !a.noVersion {
@@ -237,6 +239,52 @@ func (a *App) runCommand(cmd *cobra.Command, args []string) error {
 		verflag.PrintAndExitIfRequested()
 	}
 
+	if err := a.loadOptions(cmd, a.options); err != nil {
+		return err
+	}
+	defer log.Sync()
+
+	if !a.silence {
+		printWorkingDir()
+
+		log.Infof("Starting %s ...", a.name)
+
+		if !a.noVersion {
+			log.Infof("Version: %s", version.Get().ToJSON())
+		}
+
+		if !a.noConfig {
+			if a.runModeEnv != "" {
+				log.Infof(
+					"Run environment variable: %s, value: %s",
+					a.runModeEnv,
+					os.Getenv(a.runModeEnv),
+				)
+			}
+
+			log.Infof("Config file used: %s", viper.ConfigFileUsed())
+		}
+	}
+
+	if a.reloadFunc != nil && !a.noConfig {
+		stop, err := a.watchConfig(cmd)
+		if err != nil {
+			return err
+		}
+		defer stop()
+	}
+
+	if a.runFunc != nil {
+		return a.runFunc(a.binaryName)
+	}
+
+	return nil
+}
+
+// loadOptions reads the configuration file and the flags of cmd into options,
+// initializes the logger and applies the option rules.
+// It is shared by the application and its sub commands.
+func (a *App) loadOptions(cmd *cobra.Command, options CliOptions) error {
 	parseConfigFile(a.binaryName, a.runModeEnv)
 
 	if !a.noConfig {
@@ -244,7 +292,7 @@ func (a *App) runCommand(cmd *cobra.Command, args []string) error {
 			return err
 		}
 
-		if err := viper.Unmarshal(a.options); err != nil {
+		if err := viper.Unmarshal(options); err != nil {
 			return err
 		}
 	}
@@ -267,55 +315,28 @@ func (a *App) runCommand(cmd *cobra.Command, args []string) error {
 	}
 
 	log.Init(logOptions)
-	defer log.Sync()
 
-	if a.options != nil {
-		if err := a.applyOptionRules(); err != nil {
+	if options != nil {
+		if err := a.applyOptionRules(options); err != nil {
 			return err
 		}
 	}
 
-	if !a.silence {
-		printWorkingDir()
-
-		log.Infof("Starting %s ...", a.name)
-
-		if !a.noVersion {
-			log.Infof("Version: %s", version.Get().ToJSON())
-		}
-
-		if !a.noConfig {
-			if a.runModeEnv != "" {
-				log.Infof(
-					"Run environment variable: %s, value: %s",
-					a.runModeEnv,
-					os.Getenv(a.runModeEnv),
-				)
-			}
-
-			log.Infof("Config file used: %s", viper.ConfigFileUsed())
-		}
-	}
-
-	if a.runFunc != nil {
-		return a.runFunc(a.binaryName)
-	}
-
 	return nil
 }
 
-func (a *App) applyOptionRules() error {
-	if completeableOptions, ok := a.options.(CompleteableOptions); ok {
+func (a *App) applyOptionRules(options CliOptions) error {
+	if completeableOptions, ok := options.(CompleteableOptions); ok {
 		if err := completeableOptions.Complete(); err != nil {
 			return err
 		}
 	}
 
-	if errs := a.options.Validate(); len(errs) != 0 {
+	if errs := options.Validate(); len(errs) != 0 {
 		return errors.NewAggregate(errs)
 	}
 
-	if printableOptions, ok := a.options.(PrintableOptions); ok && !a.silence {
+	if printableOptions, ok := options.(PrintableOptions); ok && !a.silence {
 		log.Infof("Config contents: %s", printableOptions.String())
 	}
 
@@ -8,6 +8,10 @@ import (
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
+
+	cliflag "gobackend/pkg/flag"
+	"gobackend/pkg/log"
+	"gobackend/pkg/term"
 )
 
 // Command is a sub command structure of a cli application.
@@ -69,7 +73,9 @@ func (c *Command) AddCommands(cmds ...*Command) {
 	c.commands = append(c.commands, cmds...)
 }
 
-func (c *Command) cobraCommand() *cobra.Command {
+// cobraCommand converts the command into a cobra command, the commands with options
+// read the configuration file of app the same way as app does.
+func (c *Command) cobraCommand(app *App) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   c.usage,
 		Short: c.desc,
@@ -80,26 +86,63 @@ func (c *Command) cobraCommand() *cobra.Command {
 
 	if len(c.commands) > 0 {
 		for _, command := range c.commands {
-			cmd.AddCommand(command.cobraCommand())
+			cmd.AddCommand(command.cobraCommand(app))
 		}
 	}
 
 	if c.runFunc != nil {
-		cmd.Run = c.runCommand
+		cmd.Run = func(cmd *cobra.Command, args []string) {
+			c.runCommand(app, cmd, args)
+		}
 	}
 
+	var namedFlagSets cliflag.NamedFlagSets
 	if c.options != nil {
-		for _, f := range c.options.Flags().FlagSets {
+		namedFlagSets = c.options.Flags()
+		for _, f := range namedFlagSets.FlagSets {
 			cmd.Flags().AddFlagSet(f)
 		}
+
+		if !app.noConfig {
+			addConfigFlag(namedFlagSets.FlagSet("global"))
+		}
 	}
 
-	addHelpCommandFlag(c.usage, cmd.Flags())
+	addHelpCommandFlag(c.usage, namedFlagSets.FlagSet("global"))
+	cmd.Flags().AddFlagSet(namedFlagSets.FlagSet("global"))
+
+	// Override the help function of the application which prints the flags of the application.
+	cmd.SetHelpFunc(func(cmd *cobra.Command, args []string) {
+		out := cmd.OutOrStdout()
+		cols, _, _ := term.TerminalSize(out)
+
+		fmt.Fprintf(out, "%s\n\nUsage:\n  %s\n", cmd.Short, cmd.UseLine())
+
+		if cmd.HasAvailableSubCommands() {
+			fmt.Fprintf(out, "\nAvailable Commands:\n")
+
+			for _, sub := range cmd.Commands() {
+				if sub.IsAvailableCommand() {
+					fmt.Fprintf(out, "  %s %s\n", sub.Name()+strings.Repeat(" ", sub.NamePadding()-len(sub.Name())), sub.Short)
+				}
+			}
+		}
+
+		cliflag.PrintSections(out, namedFlagSets, cols)
+	})
 
 	return cmd
 }
 
-func (c *Command) runCommand(cmd *cobra.Command, args []string) {
+func (c *Command) runCommand(app *App, cmd *cobra.Command, args []string) {
+	if c.options != nil {
+		if err := app.loadOptions(cmd, c.options); err != nil {
+			fmt.Printf("%v %v\n", color.RedString("Error:"), err)
+			os.Exit(1)
+		}
+		defer log.Sync()
+	}
+
 	if c.runFunc != nil {
 		if err := c.runFunc(args); err != nil {
 			fmt.Printf("%v %v\n", color.RedString("Error:"), err)
@@ -110,12 +153,21 @@ func (c *Command) runCommand(cmd *cobra.Command, args []string) {
 
 // AddCommand adds sub command to the application.
 func (a *App) AddCommand(cmd *Command) {
-	a.commands = append(a.commands, cmd)
+	a.AddCommands(cmd)
 }
 
 // AddCommands adds multiple sub commands to the application.
 func (a *App) AddCommands(cmds ...*Command) {
 	a.commands = append(a.commands, cmds...)
+
+	// The cobra command has been built by New, register the commands to it too.
+	if a.cmd != nil {
+		for _, command := range cmds {
+			a.cmd.AddCommand(command.cobraCommand(a))
+		}
+
+		a.cmd.SetHelpCommand(helpCommand(a.name))
+	}
 }
 
 // FormatBinaryName is formatted as an executable file name under different
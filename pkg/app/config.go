@@ -34,9 +34,7 @@ func addConfigFlag(fs *pflag.FlagSet) {
 }
 
 func parseConfigFile(binaryName, runModeEnv string) {
-	viper.AutomaticEnv()
-	viper.SetEnvPrefix(strings.Replace(strings.ToUpper(binaryName), "-", "_", -1))
-	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_", "-", "_"))
+	bindEnv(viper.GetViper(), binaryName)
 
 	if cfgFile != "" {
 		viper.SetConfigFile(cfgFile)
@@ -51,6 +49,14 @@ func parseConfigFile(binaryName, runModeEnv string) {
 	}
 }
 
+// bindEnv makes v read the environment variables prefixed by the binary name, e.g. the variable
+// of log.level is GOBACKEND_APISERVER_LOG_LEVEL.
+func bindEnv(v *viper.Viper, binaryName string) {
+	v.AutomaticEnv()
+	v.SetEnvPrefix(strings.Replace(strings.ToUpper(binaryName), "-", "_", -1))
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_", "-", "_"))
+}
+
 func setConfigPath(binaryName string) {
 	viper.AddConfigPath(".")
 	viper.AddConfigPath("./configs")
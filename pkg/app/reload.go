@@ -0,0 +1,157 @@
+package app
+
+import (
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"gobackend/pkg/errors"
+	"gobackend/pkg/log"
+)
+
+// reloadDelay is how long the configuration file settles before it is reloaded,
+// editors usually write a file in several steps.
+const reloadDelay = 100 * time.Millisecond
+
+// ReloadFunc applies the options reloaded from the configuration file at runtime. It returns
+// an error if the options can not be applied, e.g. they change the settings requiring a restart.
+type ReloadFunc func(options CliOptions) error
+
+// WithReload reloads the configuration file when it changes or the process receives SIGHUP.
+// The file is read into the options created by newOptions, which are completed and validated
+// before they are passed to reload. The flags set in the command line still take precedence.
+func WithReload(newOptions func() CliOptions, reload ReloadFunc) Option {
+	return func(a *App) {
+		a.newOptions = newOptions
+		a.reloadFunc = reload
+	}
+}
+
+// watchConfig reloads the configuration file on changes and SIGHUP until the returned
+// function is called. The directory of the file is watched rather than the file itself,
+// so that the file replaced by editors or updated through symlinks, e.g. kubernetes
+// config maps, is still watched.
+func (a *App) watchConfig(cmd *cobra.Command) (func(), error) {
+	file, err := filepath.Abs(viper.ConfigFileUsed())
+	if err != nil {
+		return nil, err
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := watcher.Add(filepath.Dir(file)); err != nil {
+		watcher.Close()
+
+		return nil, err
+	}
+
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+
+	done := make(chan struct{})
+
+	go func() {
+		defer watcher.Close()
+		defer signal.Stop(hup)
+
+		realFile, _ := filepath.EvalSymlinks(file)
+
+		var settled <-chan time.Time
+
+		for {
+			select {
+			case <-done:
+				return
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+
+				current, _ := filepath.EvalSymlinks(file)
+				written := filepath.Clean(event.Name) == file && event.Op&(fsnotify.Write|fsnotify.Create) != 0
+
+				if written || (current != "" && current != realFile) {
+					realFile = current
+					settled = time.After(reloadDelay)
+				}
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+
+				log.Warnf("watch configuration file %s error: %s", file, err)
+			case <-settled:
+				settled = nil
+
+				a.reload(cmd, file, "file change")
+			case <-hup:
+				a.reload(cmd, file, "SIGHUP")
+			}
+		}
+	}()
+
+	log.Infof("watching configuration file %s, it is also reloaded on SIGHUP", file)
+
+	return func() { close(done) }, nil
+}
+
+// reload reads the configuration file again and applies it with reloadFunc, the running
+// configuration is kept if it fails.
+func (a *App) reload(cmd *cobra.Command, file, reason string) {
+	log.Infof("reloading configuration file %s on %s", file, reason)
+
+	options, err := a.readOptions(cmd, file)
+	if err == nil {
+		err = a.reloadFunc(options)
+	}
+
+	if err != nil {
+		log.Errorf("reload configuration file %s failed, the running configuration is kept: %s", file, err)
+
+		return
+	}
+
+	log.Infof("configuration file %s reloaded", file)
+}
+
+// readOptions reads the configuration file, the environment variables and the flags into new
+// options the same way as loadOptions does, but the global viper is left as it is.
+func (a *App) readOptions(cmd *cobra.Command, file string) (CliOptions, error) {
+	v := viper.New()
+	bindEnv(v, a.binaryName)
+	v.SetConfigFile(file)
+
+	if err := v.ReadInConfig(); err != nil {
+		return nil, err
+	}
+
+	if err := v.BindPFlags(cmd.Flags()); err != nil {
+		return nil, err
+	}
+
+	options := a.newOptions()
+	if err := v.Unmarshal(options); err != nil {
+		return nil, err
+	}
+
+	if completeableOptions, ok := options.(CompleteableOptions); ok {
+		if err := completeableOptions.Complete(); err != nil {
+			return nil, err
+		}
+	}
+
+	if errs := options.Validate(); len(errs) != 0 {
+		return nil, errors.NewAggregate(errs)
+	}
+
+	return options, nil
+}
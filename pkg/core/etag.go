@@ -0,0 +1,49 @@
+package core
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ETag returns the entity tag of the resource version.
+func ETag(version uint64) string {
+	return strconv.Quote(strconv.FormatUint(version, 10))
+}
+
+// SetETag writes the resource version into the ETag response header.
+func SetETag(c *gin.Context, version uint64) {
+	c.Header("ETag", ETag(version))
+}
+
+// IfMatch reports whether the resource version satisfies the If-Match request header,
+// it is always satisfied if the header is not set.
+func IfMatch(c *gin.Context, version uint64) bool {
+	header := c.GetHeader("If-Match")
+
+	return header == "" || matchETag(header, version)
+}
+
+// IfNoneMatch reports whether the resource version satisfies the If-None-Match request header,
+// it is always satisfied if the header is not set.
+func IfNoneMatch(c *gin.Context, version uint64) bool {
+	header := c.GetHeader("If-None-Match")
+
+	return header == "" || !matchETag(header, version)
+}
+
+// matchETag reports whether the comma separated entity tags match the resource version,
+// `*` matches any version and weak tags are compared as strong ones.
+func matchETag(header string, version uint64) bool {
+	etag := ETag(version)
+
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || tag == etag {
+			return true
+		}
+	}
+
+	return false
+}
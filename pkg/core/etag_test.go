@@ -0,0 +1,25 @@
+package core
+
+import "testing"
+
+func TestMatchETag(t *testing.T) {
+	tests := []struct {
+		header  string
+		version uint64
+		want    bool
+	}{
+		{`"3"`, 3, true},
+		{`"3"`, 4, false},
+		{`W/"3"`, 3, true},
+		{`"1", "2", "3"`, 3, true},
+		{`"1", "2"`, 3, false},
+		{`*`, 3, true},
+		{`3`, 3, false},
+	}
+
+	for _, tt := range tests {
+		if got := matchETag(tt.header, tt.version); got != tt.want {
+			t.Errorf("matchETag(%q, %d) = %v, want %v", tt.header, tt.version, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package db
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Options defines optsions for database.
+type Options struct {
+	// Driver is the name of a registered dialect, e.g. mysql, postgres, sqlite.
+	Driver   string
+	Host     string
+	Username string
+	Password string
+	// Database is the database name, or the database file path of sqlite.
+	Database string
+	// SSLMode is the ssl mode of postgresql, see PostgreSQLSSLModes, it is disable if empty.
+	SSLMode               string
+	MaxIdleConnections    int
+	MaxOpenConnections    int
+	MaxConnectionLifetime time.Duration
+	Logger                logger.Interface
+}
+
+// DialectorFunc creates a gorm dialector with the given options.
+type DialectorFunc func(opts *Options) (gorm.Dialector, error)
+
+var (
+	dialectsMu sync.RWMutex
+	dialects   = make(map[string]DialectorFunc)
+)
+
+// Register makes a dialect available by the provided driver name.
+// If Register is called twice with the same name, the latter one wins.
+func Register(driver string, fn DialectorFunc) {
+	dialectsMu.Lock()
+	defer dialectsMu.Unlock()
+
+	dialects[driver] = fn
+}
+
+// Drivers returns a sorted list of the names of the registered drivers.
+func Drivers() []string {
+	dialectsMu.RLock()
+	defer dialectsMu.RUnlock()
+
+	list := make([]string, 0, len(dialects))
+	for name := range dialects {
+		list = append(list, name)
+	}
+
+	sort.Strings(list)
+
+	return list
+}
+
+// New create a new gorm db instance with the given options.
+func New(opts *Options) (*gorm.DB, error) {
+	dialectsMu.RLock()
+	fn, ok := dialects[opts.Driver]
+	dialectsMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown database driver %q (forgotten import?)", opts.Driver)
+	}
+
+	dialector, err := fn(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := gorm.Open(dialector, &gorm.Config{
+		Logger: opts.Logger,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+
+	// SetMaxOpenConns sets the maximum number of open connections to the database.
+	sqlDB.SetMaxOpenConns(opts.MaxOpenConnections)
+
+	// SetConnMaxLifetime sets the maximum amount of time a connection may be reused.
+	sqlDB.SetConnMaxLifetime(opts.MaxConnectionLifetime)
+
+	// SetMaxIdleConns sets the maximum number of connections in the idle connection pool.
+	sqlDB.SetMaxIdleConns(opts.MaxIdleConnections)
+
+	return db, nil
+}
@@ -0,0 +1,46 @@
+package db
+
+import (
+	"errors"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+var (
+	duplicateKeyMu    sync.RWMutex
+	duplicateKeyFuncs []func(err error) bool
+)
+
+// registerDuplicateKey adds the function reporting whether an error of a dialect is caused by
+// a violation of primary key or unique constraints, the dialects register them with themselves.
+func registerDuplicateKey(fn func(err error) bool) {
+	duplicateKeyMu.Lock()
+	defer duplicateKeyMu.Unlock()
+
+	duplicateKeyFuncs = append(duplicateKeyFuncs, fn)
+}
+
+// IsDuplicateKey returns true if the error is caused by a violation of
+// primary key or unique constraints, regardless of the driver.
+func IsDuplicateKey(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	duplicateKeyMu.RLock()
+	defer duplicateKeyMu.RUnlock()
+
+	for _, fn := range duplicateKeyFuncs {
+		if fn(err) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsNotFound returns true if the error means no record is found.
+func IsNotFound(err error) bool {
+	return errors.Is(err, gorm.ErrRecordNotFound)
+}
@@ -0,0 +1,37 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgconn"
+)
+
+func TestIsDuplicateKey(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{&mysql.MySQLError{Number: 1062, Message: "Duplicate entry"}, true},
+		{fmt.Errorf("wrapped: %w", &mysql.MySQLError{Number: 1062}), true},
+		{&mysql.MySQLError{Number: 1146, Message: "Table doesn't exist"}, false},
+		{&pgconn.PgError{Code: "23505"}, true},
+		{&pgconn.PgError{Code: "42P01"}, false},
+		{errors.New("Error 1062: Duplicate entry"), false},
+		{nil, false},
+	}
+
+	for _, tt := range tests {
+		if got := IsDuplicateKey(tt.err); got != tt.want {
+			t.Errorf("IsDuplicateKey(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestNewUnknownDriver(t *testing.T) {
+	if _, err := New(&Options{Driver: "oracle"}); err == nil {
+		t.Errorf("New() with unknown driver should fail")
+	}
+}
@@ -0,0 +1,248 @@
+// Package migrate applies ordered and versioned schema migrations to a gorm database,
+// the applied versions are recorded in a schema history table.
+package migrate
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DefaultHistoryTable is the table used to record the applied migrations.
+const DefaultHistoryTable = "schema_history"
+
+// Migration is a versioned schema change.
+// Up and Down run in a transaction together with the update of the history table,
+// notice that some databases such as mysql commit DDL statements implicitly.
+type Migration struct {
+	// Version must be unique and greater than 0, migrations are applied in ascending order of version.
+	Version     uint64
+	Description string
+	Up          func(tx *gorm.DB) error
+	Down        func(tx *gorm.DB) error
+}
+
+// History is a record of the schema history table.
+type History struct {
+	Version     uint64    `gorm:"primaryKey;autoIncrement:false;column:version"`
+	Description string    `gorm:"column:description;type:varchar(255)"`
+	AppliedAt   time.Time `gorm:"column:applied_at"`
+}
+
+// TableName maps to the schema history table name.
+func (h *History) TableName() string {
+	return DefaultHistoryTable
+}
+
+// Status describes whether a migration is applied.
+type Status struct {
+	Version     uint64     `json:"version"`
+	Description string     `json:"description"`
+	Applied     bool       `json:"applied"`
+	AppliedAt   *time.Time `json:"applied_at,omitempty"`
+}
+
+// Migrator applies migrations to a database.
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+}
+
+// New creates a migrator with the given migrations, they are sorted by version.
+func New(db *gorm.DB, migrations []Migration) (*Migrator, error) {
+	sorted := make([]Migration, len(migrations))
+	copy(sorted, migrations)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Version < sorted[j].Version
+	})
+
+	for i, m := range sorted {
+		if m.Version == 0 {
+			return nil, fmt.Errorf("migration `%s` has no version", m.Description)
+		}
+
+		if i > 0 && sorted[i-1].Version == m.Version {
+			return nil, fmt.Errorf("duplicate migration version %d", m.Version)
+		}
+
+		if m.Up == nil || m.Down == nil {
+			return nil, fmt.Errorf("migration %d must have both up and down", m.Version)
+		}
+	}
+
+	return &Migrator{
+		db:         db,
+		migrations: sorted,
+	}, nil
+}
+
+// Up applies n pending migrations in ascending order, all the pending migrations
+// are applied if n <= 0. It returns the number of applied migrations.
+func (m *Migrator) Up(n int) (int, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+
+	for _, migration := range m.migrations {
+		if n > 0 && count >= n {
+			break
+		}
+
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+
+		migration := migration
+		if err := m.db.Transaction(func(tx *gorm.DB) error {
+			if err := migration.Up(tx); err != nil {
+				return err
+			}
+
+			return tx.Create(&History{
+				Version:     migration.Version,
+				Description: migration.Description,
+				AppliedAt:   time.Now(),
+			}).Error
+		}); err != nil {
+			return count, fmt.Errorf("apply migration %d failed: %w", migration.Version, err)
+		}
+
+		count++
+	}
+
+	return count, nil
+}
+
+// Down rolls back n applied migrations in descending order, all the applied migrations
+// are rolled back if n <= 0. It returns the number of rolled back migrations.
+func (m *Migrator) Down(n int) (int, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return 0, err
+	}
+
+	versions := make([]uint64, 0, len(applied))
+	for version := range applied {
+		versions = append(versions, version)
+	}
+
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i] > versions[j]
+	})
+
+	count := 0
+
+	for _, version := range versions {
+		if n > 0 && count >= n {
+			break
+		}
+
+		migration, ok := m.find(version)
+		if !ok {
+			return count, fmt.Errorf("applied migration %d is unknown, can not roll it back", version)
+		}
+
+		if err := m.db.Transaction(func(tx *gorm.DB) error {
+			if err := migration.Down(tx); err != nil {
+				return err
+			}
+
+			return tx.Where("version = ?", version).Delete(&History{}).Error
+		}); err != nil {
+			return count, fmt.Errorf("roll back migration %d failed: %w", version, err)
+		}
+
+		count++
+	}
+
+	return count, nil
+}
+
+// Reset rolls back all the applied migrations and then applies all the migrations.
+func (m *Migrator) Reset() error {
+	if _, err := m.Down(0); err != nil {
+		return err
+	}
+
+	_, err := m.Up(0)
+
+	return err
+}
+
+// Status returns the status of all the migrations in ascending order of version,
+// the applied migrations which are unknown to the migrator are included too.
+func (m *Migrator) Status() ([]Status, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]Status, 0, len(m.migrations))
+
+	for _, migration := range m.migrations {
+		status := Status{
+			Version:     migration.Version,
+			Description: migration.Description,
+		}
+
+		if history, ok := applied[migration.Version]; ok {
+			status.Applied = true
+			status.AppliedAt = &history.AppliedAt
+
+			delete(applied, migration.Version)
+		}
+
+		list = append(list, status)
+	}
+
+	// The migrations applied by a newer version of the binary are listed as well.
+	for _, history := range applied {
+		history := history
+		list = append(list, Status{
+			Version:     history.Version,
+			Description: history.Description,
+			Applied:     true,
+			AppliedAt:   &history.AppliedAt,
+		})
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Version < list[j].Version
+	})
+
+	return list, nil
+}
+
+// applied returns the applied migrations, the history table is created if not exists.
+func (m *Migrator) applied() (map[uint64]History, error) {
+	if err := m.db.AutoMigrate(&History{}); err != nil {
+		return nil, fmt.Errorf("create schema history table failed: %w", err)
+	}
+
+	var histories []History
+	if err := m.db.Find(&histories).Error; err != nil {
+		return nil, err
+	}
+
+	applied := make(map[uint64]History, len(histories))
+	for _, history := range histories {
+		applied[history.Version] = history
+	}
+
+	return applied, nil
+}
+
+func (m *Migrator) find(version uint64) (Migration, bool) {
+	for _, migration := range m.migrations {
+		if migration.Version == version {
+			return migration, true
+		}
+	}
+
+	return Migration{}, false
+}
@@ -0,0 +1,190 @@
+//go:build cgo
+// +build cgo
+
+package migrate
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"gobackend/pkg/db"
+)
+
+type book struct {
+	ID    uint64 `gorm:"primary_key"`
+	Title string
+}
+
+type bookV2 struct {
+	ID     uint64 `gorm:"primary_key"`
+	Title  string
+	Author string
+}
+
+func (b *bookV2) TableName() string {
+	return "books"
+}
+
+func newDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := db.New(&db.Options{
+		Driver:             db.SQLite,
+		Database:           filepath.Join(t.TempDir(), "test.db"),
+		MaxIdleConnections: 1,
+		MaxOpenConnections: 1,
+		Logger:             logger.Discard,
+	})
+	if err != nil {
+		t.Fatalf("db.New() error = %v", err)
+	}
+
+	return db
+}
+
+func testMigrations() []Migration {
+	return []Migration{
+		{
+			Version:     2,
+			Description: "add books.author",
+			Up: func(tx *gorm.DB) error {
+				return tx.Migrator().AddColumn(&bookV2{}, "Author")
+			},
+			Down: func(tx *gorm.DB) error {
+				return tx.Migrator().DropColumn(&bookV2{}, "Author")
+			},
+		},
+		{
+			Version:     1,
+			Description: "create books",
+			Up: func(tx *gorm.DB) error {
+				return tx.Table("books").Migrator().CreateTable(&book{})
+			},
+			Down: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable("books")
+			},
+		},
+	}
+}
+
+func TestMigrator(t *testing.T) {
+	db := newDB(t)
+
+	m, err := New(db, testMigrations())
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	if n, err := m.Up(1); err != nil || n != 1 {
+		t.Fatalf("Up(1) = %d, %v, want 1, nil", n, err)
+	}
+
+	if !db.Migrator().HasTable("books") || db.Migrator().HasColumn(&bookV2{}, "Author") {
+		t.Fatalf("Up(1) should only apply the first migration")
+	}
+
+	if n, err := m.Up(0); err != nil || n != 1 {
+		t.Fatalf("Up(0) = %d, %v, want 1, nil", n, err)
+	}
+
+	if !db.Migrator().HasColumn(&bookV2{}, "Author") {
+		t.Fatalf("Up(0) should apply all the migrations")
+	}
+
+	status, err := m.Status()
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+
+	if len(status) != 2 || !status[0].Applied || !status[1].Applied || status[0].Version != 1 {
+		t.Fatalf("Status() = %+v", status)
+	}
+
+	if n, err := m.Down(1); err != nil || n != 1 {
+		t.Fatalf("Down(1) = %d, %v, want 1, nil", n, err)
+	}
+
+	if db.Migrator().HasColumn(&bookV2{}, "Author") || !db.Migrator().HasTable("books") {
+		t.Fatalf("Down(1) should only roll back the last migration")
+	}
+
+	if err := m.Reset(); err != nil {
+		t.Fatalf("Reset() error = %v", err)
+	}
+
+	status, err = m.Status()
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+
+	for _, s := range status {
+		if !s.Applied {
+			t.Errorf("migration %d is not applied after reset", s.Version)
+		}
+	}
+}
+
+func TestStatusUnknownMigrations(t *testing.T) {
+	db := newDB(t)
+
+	m, err := New(db, testMigrations())
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	if _, err := m.Up(0); err != nil {
+		t.Fatalf("Up(0) error = %v", err)
+	}
+
+	// The migrations applied by a newer version are not known by the migrator.
+	appliedAt := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, version := range []uint64{3, 4} {
+		history := &History{
+			Version:     version,
+			Description: "unknown",
+			AppliedAt:   appliedAt.AddDate(0, 0, int(version)),
+		}
+		if err := db.Create(history).Error; err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	status, err := m.Status()
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+
+	if len(status) != 4 {
+		t.Fatalf("Status() = %+v, want 4 migrations", status)
+	}
+
+	for _, s := range status[2:] {
+		want := appliedAt.AddDate(0, 0, int(s.Version))
+		if !s.Applied || s.AppliedAt == nil || !s.AppliedAt.Equal(want) {
+			t.Errorf("migration %d applied at %v, want %v", s.Version, s.AppliedAt, want)
+		}
+	}
+}
+
+func TestNewInvalidMigrations(t *testing.T) {
+	noop := func(tx *gorm.DB) error { return nil }
+
+	tests := []struct {
+		name       string
+		migrations []Migration
+	}{
+		{"zero version", []Migration{{Up: noop, Down: noop}}},
+		{"duplicate version", []Migration{{Version: 1, Up: noop, Down: noop}, {Version: 1, Up: noop, Down: noop}}},
+		{"missing down", []Migration{{Version: 1, Up: noop}}},
+	}
+
+	for _, tt := range tests {
+		if _, err := New(nil, tt.migrations); err == nil {
+			t.Errorf("%s: New() should fail", tt.name)
+		}
+	}
+}
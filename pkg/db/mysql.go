@@ -1,56 +1,36 @@
 package db
 
 import (
+	"errors"
 	"fmt"
-	"time"
 
+	mysqldriver "github.com/go-sql-driver/mysql"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
 )
 
-// Options defines optsions for mysql database.
-type Options struct {
-	Host                  string
-	Username              string
-	Password              string
-	Database              string
-	MaxIdleConnections    int
-	MaxOpenConnections    int
-	MaxConnectionLifetime time.Duration
-	Logger                logger.Interface
-}
-
-// New create a new gorm db instance with the given options.
-func New(opts *Options) (*gorm.DB, error) {
-	dsn := fmt.Sprintf(`%s:%s@tcp(%s)/%s?charset=utf8&parseTime=%t&loc=%s`,
-		opts.Username,
-		opts.Password,
-		opts.Host,
-		opts.Database,
-		true,
-		"Local")
-
-	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{
-		Logger: opts.Logger,
-	})
-	if err != nil {
-		return nil, err
-	}
+// MySQL is the driver name of mysql.
+const MySQL = "mysql"
 
-	sqlDB, err := db.DB()
-	if err != nil {
-		return nil, err
-	}
+// mysqlDuplicateEntry is the error number of mysql `ER_DUP_ENTRY`.
+const mysqlDuplicateEntry = 1062
 
-	// SetMaxOpenConns sets the maximum number of open connections to the database.
-	sqlDB.SetMaxOpenConns(opts.MaxOpenConnections)
+func init() {
+	Register(MySQL, func(opts *Options) (gorm.Dialector, error) {
+		dsn := fmt.Sprintf(`%s:%s@tcp(%s)/%s?charset=utf8&parseTime=%t&loc=%s`,
+			opts.Username,
+			opts.Password,
+			opts.Host,
+			opts.Database,
+			true,
+			"Local")
 
-	// SetConnMaxLifetime sets the maximum amount of time a connection may be reused.
-	sqlDB.SetConnMaxLifetime(opts.MaxConnectionLifetime)
+		return mysql.Open(dsn), nil
+	})
 
-	// SetMaxIdleConns sets the maximum number of connections in the idle connection pool.
-	sqlDB.SetMaxIdleConns(opts.MaxIdleConnections)
+	registerDuplicateKey(func(err error) bool {
+		var mysqlErr *mysqldriver.MySQLError
 
-	return db, nil
+		return errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlDuplicateEntry
+	})
 }
@@ -0,0 +1,78 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/jackc/pgconn"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// PostgreSQL is the driver name of postgresql.
+const PostgreSQL = "postgres"
+
+// defaultPostgreSQLPort is used when the host doesn't contain a port.
+const defaultPostgreSQLPort = "5432"
+
+// postgresUniqueViolation is the SQLSTATE of postgresql `unique_violation`.
+const postgresUniqueViolation = "23505"
+
+// defaultPostgreSQLSSLMode is used when the ssl mode is empty, it keeps the connections of
+// the existing deployments working.
+const defaultPostgreSQLSSLMode = "disable"
+
+// PostgreSQLSSLModes are the ssl modes supported by postgresql, see
+// https://www.postgresql.org/docs/current/libpq-ssl.html#LIBPQ-SSL-PROTECTION.
+var PostgreSQLSSLModes = []string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}
+
+// dsnValueEscaper escapes the quotes and the backslashes of the values in connection strings.
+var dsnValueEscaper = strings.NewReplacer(`\`, `\\`, `'`, `\'`)
+
+func init() {
+	Register(PostgreSQL, func(opts *Options) (gorm.Dialector, error) {
+		return postgres.Open(postgresDSN(opts)), nil
+	})
+
+	registerDuplicateKey(func(err error) bool {
+		var postgresErr *pgconn.PgError
+
+		return errors.As(err, &postgresErr) && postgresErr.Code == postgresUniqueViolation
+	})
+}
+
+// postgresDSN returns the keyword/value connection string of the options, the values are quoted
+// so that the spaces, quotes and backslashes, e.g. in passwords, are kept as they are.
+func postgresDSN(opts *Options) string {
+	host, port, err := net.SplitHostPort(opts.Host)
+	if err != nil {
+		host, port = opts.Host, defaultPostgreSQLPort
+	}
+
+	sslMode := opts.SSLMode
+	if sslMode == "" {
+		sslMode = defaultPostgreSQLSSLMode
+	}
+
+	settings := []struct {
+		keyword string
+		value   string
+	}{
+		{"host", host},
+		{"port", port},
+		{"user", opts.Username},
+		{"password", opts.Password},
+		{"dbname", opts.Database},
+		{"sslmode", sslMode},
+		{"TimeZone", "Local"},
+	}
+
+	pairs := make([]string, 0, len(settings))
+	for _, s := range settings {
+		pairs = append(pairs, fmt.Sprintf("%s='%s'", s.keyword, dsnValueEscaper.Replace(s.value)))
+	}
+
+	return strings.Join(pairs, " ")
+}
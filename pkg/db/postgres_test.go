@@ -0,0 +1,43 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/jackc/pgconn"
+)
+
+func TestPostgresDSN(t *testing.T) {
+	opts := &Options{
+		Host:     "db.example.com:6543",
+		Username: "gobackend",
+		Password: `p@ss word' sslmode=disable \`,
+		Database: "gobackend",
+		SSLMode:  "require",
+	}
+
+	config, err := pgconn.ParseConfig(postgresDSN(opts))
+	if err != nil {
+		t.Fatalf("ParseConfig() error = %v", err)
+	}
+
+	if config.Host != "db.example.com" || config.Port != 6543 || config.User != opts.Username ||
+		config.Password != opts.Password || config.Database != opts.Database {
+		t.Errorf("ParseConfig() = %s:%d user=%s password=%q dbname=%s",
+			config.Host, config.Port, config.User, config.Password, config.Database)
+	}
+
+	// The password can not turn off TLS.
+	if config.TLSConfig == nil {
+		t.Error("ParseConfig() TLSConfig = nil, want the sslmode require")
+	}
+
+	// The port and the ssl mode have the defaults.
+	config, err = pgconn.ParseConfig(postgresDSN(&Options{Host: "localhost"}))
+	if err != nil {
+		t.Fatalf("ParseConfig() error = %v", err)
+	}
+
+	if config.Port != 5432 || config.TLSConfig != nil {
+		t.Errorf("ParseConfig() port = %d, TLSConfig = %v, want 5432 and nil", config.Port, config.TLSConfig)
+	}
+}
@@ -0,0 +1,7 @@
+package db
+
+// SQLite is the driver name of sqlite, the database option is the path of the database file,
+// use `file::memory:?cache=shared` for a database which lives in memory only.
+// The driver is a cgo package, so the dialect is only available when cgo is enabled,
+// e.g. it is not in the release binaries which are built with CGO_ENABLED=0.
+const SQLite = "sqlite"
@@ -0,0 +1,29 @@
+//go:build cgo
+// +build cgo
+
+package db
+
+import (
+	"errors"
+
+	"github.com/mattn/go-sqlite3"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func init() {
+	Register(SQLite, func(opts *Options) (gorm.Dialector, error) {
+		if opts.Database == "" {
+			return nil, errors.New("the database file of sqlite is required")
+		}
+
+		return sqlite.Open(opts.Database), nil
+	})
+
+	registerDuplicateKey(func(err error) bool {
+		var sqliteErr sqlite3.Error
+
+		return errors.As(err, &sqliteErr) && (sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique ||
+			sqliteErr.ExtendedCode == sqlite3.ErrConstraintPrimaryKey)
+	})
+}
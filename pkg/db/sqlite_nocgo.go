@@ -0,0 +1,16 @@
+//go:build !cgo
+// +build !cgo
+
+package db
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+func init() {
+	Register(SQLite, func(opts *Options) (gorm.Dialector, error) {
+		return nil, errors.New("sqlite requires cgo, the binary is built with CGO_ENABLED=0")
+	})
+}
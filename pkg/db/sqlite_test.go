@@ -0,0 +1,45 @@
+//go:build cgo
+// +build cgo
+
+package db
+
+import (
+	"path/filepath"
+	"testing"
+
+	"gorm.io/gorm/logger"
+)
+
+type record struct {
+	ID   uint64 `gorm:"primary_key"`
+	Name string `gorm:"unique"`
+}
+
+func TestSQLiteErrors(t *testing.T) {
+	db, err := New(&Options{
+		Driver:             SQLite,
+		Database:           filepath.Join(t.TempDir(), "test.db"),
+		MaxIdleConnections: 1,
+		MaxOpenConnections: 1,
+		Logger:             logger.Discard,
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	if err := db.AutoMigrate(&record{}); err != nil {
+		t.Fatalf("AutoMigrate() error = %v", err)
+	}
+
+	if err := db.Create(&record{Name: "foo"}).Error; err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	if err := db.Create(&record{Name: "foo"}).Error; !IsDuplicateKey(err) {
+		t.Errorf("IsDuplicateKey(%v) = false, want true", err)
+	}
+
+	if err := db.Where("name = ?", "bar").First(&record{}).Error; !IsNotFound(err) {
+		t.Errorf("IsNotFound(%v) = false, want true", err)
+	}
+}
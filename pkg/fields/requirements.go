@@ -7,8 +7,10 @@ type Requirements []Requirement
 
 // Requirement contains a field, a value, and an operator that relates the field and value.
 // This is currently for reading internal selection information of field selector.
+// Values holds the value set of the In and NotIn operators, Value is empty for them.
 type Requirement struct {
 	Operator selection.Operator
 	Field    string
 	Value    string
+	Values   []string
 }
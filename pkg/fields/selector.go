@@ -3,7 +3,9 @@ package fields
 import (
 	"bytes"
 	"fmt"
+	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 
 	"gobackend/pkg/selection"
@@ -219,6 +221,172 @@ func (t *notHasTerm) DeepCopySelector() Selector {
 	return out
 }
 
+// compareTerm compares the field with the value, numerically if both of them are numbers,
+// otherwise lexically, e.g. RFC 3339 times in UTC.
+type compareTerm struct {
+	field string
+	op    selection.Operator
+	value string
+}
+
+func (t *compareTerm) Matches(ls Fields) bool {
+	if !ls.Has(t.field) {
+		return false
+	}
+
+	c := compare(ls.Get(t.field), t.value)
+
+	switch t.op {
+	case selection.GreaterThan:
+		return c > 0
+	case selection.GreaterThanOrEquals:
+		return c >= 0
+	case selection.LessThan:
+		return c < 0
+	case selection.LessThanOrEquals:
+		return c <= 0
+	default:
+		return false
+	}
+}
+
+func (t *compareTerm) Empty() bool {
+	return false
+}
+
+func (t *compareTerm) RequiresExactMatch(field string) (value string, found bool) {
+	return "", false
+}
+
+func (t *compareTerm) Transform(fn TransformFunc) (Selector, error) {
+	field, value, err := fn(t.field, t.value)
+	if err != nil {
+		return nil, err
+	}
+	if len(field) == 0 && len(value) == 0 {
+		return Everything(), nil
+	}
+
+	return &compareTerm{field, t.op, value}, nil
+}
+
+func (t *compareTerm) Requirements() Requirements {
+	return []Requirement{{
+		Field:    t.field,
+		Operator: t.op,
+		Value:    t.value,
+	}}
+}
+
+func (t *compareTerm) String() string {
+	return fmt.Sprintf("%v%v%v", t.field, compareOperators[t.op], EscapeValue(t.value))
+}
+
+func (t *compareTerm) DeepCopySelector() Selector {
+	if t == nil {
+		return nil
+	}
+	out := new(compareTerm)
+	*out = *t
+
+	return out
+}
+
+// compare returns an integer comparing a and b, as numbers if both of them are numbers.
+func compare(a, b string) int {
+	x, errX := strconv.ParseFloat(a, 64)
+	y, errY := strconv.ParseFloat(b, 64)
+
+	if errX != nil || errY != nil {
+		return strings.Compare(a, b)
+	}
+
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// setTerm matches the fields whose value is in (or not in for notin) the value set.
+type setTerm struct {
+	field  string
+	op     selection.Operator
+	values []string
+}
+
+func (t *setTerm) Matches(ls Fields) bool {
+	value := ls.Get(t.field)
+	for _, v := range t.values {
+		if v == value {
+			return t.op == selection.In
+		}
+	}
+
+	return t.op == selection.NotIn
+}
+
+func (t *setTerm) Empty() bool {
+	return false
+}
+
+func (t *setTerm) RequiresExactMatch(field string) (value string, found bool) {
+	if t.field == field && t.op == selection.In && len(t.values) == 1 {
+		return t.values[0], true
+	}
+
+	return "", false
+}
+
+func (t *setTerm) Transform(fn TransformFunc) (Selector, error) {
+	out := &setTerm{field: t.field, op: t.op, values: make([]string, 0, len(t.values))}
+
+	for _, v := range t.values {
+		field, value, err := fn(t.field, v)
+		if err != nil {
+			return nil, err
+		}
+		if len(field) == 0 && len(value) == 0 {
+			return Everything(), nil
+		}
+
+		out.field = field
+		out.values = append(out.values, value)
+	}
+
+	return out, nil
+}
+
+func (t *setTerm) Requirements() Requirements {
+	return []Requirement{{
+		Field:    t.field,
+		Operator: t.op,
+		Values:   t.values,
+	}}
+}
+
+func (t *setTerm) String() string {
+	values := make([]string, 0, len(t.values))
+	for _, v := range t.values {
+		values = append(values, EscapeValue(v))
+	}
+
+	return fmt.Sprintf("%v %v (%v)", t.field, t.op, strings.Join(values, ","))
+}
+
+func (t *setTerm) DeepCopySelector() Selector {
+	if t == nil {
+		return nil
+	}
+	out := &setTerm{field: t.field, op: t.op, values: make([]string, len(t.values))}
+	copy(out.values, t.values)
+
+	return out
+}
+
 type andTerm []Selector
 
 func (t andTerm) Matches(ls Fields) bool {
@@ -428,8 +596,9 @@ func ParseAndTransformSelector(selector string, fn TransformFunc) (Selector, err
 type TransformFunc func(field, value string) (newField, newValue string, err error)
 
 // splitTerms returns the comma-separated terms contained in the given fieldSelector.
-// Backslash-escaped commas are treated as data instead of delimiters,
-// and are included in the returned terms, with the leading backslash preserved.
+// Backslash-escaped commas and the commas in parentheses, e.g. the value set of `in`,
+// are treated as data instead of delimiters, and are included in the returned terms,
+// with the leading backslash preserved.
 func splitTerms(fieldSelector string) []string {
 	if len(fieldSelector) == 0 {
 		return nil
@@ -438,13 +607,18 @@ func splitTerms(fieldSelector string) []string {
 	terms := make([]string, 0, 1)
 	startIndex := 0
 	inSlash := false
+	depth := 0
 	for i, c := range fieldSelector {
 		switch {
 		case inSlash:
 			inSlash = false
 		case c == '\\':
 			inSlash = true
-		case c == ',':
+		case c == '(':
+			depth++
+		case c == ')' && depth > 0:
+			depth--
+		case c == ',' && depth == 0:
 			terms = append(terms, fieldSelector[startIndex:i])
 			startIndex = i + 1
 		}
@@ -456,15 +630,53 @@ func splitTerms(fieldSelector string) []string {
 }
 
 const (
-	notEqualOperator    = "!="
-	doubleEqualOperator = "=="
-	equalOperator       = "="
+	notEqualOperator           = "!="
+	doubleEqualOperator        = "=="
+	equalOperator              = "="
+	greaterThanOrEqualOperator = ">="
+	lessThanOrEqualOperator    = "<="
+	greaterThanOperator        = ">"
+	lessThanOperator           = "<"
 )
 
 // termOperators holds the recognized operators supported in fieldSelectors.
 // doubleEqualOperator and equal are equivalent, but doubleEqualOperator is checked first
-// to avoid leaving a leading = character on the rhs value.
-var termOperators = []string{notEqualOperator, doubleEqualOperator, equalOperator}
+// to avoid leaving a leading = character on the rhs value, so are the comparison operators.
+var termOperators = []string{
+	notEqualOperator,
+	doubleEqualOperator,
+	greaterThanOrEqualOperator,
+	lessThanOrEqualOperator,
+	equalOperator,
+	greaterThanOperator,
+	lessThanOperator,
+}
+
+// compareOperators maps the comparison operators to their strings in fieldSelectors.
+var compareOperators = map[selection.Operator]string{
+	selection.GreaterThan:         greaterThanOperator,
+	selection.GreaterThanOrEquals: greaterThanOrEqualOperator,
+	selection.LessThan:            lessThanOperator,
+	selection.LessThanOrEquals:    lessThanOrEqualOperator,
+}
+
+// setTermRegexp matches the set based terms, e.g. `http_status in (200,201)` or `name notin (a)`.
+var setTermRegexp = regexp.MustCompile(`^\s*([^\s=!<>(),]+)\s+(in|notin)\s*\((.*)\)\s*$`)
+
+// splitSetTerm returns the field, operator and the literal values of a set based term,
+// along with an indicator of whether the term is a set based term.
+func splitSetTerm(term string) (field string, op selection.Operator, values []string, ok bool) {
+	m := setTermRegexp.FindStringSubmatch(term)
+	if m == nil {
+		return "", "", nil, false
+	}
+
+	for _, v := range splitTerms(m[3]) {
+		values = append(values, strings.TrimSpace(v))
+	}
+
+	return m[1], selection.Operator(m[2]), values, true
+}
 
 // splitTerm returns the lhs, operator, and rhs parsed from the given term, along with an
 // indicator of whether the parse was successful.
@@ -492,6 +704,15 @@ func parseSelector(selector string, fn TransformFunc) (Selector, error) {
 		if part == "" {
 			continue
 		}
+		if field, op, values, ok := splitSetTerm(part); ok {
+			term, err := newSetTerm(field, op, values)
+			if err != nil {
+				return nil, fmt.Errorf("invalid selector: '%s'; %w", selector, err)
+			}
+			items = append(items, term)
+
+			continue
+		}
 		lhs, op, rhs, ok := splitTerm(part)
 		if !ok {
 			return nil, fmt.Errorf("invalid selector: '%s'; can't understand '%s'", selector, part)
@@ -507,6 +728,14 @@ func parseSelector(selector string, fn TransformFunc) (Selector, error) {
 			items = append(items, &equalTerm{field: lhs, value: unescapedRHS})
 		case equalOperator:
 			items = append(items, &hasTerm{field: lhs, value: unescapedRHS})
+		case greaterThanOperator:
+			items = append(items, &compareTerm{field: lhs, op: selection.GreaterThan, value: unescapedRHS})
+		case greaterThanOrEqualOperator:
+			items = append(items, &compareTerm{field: lhs, op: selection.GreaterThanOrEquals, value: unescapedRHS})
+		case lessThanOperator:
+			items = append(items, &compareTerm{field: lhs, op: selection.LessThan, value: unescapedRHS})
+		case lessThanOrEqualOperator:
+			items = append(items, &compareTerm{field: lhs, op: selection.LessThanOrEquals, value: unescapedRHS})
 		default:
 			return nil, fmt.Errorf("invalid selector: '%s'; can't understand '%s'", selector, part)
 		}
@@ -518,6 +747,24 @@ func parseSelector(selector string, fn TransformFunc) (Selector, error) {
 	return andTerm(items).Transform(fn)
 }
 
+// newSetTerm unescapes the values and returns a set based term.
+func newSetTerm(field string, op selection.Operator, values []string) (*setTerm, error) {
+	if len(values) == 0 {
+		return nil, fmt.Errorf("for 'in', 'notin' operators, values set can't be empty")
+	}
+
+	term := &setTerm{field: field, op: op, values: make([]string, 0, len(values))}
+	for _, v := range values {
+		value, err := UnescapeValue(v)
+		if err != nil {
+			return nil, err
+		}
+		term.values = append(term.values, value)
+	}
+
+	return term, nil
+}
+
 // OneTermEqualSelector returns an object that matches objects where one field/field equals one value.
 // Cannot return an error.
 func OneTermEqualSelector(k, v string) Selector {
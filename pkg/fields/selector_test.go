@@ -25,6 +25,9 @@ func TestSplitTerms(t *testing.T) {
 		`k=\a\b\`:      {`k=\a\b\`},         // non-escape sequences
 		`k=\`:          {`k=\`},             // orphan backslash
 
+		// Value sets
+		`a in (x,y),b=c`: {`a in (x,y)`, `b=c`},
+
 		// Multi-byte
 		`함=수,목=록`: {`함=수`, `목=록`},
 	}
@@ -121,13 +124,18 @@ func TestSelectorParse(t *testing.T) {
 		"x!=a,y=b",
 		`x=a||y\=b`,
 		`x=a\=\=b`,
+		"x in (a)",
+		"x in (a,b,c)",
+		"x notin (a),y=b",
+		"x>1,y<=2",
+		"t<2022-01-01T00:00:00Z,t>=2021-01-01T00:00:00Z",
 	}
 	testBadStrings := []string{
 		"x=a||y=b",
 		"x==a==b",
 		"x=a,b",
-		"x in (a)",
-		"x in (a,b,c)",
+		"x in ()",
+		"x in (a",
 		"x",
 	}
 	for _, test := range testGoodStrings {
@@ -198,6 +206,14 @@ func TestSelectorMatches(t *testing.T) {
 	expectNoMatch(t, "x=y", Set{"x": "z"})
 	expectNoMatch(t, "x=y,z=w", Set{"x": "w", "z": "w"})
 	expectNoMatch(t, "x!=y,z!=w", Set{"x": "z", "z": "w"})
+	expectMatch(t, "x in (y,z)", Set{"x": "z"})
+	expectNoMatch(t, "x in (y,z)", Set{"x": "w"})
+	expectMatch(t, "x notin (y,z)", Set{"x": "w"})
+	expectNoMatch(t, "x notin (y,z)", Set{"x": "y"})
+	expectMatch(t, "x>9,x<=10", Set{"x": "10"}) // compared as numbers
+	expectNoMatch(t, "x>=10", Set{"x": "9"})
+	expectMatch(t, "t>=2021-01-01T00:00:00Z", Set{"t": "2021-06-01T00:00:00Z"})
+	expectNoMatch(t, "t<2021-01-01T00:00:00Z", Set{"t": "2021-06-01T00:00:00Z"})
 
 	fieldset := Set{
 		"foo":     "bar",
@@ -0,0 +1,490 @@
+package labels
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gobackend/pkg/selection"
+	"gobackend/pkg/validation"
+)
+
+// Selector represents a label selector.
+type Selector interface {
+	// Matches returns true if this selector matches the given set of labels.
+	Matches(Labels) bool
+
+	// Empty returns true if this selector does not restrict the selection space.
+	Empty() bool
+
+	// Requirements converts this interface into Requirements to expose
+	// more detailed selection information.
+	Requirements() Requirements
+
+	// String returns a human readable string that represents this selector.
+	String() string
+}
+
+// Requirements is AND of all requirements.
+type Requirements []Requirement
+
+// Requirement contains a key, an operator and a set of values that relates the key and values.
+// Values is empty for the Exists and DoesNotExist operators, has exactly one element for
+// the Equals, DoubleEquals, NotEquals, GreaterThan and LessThan operators, and has at least
+// one element for the In and NotIn operators.
+type Requirement struct {
+	Key      string
+	Operator selection.Operator
+	Values   []string
+}
+
+// NewRequirement validates and returns a Requirement.
+// The key must be a qualified name, the values must be valid label values,
+// and they must be integers for the GreaterThan and LessThan operators.
+func NewRequirement(key string, op selection.Operator, values []string) (*Requirement, error) {
+	if errs := validation.IsQualifiedName(key); len(errs) != 0 {
+		return nil, fmt.Errorf("invalid label key %q: %s", key, strings.Join(errs, "; "))
+	}
+
+	switch op {
+	case selection.In, selection.NotIn:
+		if len(values) == 0 {
+			return nil, fmt.Errorf("for 'in', 'notin' operators, values set can't be empty")
+		}
+	case selection.Equals, selection.DoubleEquals, selection.NotEquals:
+		if len(values) != 1 {
+			return nil, fmt.Errorf("exact-match compatibility requires one single value")
+		}
+	case selection.Exists, selection.DoesNotExist:
+		if len(values) != 0 {
+			return nil, fmt.Errorf("values set must be empty for exists and does not exist")
+		}
+	case selection.GreaterThan, selection.LessThan:
+		if len(values) != 1 {
+			return nil, fmt.Errorf("for 'gt', 'lt' operators, exactly one value is required")
+		}
+
+		// The value is compared as a number, it may be negative.
+		if _, err := strconv.ParseInt(values[0], 10, 64); err != nil {
+			return nil, fmt.Errorf("for 'gt', 'lt' operators, the value must be an integer")
+		}
+
+		return &Requirement{Key: key, Operator: op, Values: []string{values[0]}}, nil
+	default:
+		return nil, fmt.Errorf("operator '%v' is not recognized", op)
+	}
+
+	for _, v := range values {
+		if errs := validation.IsValidLabelValue(v); len(errs) != 0 {
+			return nil, fmt.Errorf("invalid label value %q: %s", v, strings.Join(errs, "; "))
+		}
+	}
+
+	vals := make([]string, len(values))
+	copy(vals, values)
+	sort.Strings(vals)
+
+	return &Requirement{Key: key, Operator: op, Values: vals}, nil
+}
+
+// Matches returns true if the Requirement matches the input Labels.
+// There is a match in the following cases:
+//
+//	(1) The operator is Exists and Labels has the Requirement's key.
+//	(2) The operator is In, Labels has the Requirement's key and Labels'
+//	    value for that key is in Requirement's value set.
+//	(3) The operator is NotIn, Labels has the Requirement's key and
+//	    Labels' value for that key is not in Requirement's value set.
+//	(4) The operator is DoesNotExist or NotIn and Labels does not have the
+//	    Requirement's key.
+//	(5) The operator is GreaterThanOperator or LessThanOperator, and Labels has
+//	    the Requirement's key and the corresponding value satisfies mathematical inequality.
+func (r *Requirement) Matches(ls Labels) bool {
+	switch r.Operator {
+	case selection.In, selection.Equals, selection.DoubleEquals:
+		return ls.Has(r.Key) && r.hasValue(ls.Get(r.Key))
+	case selection.NotIn, selection.NotEquals:
+		return !ls.Has(r.Key) || !r.hasValue(ls.Get(r.Key))
+	case selection.Exists:
+		return ls.Has(r.Key)
+	case selection.DoesNotExist:
+		return !ls.Has(r.Key)
+	case selection.GreaterThan, selection.LessThan:
+		if !ls.Has(r.Key) {
+			return false
+		}
+
+		lsValue, err := strconv.ParseInt(ls.Get(r.Key), 10, 64)
+		if err != nil {
+			return false
+		}
+
+		rValue, err := strconv.ParseInt(r.Values[0], 10, 64)
+		if err != nil {
+			return false
+		}
+
+		return (r.Operator == selection.GreaterThan && lsValue > rValue) ||
+			(r.Operator == selection.LessThan && lsValue < rValue)
+	default:
+		return false
+	}
+}
+
+func (r *Requirement) hasValue(value string) bool {
+	for _, v := range r.Values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// String returns a human-readable string that represents this Requirement.
+func (r *Requirement) String() string {
+	switch r.Operator {
+	case selection.DoesNotExist:
+		return "!" + r.Key
+	case selection.Exists:
+		return r.Key
+	case selection.In, selection.NotIn:
+		return fmt.Sprintf("%s %s (%s)", r.Key, r.Operator, strings.Join(r.Values, ","))
+	case selection.GreaterThan:
+		return r.Key + ">" + r.Values[0]
+	case selection.LessThan:
+		return r.Key + "<" + r.Values[0]
+	default:
+		return r.Key + string(r.Operator) + r.Values[0]
+	}
+}
+
+type internalSelector []Requirement
+
+// Everything returns a selector that matches all labels.
+func Everything() Selector {
+	return internalSelector{}
+}
+
+func (s internalSelector) Matches(ls Labels) bool {
+	for i := range s {
+		if !s[i].Matches(ls) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (s internalSelector) Empty() bool {
+	return len(s) == 0
+}
+
+func (s internalSelector) Requirements() Requirements {
+	return Requirements(s)
+}
+
+func (s internalSelector) String() string {
+	reqs := make([]string, 0, len(s))
+	for i := range s {
+		reqs = append(reqs, s[i].String())
+	}
+
+	return strings.Join(reqs, ",")
+}
+
+// SelectorFromSet returns a Selector which will match exactly the given Set.
+// A nil or empty Set is considered equivalent to Everything().
+// The Set is not validated, the caller must make sure it is valid.
+func SelectorFromSet(ls Set) Selector {
+	requirements := make(internalSelector, 0, len(ls))
+	for key, value := range ls {
+		requirements = append(requirements, Requirement{Key: key, Operator: selection.Equals, Values: []string{value}})
+	}
+
+	sort.Slice(requirements, func(i, j int) bool {
+		return requirements[i].Key < requirements[j].Key
+	})
+
+	return requirements
+}
+
+// Parse takes a string representing a selector and returns a selector
+// object, or an error. The input will cause an error if it does not follow this form:
+//
+//	<selector-syntax>         ::= <requirement> | <requirement> "," <selector-syntax>
+//	<requirement>             ::= [!] KEY [ <set-based-restriction> | <exact-match-restriction> ]
+//	<set-based-restriction>   ::= "" | <inclusion-exclusion> <value-set>
+//	<inclusion-exclusion>     ::= <inclusion> | <exclusion>
+//	<exclusion>               ::= "notin"
+//	<inclusion>               ::= "in"
+//	<value-set>               ::= "(" <values> ")"
+//	<values>                  ::= VALUE | VALUE "," <values>
+//	<exact-match-restriction> ::= ["="|"=="|"!="|">"|"<"] VALUE
+//
+// KEY is a sequence of one or more characters following [ DNS_SUBDOMAIN "/" ] DNS_LABEL.
+// VALUE is a sequence of zero or more characters "([A-Za-z0-9_-\.])". Max length is 63 characters.
+// Delimiter is white space: (' ', '\t').
+// Example of valid syntax:
+//
+//	"env=prod,tier in (web,api),!deprecated"
+//
+// Note:
+//
+//	(1) Inclusion - " in " - denotes that the KEY exists and is equal to any of the
+//	    VALUEs in its requirement
+//	(2) Exclusion - " notin " - denotes that the KEY is not equal to any
+//	    of the VALUEs in its requirement or does not exist
+//	(3) The empty string is a valid VALUE
+//	(4) A requirement with just a KEY - as in "y" above - denotes that
+//	    the KEY exists and can be any VALUE.
+//	(5) A requirement with just !KEY requires that the KEY not exist.
+func Parse(selector string) (Selector, error) {
+	p := &parser{tokens: lex(selector)}
+
+	requirements, err := p.parse()
+	if err != nil {
+		return nil, fmt.Errorf("invalid label selector '%s': %w", selector, err)
+	}
+
+	sort.SliceStable(requirements, func(i, j int) bool {
+		return requirements[i].Key < requirements[j].Key
+	})
+
+	return requirements, nil
+}
+
+// token is the lexical unit of a selector.
+type token struct {
+	kind  tokenKind
+	value string
+}
+
+type tokenKind int
+
+const (
+	identifierToken tokenKind = iota
+	commaToken
+	openParToken
+	closedParToken
+	doesNotExistToken
+	equalsToken
+	doubleEqualsToken
+	notEqualsToken
+	greaterThanToken
+	lessThanToken
+	inToken
+	notInToken
+	endOfStringToken
+)
+
+// operatorTokens maps the operator strings to their tokens, the longer ones come first.
+var operatorTokens = []struct {
+	s    string
+	kind tokenKind
+}{
+	{"==", doubleEqualsToken},
+	{"!=", notEqualsToken},
+	{"=", equalsToken},
+	{"!", doesNotExistToken},
+	{">", greaterThanToken},
+	{"<", lessThanToken},
+	{",", commaToken},
+	{"(", openParToken},
+	{")", closedParToken},
+}
+
+// isIdentifierChar returns true if c can be part of an identifier.
+func isIdentifierChar(c byte) bool {
+	return !isWhitespace(c) && !strings.ContainsRune("=!<>,()", rune(c))
+}
+
+func isWhitespace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\n'
+}
+
+// lex splits the selector into tokens, the last one is always endOfStringToken.
+func lex(s string) []token {
+	var tokens []token
+
+	for i := 0; i < len(s); {
+		if isWhitespace(s[i]) {
+			i++
+
+			continue
+		}
+
+		if !isIdentifierChar(s[i]) {
+			for _, op := range operatorTokens {
+				if strings.HasPrefix(s[i:], op.s) {
+					tokens = append(tokens, token{kind: op.kind, value: op.s})
+					i += len(op.s)
+
+					break
+				}
+			}
+
+			continue
+		}
+
+		start := i
+		for i < len(s) && isIdentifierChar(s[i]) {
+			i++
+		}
+
+		word := s[start:i]
+
+		switch word {
+		case string(selection.In):
+			tokens = append(tokens, token{kind: inToken, value: word})
+		case string(selection.NotIn):
+			tokens = append(tokens, token{kind: notInToken, value: word})
+		default:
+			tokens = append(tokens, token{kind: identifierToken, value: word})
+		}
+	}
+
+	return append(tokens, token{kind: endOfStringToken})
+}
+
+// parser builds the requirements from the tokens of a selector.
+type parser struct {
+	tokens   []token
+	position int
+}
+
+// next returns the current token and moves forward.
+func (p *parser) next() token {
+	t := p.tokens[p.position]
+	if t.kind != endOfStringToken {
+		p.position++
+	}
+
+	return t
+}
+
+// peek returns the current token without moving forward.
+func (p *parser) peek() token {
+	return p.tokens[p.position]
+}
+
+func (p *parser) parse() (internalSelector, error) {
+	var requirements internalSelector
+
+	if p.peek().kind == endOfStringToken {
+		return requirements, nil
+	}
+
+	for {
+		r, err := p.parseRequirement()
+		if err != nil {
+			return nil, err
+		}
+
+		requirements = append(requirements, *r)
+
+		switch t := p.next(); t.kind {
+		case endOfStringToken:
+			return requirements, nil
+		case commaToken:
+			if p.peek().kind == endOfStringToken {
+				return nil, fmt.Errorf("found ',', expected: identifier after ','")
+			}
+		default:
+			return nil, fmt.Errorf("found '%s', expected: ',' or end of string", t.value)
+		}
+	}
+}
+
+func (p *parser) parseRequirement() (*Requirement, error) {
+	t := p.next()
+
+	if t.kind == doesNotExistToken {
+		key := p.next()
+		if key.kind != identifierToken {
+			return nil, fmt.Errorf("found '%s', expected: identifier after '!'", key.value)
+		}
+
+		return NewRequirement(key.value, selection.DoesNotExist, nil)
+	}
+
+	if t.kind != identifierToken {
+		return nil, fmt.Errorf("found '%s', expected: !, identifier, or end of string", t.value)
+	}
+
+	key := t.value
+
+	var op selection.Operator
+
+	switch p.peek().kind {
+	case endOfStringToken, commaToken:
+		return NewRequirement(key, selection.Exists, nil)
+	case equalsToken:
+		op = selection.Equals
+	case doubleEqualsToken:
+		op = selection.DoubleEquals
+	case notEqualsToken:
+		op = selection.NotEquals
+	case greaterThanToken:
+		op = selection.GreaterThan
+	case lessThanToken:
+		op = selection.LessThan
+	case inToken:
+		op = selection.In
+	case notInToken:
+		op = selection.NotIn
+	default:
+		return nil, fmt.Errorf("found '%s', expected: %s", p.peek().value, "=, !=, ==, in, notin, >, <")
+	}
+
+	p.next()
+
+	if op == selection.In || op == selection.NotIn {
+		values, err := p.parseValues()
+		if err != nil {
+			return nil, err
+		}
+
+		return NewRequirement(key, op, values)
+	}
+
+	// An exact match may compare with an empty value, e.g. `env=`.
+	var value string
+	if p.peek().kind == identifierToken {
+		value = p.next().value
+	}
+
+	return NewRequirement(key, op, []string{value})
+}
+
+// parseValues parses a value set, e.g. `(web,api)`.
+func (p *parser) parseValues() ([]string, error) {
+	if t := p.next(); t.kind != openParToken {
+		return nil, fmt.Errorf("found '%s', expected: '('", t.value)
+	}
+
+	if p.peek().kind == closedParToken {
+		return nil, fmt.Errorf("for 'in', 'notin' operators, values set can't be empty")
+	}
+
+	var values []string
+
+	for {
+		// The empty string is a valid value, e.g. `(,web)`.
+		value := ""
+		if p.peek().kind == identifierToken {
+			value = p.next().value
+		}
+
+		values = append(values, value)
+
+		switch t := p.next(); t.kind {
+		case closedParToken:
+			return values, nil
+		case commaToken:
+		default:
+			return nil, fmt.Errorf("found '%s', expected: ',' or ')'", t.value)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package labels
+
+import (
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	testcases := map[string]string{
+		// The requirements are sorted by key.
+		"":                                       "",
+		"x":                                      "x",
+		"!x":                                     "!x",
+		"x=a":                                    "x=a",
+		"x==a":                                   "x==a",
+		"x!=a":                                   "x!=a",
+		"x=":                                     "x=",
+		"x>1,y<-2":                               "x>1,y<-2",
+		"x in (b,a)":                             "x in (a,b)",
+		"x notin(a)":                             "x notin (a)",
+		"x in (,a)":                              "x in (,a)",
+		"env=prod,tier in (web,api),!deprecated": "!deprecated,env=prod,tier in (api,web)",
+		"example.com/tier = web , x":             "example.com/tier=web,x",
+	}
+
+	for selector, expected := range testcases {
+		s, err := Parse(selector)
+		if err != nil {
+			t.Errorf("Parse(%q) returned error: %v", selector, err)
+
+			continue
+		}
+
+		if got := s.String(); got != expected {
+			t.Errorf("Parse(%q).String() = %q, want %q", selector, got, expected)
+		}
+	}
+}
+
+func TestParseError(t *testing.T) {
+	testcases := []string{
+		"x=a,",
+		",x",
+		"x in",
+		"x in ()",
+		"x in (a",
+		"x in a",
+		"x=a b",
+		"x=a=b",
+		"!",
+		"!x=a",
+		"x>a",
+		"-x=a",
+		"x=a/b",
+		"x=" + string(make([]byte, 64)),
+	}
+
+	for _, selector := range testcases {
+		if _, err := Parse(selector); err == nil {
+			t.Errorf("Parse(%q) expected error", selector)
+		}
+	}
+}
+
+func TestSelectorMatches(t *testing.T) {
+	ls := Set{"env": "prod", "tier": "web", "replicas": "3"}
+
+	testcases := map[string]bool{
+		"":                                       true,
+		"env":                                    true,
+		"!env":                                   false,
+		"!deprecated":                            true,
+		"env=prod":                               true,
+		"env==dev":                               false,
+		"env!=dev":                               true,
+		"deprecated!=true":                       true,
+		"tier in (web,api)":                      true,
+		"tier notin (web,api)":                   false,
+		"deprecated notin (true)":                true,
+		"deprecated in (true)":                   false,
+		"replicas>2":                             true,
+		"replicas<3":                             false,
+		"env>1":                                  false,
+		"env=prod,tier in (web,api),!deprecated": true,
+		"env=prod,tier in (db)":                  false,
+	}
+
+	for selector, expected := range testcases {
+		s, err := Parse(selector)
+		if err != nil {
+			t.Errorf("Parse(%q) returned error: %v", selector, err)
+
+			continue
+		}
+
+		if got := s.Matches(ls); got != expected {
+			t.Errorf("%q.Matches(%v) = %v, want %v", selector, ls, got, expected)
+		}
+	}
+}
+
+func TestSelectorFromSet(t *testing.T) {
+	s := SelectorFromSet(Set{"b": "2", "a": "1"})
+
+	if got, expected := s.String(), "a=1,b=2"; got != expected {
+		t.Errorf("SelectorFromSet().String() = %q, want %q", got, expected)
+	}
+
+	if !s.Matches(Set{"a": "1", "b": "2", "c": "3"}) {
+		t.Errorf("SelectorFromSet() should match a superset")
+	}
+
+	if s.Matches(Set{"a": "1"}) {
+		t.Errorf("SelectorFromSet() should not match a subset")
+	}
+
+	if !Everything().Empty() || !Everything().Matches(Set{}) {
+		t.Errorf("Everything() should be empty and match everything")
+	}
+}
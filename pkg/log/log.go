@@ -46,10 +46,29 @@ func Init(opts *Options) {
 //
 func SetHooks(hooks ...Hook) {
 	zapLogger := std.zapLogger.WithOptions(zap.Hooks(hooks...))
-	std = newLogger(zapLogger, std.options)
+	logger := newLogger(zapLogger, std.options)
+	logger.level = std.level
+	std = logger
 	resetDefaultLogger()
 }
 
+// SetLevel changes the level of the default logger at runtime, e.g. info, debug. The loggers
+// derived from it, such as the context loggers, share the level.
+func SetLevel(text string) error {
+	var level Level
+	if err := level.UnmarshalText([]byte(text)); err != nil {
+		return err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	std.level.SetLevel(level)
+	std.options.Level = text
+
+	return nil
+}
+
 // New logger instance with given options.
 func New(opts *Options) *Logger {
 	if opts == nil {
@@ -125,8 +144,10 @@ func New(opts *Options) *Logger {
 		EncodeCaller:   zapcore.ShortCallerEncoder,
 	}
 
+	atomicLevel := zap.NewAtomicLevelAt(level)
+
 	loggerConfig := &zap.Config{
-		Level:             zap.NewAtomicLevelAt(level),
+		Level:             atomicLevel,
 		Development:       development,
 		DisableCaller:     opts.DisableCaller,
 		DisableStacktrace: opts.DisableStacktrace,
@@ -144,13 +165,16 @@ func New(opts *Options) *Logger {
 	}
 
 	l := zap.New(
-		zapcore.NewCore(encoder, sink, zap.NewAtomicLevelAt(level)),
+		zapcore.NewCore(encoder, sink, atomicLevel),
 		buildOptions(loggerConfig, errSink)...,
 	)
 
 	l = l.WithOptions(zap.AddStacktrace(zapcore.PanicLevel), zap.AddCallerSkip(1))
 
-	return newLogger(l, opts)
+	logger := newLogger(l, opts)
+	logger.level = atomicLevel
+
+	return logger
 }
 
 // NewStdInfoLogger returns *log.Logger(Go standard library 'log') which writes to std.zapLogger at info level.
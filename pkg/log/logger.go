@@ -3,7 +3,6 @@ package log
 import (
 	"context"
 
-	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
 
@@ -15,6 +14,8 @@ const logContextKey ctxKey = iota
 type Logger struct {
 	zapLogger *zap.Logger
 	options   *Options
+	// level is the level of the logger created by New, the derived loggers leave it unset.
+	level zap.AtomicLevel
 }
 
 // Create a new logger instance.
@@ -94,12 +95,8 @@ func (l *Logger) GetOptions() *Options {
 //}
 //
 func (l *Logger) C(ctx context.Context) *Logger {
-	ctxLogger, ok := ctx.(*gin.Context).Get(ContextLoggerName)
-	if !ok {
-		return l
-	}
-
-	cl, ok := ctxLogger.(*Logger)
+	// gin.Context returns the values set by c.Set.
+	cl, ok := ctx.Value(ContextLoggerName).(*Logger)
 	if !ok {
 		return l
 	}
@@ -0,0 +1,15 @@
+package v1
+
+// DryRunAll means to complete all processing stages, but don't persist changes to storage.
+const DryRunAll = "All"
+
+// IsDryRun returns true if the modifications should not be persisted.
+func IsDryRun(dryRun []string) bool {
+	for _, v := range dryRun {
+		if v == DryRunAll {
+			return true
+		}
+	}
+
+	return false
+}
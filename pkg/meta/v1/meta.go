@@ -20,6 +20,8 @@ type Object interface {
 	SetID(id uint64)
 	GetName() string
 	SetName(name string)
+	GetLabels() map[string]string
+	SetLabels(labels map[string]string)
 	GetCreatedAt() time.Time
 	SetCreatedAt(createdAt time.Time)
 	GetUpdatedAt() time.Time
@@ -32,6 +34,8 @@ type Object interface {
 type ListInterface interface {
 	GetTotalCount() int64
 	SetTotalCount(count int64)
+	GetContinue() string
+	SetContinue(c string)
 }
 
 // Type exposes the type and APIVersion of versioned or internal API objects.
@@ -46,6 +50,8 @@ var _ ListInterface = &ListMeta{}
 
 func (meta *ListMeta) GetTotalCount() int64      { return meta.TotalCount }
 func (meta *ListMeta) SetTotalCount(count int64) { meta.TotalCount = count }
+func (meta *ListMeta) GetContinue() string       { return meta.Continue }
+func (meta *ListMeta) SetContinue(c string)      { meta.Continue = c }
 
 var _ Type = &TypeMeta{}
 
@@ -72,11 +78,13 @@ func (obj *ObjectMeta) GetObjectMeta() Object { return obj }
 
 var _ Object = &ObjectMeta{}
 
-func (meta *ObjectMeta) GetID() uint64                    { return meta.ID }
-func (meta *ObjectMeta) SetID(id uint64)                  { meta.ID = id }
-func (meta *ObjectMeta) GetName() string                  { return meta.Name }
-func (meta *ObjectMeta) SetName(name string)              { meta.Name = name }
-func (meta *ObjectMeta) GetCreatedAt() time.Time          { return meta.CreatedAt }
-func (meta *ObjectMeta) SetCreatedAt(createdAt time.Time) { meta.CreatedAt = createdAt }
-func (meta *ObjectMeta) GetUpdatedAt() time.Time          { return meta.UpdatedAt }
-func (meta *ObjectMeta) SetUpdatedAt(updatedAt time.Time) { meta.UpdatedAt = updatedAt }
+func (meta *ObjectMeta) GetID() uint64                      { return meta.ID }
+func (meta *ObjectMeta) SetID(id uint64)                    { meta.ID = id }
+func (meta *ObjectMeta) GetName() string                    { return meta.Name }
+func (meta *ObjectMeta) SetName(name string)                { meta.Name = name }
+func (meta *ObjectMeta) GetLabels() map[string]string       { return meta.Labels }
+func (meta *ObjectMeta) SetLabels(labels map[string]string) { meta.Labels = labels }
+func (meta *ObjectMeta) GetCreatedAt() time.Time            { return meta.CreatedAt }
+func (meta *ObjectMeta) SetCreatedAt(createdAt time.Time)   { meta.CreatedAt = createdAt }
+func (meta *ObjectMeta) GetUpdatedAt() time.Time            { return meta.UpdatedAt }
+func (meta *ObjectMeta) SetUpdatedAt(updatedAt time.Time)   { meta.UpdatedAt = updatedAt }
@@ -28,7 +28,13 @@ type TypeMeta struct {
 // ListMeta describes metadata that synthetic resources must have, including lists and
 // various status objects. A resource may have only one of {ObjectMeta, ListMeta}.
 type ListMeta struct {
+	// TotalCount is the number of the objects matching the selectors, regardless of the pagination.
+	// It is not populated if the client sets `total_count=false`.
 	TotalCount int64 `json:"total_count,omitempty"`
+
+	// Continue is set if there are more objects than the returned ones. The value is opaque,
+	// clients send it back as the `continue` option to get the next page.
+	Continue string `json:"continue,omitempty"`
 }
 
 // ObjectMetaBase is the metadata that all objects must have.
@@ -76,6 +82,14 @@ type ObjectMeta struct {
 	// use prefixed to distinguish resource types, easy to remember, Url-friendly.
 	InstanceID string `json:"instance_id,omitempty" gorm:"unique;column:instance_id;type:varchar(32);not null"`
 
+	// ResourceVersion represents the internal version of this object, it is increased on every update.
+	// Clients can send it back, or send it as the `If-Match` header, to make sure the object
+	// has not been modified since it was read.
+	//
+	// Populated by the system.
+	// Read-only.
+	ResourceVersion uint64 `json:"resource_version,omitempty" gorm:"column:resource_version;not null;default:1"`
+
 	// Name defines the space within each name must be unique.
 	// Not all objects are required to be scoped to a username - the value of this field for
 	// those objects will be empty.
@@ -92,6 +106,14 @@ type ObjectMeta struct {
 	// Cannot be updated.
 	Name string `json:"name,omitempty" gorm:"column:name;type:varchar(64);not null" validate:"name"`
 
+	// Labels are key value pairs which are used to organize and select objects, e.g.
+	// `label_selector=env=prod,tier in (web,api)` in list calls.
+	// The keys must be qualified names and the values must be valid label values.
+	Labels map[string]string `json:"labels,omitempty" gorm:"-" validate:"omitempty"`
+
+	// LabelsShadow is the shadow of Labels. DO NOT modify directly.
+	LabelsShadow string `json:"-" gorm:"column:labels_shadow" validate:"omitempty"`
+
 	// Extend store the fields that need to be added, but do not want to add a new table column, will not be stored in db.
 	Extend Extend `json:"extend,omitempty" gorm:"-" validate:"omitempty"`
 
@@ -110,14 +132,32 @@ type ListOptions struct {
 	// e.g.: field_selector=name=david
 	FieldSelector string `json:"field_selector,omitempty" form:"field_selector"`
 
-	// TimeoutSeconds specifies the seconds of ClientIP type session sticky time.
-	TimeoutSeconds *int64 `json:"timeout_seconds,omitempty"`
+	// TimeoutSeconds bounds the duration of the list call, regardless of any activity or inactivity.
+	TimeoutSeconds *int64 `json:"timeout_seconds,omitempty" form:"timeout_seconds"`
 
 	// Offset specify the number of records to skip before starting to return the records.
 	Offset *int64 `json:"offset,omitempty" form:"offset"`
 
 	// Limit specify the number of records to be retrieved.
 	Limit *int64 `json:"limit,omitempty" form:"limit"`
+
+	// SortBy is the comma separated fields to sort by, e.g. `sort_by=created_at,-name`,
+	// `-` means descending order. Defaults to the newest first.
+	SortBy string `json:"sort_by,omitempty" form:"sort_by"`
+
+	// Fields is the comma separated fields to return, e.g. `fields=id,name,email`,
+	// the other fields are left empty. Defaults to all the fields.
+	Fields string `json:"fields,omitempty" form:"fields"`
+
+	// Continue is the continue token returned by the previous list call, the list starts after
+	// the last returned object of that call. It is cheaper than a large Offset and it is stable
+	// when objects are created or deleted. It can not be used with Offset, the other options
+	// should be the same as the previous call.
+	Continue string `json:"continue,omitempty" form:"continue"`
+
+	// TotalCount specify whether to count the objects matching the selectors. Defaults to true.
+	// Counting a large table is expensive, set it to false when it is not needed.
+	TotalCount *bool `json:"total_count,omitempty" form:"total_count"`
 }
 
 // ExportOptions is the query options to the standard REST get call.
@@ -136,14 +176,29 @@ type ExportOptions struct {
 // GetOptions is the standard query options to the standard REST get call.
 type GetOptions struct {
 	TypeMeta `json:",inline"`
+
+	// ForUpdate locks the object until the end of the transaction, so that the transactions
+	// reading it with ForUpdate run one after another, it is decided by the server.
+	// +optional
+	ForUpdate bool `json:"-" form:"-"`
 }
 
 // DeleteOptions may be provided when deleting an API object.
 type DeleteOptions struct {
 	TypeMeta `json:",inline"`
 
+	// Unscoped deletes the records permanently instead of soft deleting them,
+	// it is decided by the server.
 	// +optional
-	Unscoped bool `json:"unscoped"`
+	Unscoped bool `json:"unscoped" form:"-"`
+
+	// When present, indicates that modifications should not be
+	// persisted. An invalid or unrecognized dryRun directive will
+	// result in an error response and no further processing of the
+	// request. Valid values are:
+	// - All: all dry run stages will be processed
+	// +optional
+	DryRun []string `json:"dry_run,omitempty" form:"dry_run" binding:"omitempty,dive,oneof=All"`
 }
 
 // CreateOptions may be provided when creating an API object.
@@ -156,7 +211,7 @@ type CreateOptions struct {
 	// request. Valid values are:
 	// - All: all dry run stages will be processed
 	// +optional
-	DryRun []string `json:"dry_run,omitempty"`
+	DryRun []string `json:"dry_run,omitempty" form:"dry_run" binding:"omitempty,dive,oneof=All"`
 }
 
 // PatchOptions may be provided when patching an API object.
@@ -170,7 +225,7 @@ type PatchOptions struct {
 	// request. Valid values are:
 	// - All: all dry run stages will be processed
 	// +optional
-	DryRun []string `json:"dry_run,omitempty"`
+	DryRun []string `json:"dry_run,omitempty" form:"dry_run" binding:"omitempty,dive,oneof=All"`
 
 	// Force is going to "force" Apply requests. It means user will
 	// re-acquire conflicting fields owned by other people. Force
@@ -190,7 +245,7 @@ type UpdateOptions struct {
 	// request. Valid values are:
 	// - All: all dry run stages will be processed
 	// +optional
-	DryRun []string `json:"dry_run,omitempty"`
+	DryRun []string `json:"dry_run,omitempty" form:"dry_run" binding:"omitempty,dive,oneof=All"`
 }
 
 // AuthorizeOptions may be provided when authorize an API object.
@@ -231,3 +286,20 @@ func (ext Extend) Merge(extendShadow string) Extend {
 
 	return ext
 }
+
+// MarshalLabelsShadow stores Labels into LabelsShadow.
+func (meta *ObjectMeta) MarshalLabelsShadow() {
+	data, _ := json.Marshal(meta.Labels)
+	meta.LabelsShadow = string(data)
+}
+
+// UnmarshalLabelsShadow restores Labels from LabelsShadow.
+// The shadow is empty for the records created before labels are supported.
+func (meta *ObjectMeta) UnmarshalLabelsShadow() error {
+	meta.Labels = nil
+	if meta.LabelsShadow == "" {
+		return nil
+	}
+
+	return json.Unmarshal([]byte(meta.LabelsShadow), &meta.Labels)
+}
@@ -16,4 +16,7 @@ const (
 	Exists       Operator = "exists"
 	GreaterThan  Operator = "gt"
 	LessThan     Operator = "lt"
+
+	GreaterThanOrEquals Operator = "gte"
+	LessThanOrEquals    Operator = "lte"
 )
@@ -0,0 +1,91 @@
+// Package storage creates the clients of the key-value storages.
+package storage
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// RedisOptions defines options for the redis client.
+type RedisOptions struct {
+	// Addrs are the addresses of the single node, the cluster nodes or the sentinels.
+	Addrs    []string
+	Username string
+	Password string
+	// Database is not supported by redis cluster.
+	Database int
+	// MasterName is the name of the master monitored by the sentinels, it enables the sentinel mode.
+	MasterName string
+	// EnableCluster enables the cluster mode.
+	EnableCluster bool
+	PoolSize      int
+	MinIdleConns  int
+	// DialTimeout is the timeout of establishing new connections, 0 means the default 5s.
+	DialTimeout           time.Duration
+	UseSSL                bool
+	SSLInsecureSkipVerify bool
+}
+
+// NewRedisClient creates a redis client of the single node, the sentinels or the cluster,
+// and pings the server to make sure it is reachable.
+func NewRedisClient(ctx context.Context, opts *RedisOptions) (redis.UniversalClient, error) {
+	if len(opts.Addrs) == 0 {
+		return nil, fmt.Errorf("no redis address")
+	}
+
+	var tlsConfig *tls.Config
+	if opts.UseSSL {
+		// nolint: gosec
+		tlsConfig = &tls.Config{InsecureSkipVerify: opts.SSLInsecureSkipVerify}
+	}
+
+	var client redis.UniversalClient
+
+	switch {
+	case opts.EnableCluster:
+		client = redis.NewClusterClient(&redis.ClusterOptions{
+			Addrs:        opts.Addrs,
+			Username:     opts.Username,
+			Password:     opts.Password,
+			PoolSize:     opts.PoolSize,
+			MinIdleConns: opts.MinIdleConns,
+			DialTimeout:  opts.DialTimeout,
+			TLSConfig:    tlsConfig,
+		})
+	case opts.MasterName != "":
+		client = redis.NewFailoverClient(&redis.FailoverOptions{
+			MasterName:    opts.MasterName,
+			SentinelAddrs: opts.Addrs,
+			Username:      opts.Username,
+			Password:      opts.Password,
+			DB:            opts.Database,
+			PoolSize:      opts.PoolSize,
+			MinIdleConns:  opts.MinIdleConns,
+			DialTimeout:   opts.DialTimeout,
+			TLSConfig:     tlsConfig,
+		})
+	default:
+		client = redis.NewClient(&redis.Options{
+			Addr:         opts.Addrs[0],
+			Username:     opts.Username,
+			Password:     opts.Password,
+			DB:           opts.Database,
+			PoolSize:     opts.PoolSize,
+			MinIdleConns: opts.MinIdleConns,
+			DialTimeout:  opts.DialTimeout,
+			TLSConfig:    tlsConfig,
+		})
+	}
+
+	if err := client.Ping(ctx).Err(); err != nil {
+		_ = client.Close()
+
+		return nil, fmt.Errorf("ping redis %v failed: %w", opts.Addrs, err)
+	}
+
+	return client, nil
+}
@@ -95,6 +95,22 @@ func IsValidLabelValue(value string) []string {
 	return errs
 }
 
+// ValidateLabels validates that a set of labels are correctly defined,
+// the keys must be qualified names and the values must be valid label values.
+func ValidateLabels(labels map[string]string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for k, v := range labels {
+		for _, msg := range IsQualifiedName(k) {
+			allErrs = append(allErrs, field.Invalid(fldPath, k, msg))
+		}
+		for _, msg := range IsValidLabelValue(v) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Key(k), v, msg))
+		}
+	}
+
+	return allErrs
+}
+
 const dns1123LabelFmt string = "[a-z0-9]([-a-z0-9]*[a-z0-9])?"
 
 const dns1123LabelErrMsg string = "a DNS-1123 label must consist of lower case alphanumeric characters or '-', and must start and end with an alphanumeric character"
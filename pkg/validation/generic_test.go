@@ -157,6 +157,30 @@ func TestIsQualifiedName(t *testing.T) {
 	}
 }
 
+func TestValidateLabels(t *testing.T) {
+	successCases := []map[string]string{
+		nil,
+		{"env": "prod", "example.com/tier": "web"},
+		{"deprecated": ""},
+	}
+	for i := range successCases {
+		if errs := ValidateLabels(successCases[i], field.NewPath("labels")); len(errs) != 0 {
+			t.Errorf("case[%d]: %v: expected success: %v", i, successCases[i], errs)
+		}
+	}
+
+	errorCases := []map[string]string{
+		{"-env": "prod"},
+		{"env": "prod/web"},
+		{"env": strings.Repeat("a", 64)},
+	}
+	for i := range errorCases {
+		if errs := ValidateLabels(errorCases[i], field.NewPath("labels")); len(errs) == 0 {
+			t.Errorf("case[%d]: %v: expected failure", i, errorCases[i])
+		}
+	}
+}
+
 func TestIsValidIP(t *testing.T) {
 	goodValues := []string{
 		"::1",